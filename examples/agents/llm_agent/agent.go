@@ -9,7 +9,6 @@ import (
 
 	"github.com/agent-protocol/adk-golang/pkg/agents"
 	"github.com/agent-protocol/adk-golang/pkg/core"
-	"github.com/agent-protocol/adk-golang/pkg/llmconnect/ollama"
 	"github.com/agent-protocol/adk-golang/pkg/ptr"
 	"github.com/agent-protocol/adk-golang/pkg/tools"
 )
@@ -27,26 +26,10 @@ func init() {
 		modelName = "llama3.2"
 	}
 
-	// Create Ollama configuration
-	ollamaConfig := &ollama.OllamaConfig{
-		BaseURL:     "http://localhost:11434",
-		Model:       modelName,
-		Temperature: ptr.Float32(0.7),
-		Timeout:     30 * time.Second,
-		Stream:      false,
-	}
-
-	// Allow environment variable overrides
-	if baseURL := os.Getenv("OLLAMA_API_BASE"); baseURL != "" {
-		ollamaConfig.BaseURL = baseURL
-	}
-
-	// Create Ollama connection
-	ollamaConnection := ollama.NewOllamaConnection(ollamaConfig)
-
-	// Create agent configuration for Ollama
+	// Create agent configuration. The "ollama/" prefix makes the agent resolve
+	// an Ollama connection; OLLAMA_API_BASE overrides the server address.
 	agentConfig := &agents.LlmAgentConfig{
-		Model:            modelName,
+		Model:            "ollama/" + modelName,
 		Temperature:      ptr.Float32(0.3), // Lower temperature for more consistent behavior
 		MaxTokens:        ptr.Ptr(4096),
 		MaxToolCalls:     1, // Only allow 1 tool call to prevent loops
@@ -62,9 +45,6 @@ func init() {
 		agentConfig,
 	)
 
-	// Set the LLM connection
-	agent.SetLLMConnection(ollamaConnection)
-
 	// Set instruction for better tool usage
 	agent.SetInstruction(`You are a helpful search assistant. When users ask questions:
 
@@ -5,7 +5,7 @@
 //
 // The conversion functions support:
 // - Text content (bidirectional)
-// - File content (with metadata preservation)
+// - File content (inline bytes and URIs, as inline_data and file_data parts)
 // - Function calls (represented as text in A2A)
 // - Data parts (with structured metadata)
 //
@@ -22,7 +22,9 @@
 package a2a
 
 import (
+	"encoding/base64"
 	"fmt"
+	"log"
 
 	"github.com/agent-protocol/adk-golang/pkg/core"
 	"github.com/agent-protocol/adk-golang/pkg/ptr"
@@ -98,23 +100,7 @@ func ConvertA2APartToCorePart(a2aPart Part) *core.Part {
 		}
 	case "file":
 		if a2aPart.File != nil {
-			// Convert file content to core part
-			// For now, we'll represent it as text with file metadata
-			fileName := ""
-			if a2aPart.File.Name != nil {
-				fileName = *a2aPart.File.Name
-			}
-			return &core.Part{
-				Type: "file",
-				Text: ptr.Ptr(fmt.Sprintf("File: %s", fileName)),
-				Metadata: map[string]any{
-					"file_name":     a2aPart.File.Name,
-					"file_uri":      a2aPart.File.URI,
-					"file_bytes":    a2aPart.File.Bytes,
-					"file_mime":     a2aPart.File.MimeType,
-					"original_type": "file",
-				},
-			}
+			return convertA2AFileToCorePart(a2aPart.File, a2aPart.Metadata)
 		}
 	case "data":
 		if a2aPart.Data != nil {
@@ -135,6 +121,39 @@ func ConvertA2APartToCorePart(a2aPart Part) *core.Part {
 	return nil
 }
 
+// convertA2AFileToCorePart converts A2A file content to an inline_data part
+// for inline bytes or a file_data part for a URI.
+func convertA2AFileToCorePart(file *FileContent, metadata map[string]any) *core.Part {
+	var name, mimeType string
+	if file.Name != nil {
+		name = *file.Name
+	}
+	if file.MimeType != nil {
+		mimeType = *file.MimeType
+	}
+
+	switch {
+	case file.Bytes != nil:
+		data, err := base64.StdEncoding.DecodeString(*file.Bytes)
+		if err != nil {
+			log.Printf("Skipping file part %s with invalid base64 content: %v", name, err)
+			return nil
+		}
+		return &core.Part{
+			Type:       "inline_data",
+			InlineData: &core.Blob{MimeType: mimeType, Data: data, DisplayName: name},
+			Metadata:   metadata,
+		}
+	case file.URI != nil:
+		return &core.Part{
+			Type:     "file_data",
+			FileData: &core.FileData{MimeType: mimeType, FileURI: *file.URI, DisplayName: name},
+			Metadata: metadata,
+		}
+	}
+	return nil
+}
+
 // ConvertCorePartToA2APart converts a single ADK core part to an A2A part
 func ConvertCorePartToA2APart(corePart core.Part) *Part {
 	switch corePart.Type {
@@ -175,6 +194,30 @@ func ConvertCorePartToA2APart(corePart core.Part) *Part {
 				},
 			}
 		}
+	case "inline_data":
+		if corePart.InlineData != nil {
+			return &Part{
+				Type: "file",
+				File: &FileContent{
+					Name:     optionalString(corePart.InlineData.DisplayName),
+					MimeType: optionalString(corePart.InlineData.MimeType),
+					Bytes:    ptr.Ptr(base64.StdEncoding.EncodeToString(corePart.InlineData.Data)),
+				},
+				Metadata: corePart.Metadata,
+			}
+		}
+	case "file_data":
+		if corePart.FileData != nil {
+			return &Part{
+				Type: "file",
+				File: &FileContent{
+					Name:     optionalString(corePart.FileData.DisplayName),
+					MimeType: optionalString(corePart.FileData.MimeType),
+					URI:      ptr.Ptr(corePart.FileData.FileURI),
+				},
+				Metadata: corePart.Metadata,
+			}
+		}
 	case "file":
 		// Check if this was originally a file from A2A
 		if corePart.Metadata != nil {
@@ -333,3 +376,11 @@ func ConvertEventToTaskStatusUpdate(event *core.Event, invocationCtx *core.Invoc
 func ConvertEventToTaskArtifactUpdate(event *core.Event, filename string, version int) *TaskArtifactUpdateEvent {
 	panic("ConvertEventToTaskArtifactUpdate is not implemented yet")
 }
+
+// optionalString returns nil for an empty string.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
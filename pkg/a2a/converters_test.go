@@ -166,17 +166,53 @@ func TestConvertWithFileContent(t *testing.T) {
 		t.Fatal("Expected core part, got nil")
 	}
 
-	if corePart.Type != "file" {
-		t.Errorf("Expected type 'file', got '%s'", corePart.Type)
+	if corePart.Type != "file_data" {
+		t.Errorf("Expected type 'file_data', got '%s'", corePart.Type)
 	}
 
-	if corePart.Metadata == nil {
-		t.Fatal("Expected metadata, got nil")
+	if corePart.FileData == nil {
+		t.Fatal("Expected file data, got nil")
+	}
+
+	if corePart.FileData.DisplayName != "test.txt" || corePart.FileData.FileURI != "https://example.com/test.txt" ||
+		corePart.FileData.MimeType != "text/plain" {
+		t.Errorf("Unexpected file data: %+v", corePart.FileData)
+	}
+
+	// And back to A2A
+	roundTrip := ConvertCorePartToA2APart(*corePart)
+	if roundTrip == nil || roundTrip.Type != "file" || roundTrip.File.URI == nil || *roundTrip.File.URI != "https://example.com/test.txt" {
+		t.Errorf("Unexpected A2A file part: %+v", roundTrip)
+	}
+}
+
+func TestConvertWithInlineFileContent(t *testing.T) {
+	a2aPart := Part{
+		Type: "file",
+		File: &FileContent{
+			Name:     ptr.Ptr("pixel.png"),
+			MimeType: ptr.Ptr("image/png"),
+			Bytes:    ptr.Ptr("iVBORw0KGgo="),
+		},
+	}
+
+	corePart := ConvertA2APartToCorePart(a2aPart)
+	if corePart == nil || corePart.Type != "inline_data" || corePart.InlineData == nil {
+		t.Fatalf("Expected an inline_data part, got %+v", corePart)
+	}
+	if corePart.InlineData.MimeType != "image/png" || string(corePart.InlineData.Data) != "\x89PNG\r\n\x1a\n" {
+		t.Errorf("Unexpected inline data: %+v", corePart.InlineData)
+	}
+
+	roundTrip := ConvertCorePartToA2APart(*corePart)
+	if roundTrip == nil || roundTrip.File == nil || roundTrip.File.Bytes == nil || *roundTrip.File.Bytes != "iVBORw0KGgo=" ||
+		*roundTrip.File.Name != "pixel.png" {
+		t.Errorf("Unexpected A2A file part: %+v", roundTrip)
 	}
 
-	fileName, ok := corePart.Metadata["file_name"].(*string)
-	if !ok || fileName == nil || *fileName != "test.txt" {
-		t.Errorf("Expected file_name 'test.txt', got %v", fileName)
+	a2aPart.File.Bytes = ptr.Ptr("not base64!")
+	if part := ConvertA2APartToCorePart(a2aPart); part != nil {
+		t.Errorf("Expected invalid content to be skipped, got %+v", part)
 	}
 }
 
@@ -127,8 +127,9 @@ func (a *CustomAgent) FindSubAgent(name string) core.BaseAgent {
 }
 
 // Run is a synchronous wrapper around RunAsync that collects all events.
+// Unlike RunAsync, it also returns the error reported by the execute function.
 func (a *CustomAgent) Run(invocationCtx *core.InvocationContext) ([]*core.Event, error) {
-	stream, err := a.RunAsync(invocationCtx)
+	stream, errChan, err := a.start(invocationCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -145,24 +146,40 @@ func (a *CustomAgent) Run(invocationCtx *core.InvocationContext) ([]*core.Event,
 		}
 	}
 
+	if err := <-errChan; err != nil {
+		return events, err
+	}
+
 	return events, nil
 }
 
 // RunAsync executes the agent with the given context and returns an event stream.
 // This is a base implementation that should be overridden by concrete agents.
 func (a *CustomAgent) RunAsync(invocationCtx *core.InvocationContext) (core.EventStream, error) {
+	stream, _, err := a.start(invocationCtx)
+	return stream, err
+}
+
+// start launches the execute function in a goroutine. The returned error channel
+// receives exactly one value (possibly nil) once the event stream is closed.
+func (a *CustomAgent) start(invocationCtx *core.InvocationContext) (core.EventStream, <-chan error, error) {
 	// Execute before-agent callback if present
 	if a.beforeAgentCallback != nil {
 		if err := a.beforeAgentCallback(invocationCtx); err != nil {
-			return nil, fmt.Errorf("before-agent callback failed: %w", err)
+			return nil, nil, fmt.Errorf("before-agent callback failed: %w", err)
 		}
 	}
 
 	// Create a channel to stream events
 	eventChan := make(chan *core.Event, 10)
+	errChan := make(chan error, 1)
 
 	go func() {
-		defer close(eventChan)
+		var execErr error
+		defer func() {
+			close(eventChan)
+			errChan <- execErr
+		}()
 
 		errorEvent := core.NewEvent(invocationCtx.InvocationID, a.name)
 
@@ -170,10 +187,16 @@ func (a *CustomAgent) RunAsync(invocationCtx *core.InvocationContext) (core.Even
 			log.Printf("No execute function defined for agent: %s", a.name)
 			// Send an error event if no execute function is defined
 			errorEvent.ErrorMessage = ptr.Ptr("No execute function defined for this agent")
-		} else if err := a.execute(invocationCtx, eventChan); err != nil {
-			log.Printf("Conversation flow failed: %v", err)
+		} else if execErr = a.execute(invocationCtx, eventChan); execErr != nil {
+			log.Printf("Conversation flow failed: %v", execErr)
 			// Send error event
-			errorEvent.ErrorMessage = ptr.Ptr(fmt.Sprintf("Conversation flow failed: %v", err))
+			errorEvent.ErrorMessage = ptr.Ptr(fmt.Sprintf("Conversation flow failed: %v", execErr))
+		}
+
+		// Only surface the error event when something actually went wrong,
+		// otherwise it would shadow the agent's final response.
+		if errorEvent.ErrorMessage == nil {
+			return
 		}
 
 		select {
@@ -183,7 +206,7 @@ func (a *CustomAgent) RunAsync(invocationCtx *core.InvocationContext) (core.Even
 		}
 	}()
 
-	return eventChan, nil
+	return eventChan, errChan, nil
 }
 
 // Cleanup performs any necessary cleanup operations.
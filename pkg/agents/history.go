@@ -0,0 +1,413 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// HistoryStrategy selects the session events an LLMAgent sends to the
+// model, keeping long sessions within the model's context window. Strategies
+// never separate a function call from its response.
+type HistoryStrategy interface {
+	// SelectHistory returns the events to send, oldest first.
+	SelectHistory(events []*core.Event) []*core.Event
+}
+
+// HistoryCompactor is a HistoryStrategy that compacts older events before
+// each model call. The agent records compactions as events in the session,
+// so that later requests reuse them instead of compacting again.
+type HistoryCompactor interface {
+	HistoryStrategy
+
+	// Compact returns a compaction of older events, or nil when nothing
+	// needs compacting yet, together with the usage of producing it. conn
+	// is the agent's connection.
+	Compact(ctx context.Context, conn core.LLMConnection, events []*core.Event) (*core.EventCompaction, *core.Usage, error)
+}
+
+// TokenEstimator estimates the number of tokens of a content.
+type TokenEstimator func(content *core.Content) int
+
+const (
+	// defaultHistoryTurns is the number of turns kept by strategies that
+	// are not told otherwise.
+	defaultHistoryTurns = 10
+
+	// blobTokens is the estimate for an inline blob such as an image.
+	blobTokens = 258
+
+	// partOverheadTokens accounts for the framing of each part.
+	partOverheadTokens = 4
+)
+
+// EstimateTokens is a TokenEstimator assuming about four characters per
+// token, which is close enough for English text and JSON to budget context.
+func EstimateTokens(content *core.Content) int {
+	if content == nil {
+		return 0
+	}
+	chars, tokens := 0, 0
+	for _, part := range content.Parts {
+		tokens += partOverheadTokens
+		switch {
+		case part.Text != nil:
+			chars += len(*part.Text)
+		case part.Thought != nil:
+			chars += len(*part.Thought)
+		case part.FunctionCall != nil:
+			args, _ := json.Marshal(part.FunctionCall.Args)
+			chars += len(part.FunctionCall.Name) + len(args)
+		case part.FunctionResponse != nil:
+			response, _ := json.Marshal(part.FunctionResponse.Response)
+			chars += len(part.FunctionResponse.Name) + len(response)
+		case part.InlineData != nil:
+			tokens += blobTokens
+		case part.FileData != nil:
+			chars += len(part.FileData.FileURI)
+		}
+	}
+	return tokens + (chars+3)/4
+}
+
+// SlidingWindowHistory keeps the last MaxTurns turns of the session. A turn
+// starts with a user message and includes the tool calls that answer it.
+type SlidingWindowHistory struct {
+	// MaxTurns is the number of turns kept (default 10).
+	MaxTurns int
+}
+
+// SelectHistory implements HistoryStrategy.
+func (h *SlidingWindowHistory) SelectHistory(events []*core.Event) []*core.Event {
+	events = applyCompactions(events)
+	maxTurns := h.MaxTurns
+	if maxTurns <= 0 {
+		maxTurns = defaultHistoryTurns
+	}
+	starts := turnStarts(events)
+	if len(starts) <= maxTurns {
+		return events
+	}
+	return events[starts[len(starts)-maxTurns]:]
+}
+
+// TokenBudgetHistory keeps the most recent events that fit in MaxTokens
+// according to Estimator. The most recent exchange is always kept, even when
+// it exceeds the budget on its own.
+type TokenBudgetHistory struct {
+	// MaxTokens is the token budget of the history (0 = unlimited).
+	MaxTokens int
+
+	// Estimator estimates the tokens of a content (nil = EstimateTokens).
+	Estimator TokenEstimator
+}
+
+// SelectHistory implements HistoryStrategy.
+func (h *TokenBudgetHistory) SelectHistory(events []*core.Event) []*core.Event {
+	events = applyCompactions(events)
+	if h.MaxTokens <= 0 {
+		return events
+	}
+	estimate := h.Estimator
+	if estimate == nil {
+		estimate = EstimateTokens
+	}
+
+	units := historyUnits(events)
+	first, total := len(units), 0
+	for i := len(units) - 1; i >= 0; i-- {
+		tokens := 0
+		for _, event := range units[i] {
+			tokens += estimate(eventContent(event))
+		}
+		if first < len(units) && total+tokens > h.MaxTokens {
+			break
+		}
+		total += tokens
+		first = i
+	}
+
+	// Start at a user message where possible rather than in the middle of
+	// a turn, which some providers reject
+	for first < len(units)-1 && !isTurnStart(units[first][0]) && units[first][0].Actions.Compaction == nil {
+		first++
+	}
+
+	var selected []*core.Event
+	for _, unit := range units[first:] {
+		selected = append(selected, unit...)
+	}
+	return selected
+}
+
+// DefaultSummaryInstruction prompts the model for a summary of older events.
+const DefaultSummaryInstruction = "Summarize the conversation below for an assistant that will continue it. " +
+	"Keep the user's goals and preferences, decisions made, facts learned from tools and open questions. Be concise."
+
+// SummarizingHistory replaces older turns with a summary written by a model.
+// Once CompactTurns turns have accumulated before the last KeepTurns, they
+// are summarized together with the previous summary into a compaction event,
+// so the session carries a single rolling summary followed by recent turns.
+type SummarizingHistory struct {
+	// KeepTurns is the number of recent turns sent verbatim (default 4).
+	KeepTurns int
+
+	// CompactTurns is the number of older turns that accumulate before they
+	// are summarized (default KeepTurns).
+	CompactTurns int
+
+	// Connection writes the summaries (nil = the agent's connection).
+	Connection core.LLMConnection
+
+	// Instruction prompts the summary (empty = DefaultSummaryInstruction).
+	Instruction string
+}
+
+// SelectHistory implements HistoryStrategy.
+func (h *SummarizingHistory) SelectHistory(events []*core.Event) []*core.Event {
+	return applyCompactions(events)
+}
+
+// Compact implements HistoryCompactor.
+func (h *SummarizingHistory) Compact(ctx context.Context, conn core.LLMConnection, events []*core.Event) (*core.EventCompaction, *core.Usage, error) {
+	keepTurns := h.KeepTurns
+	if keepTurns <= 0 {
+		keepTurns = 4
+	}
+	compactTurns := h.CompactTurns
+	if compactTurns <= 0 {
+		compactTurns = keepTurns
+	}
+
+	events = applyCompactions(events)
+	starts := turnStarts(events)
+	if len(starts)-keepTurns < compactTurns {
+		return nil, nil, nil
+	}
+	older := events[:starts[len(starts)-keepTurns]]
+
+	if h.Connection != nil {
+		conn = h.Connection
+	}
+	instruction := h.Instruction
+	if instruction == "" {
+		instruction = DefaultSummaryInstruction
+	}
+	request := &core.LLMRequest{
+		Contents: []core.Content{{
+			Role:  "user",
+			Parts: []core.Part{{Type: "text", Text: ptr.Ptr(instruction + "\n\n" + transcript(older))}},
+		}},
+	}
+
+	started := time.Now()
+	response, err := conn.GenerateContent(ctx, request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to summarize history: %w", err)
+	}
+	summary := strings.TrimSpace(contentText(response.Content))
+	if summary == "" {
+		return nil, nil, fmt.Errorf("failed to summarize history: the model returned no summary")
+	}
+
+	compaction := &core.EventCompaction{
+		StartTimestamp: older[0].Timestamp,
+		EndTimestamp:   older[len(older)-1].Timestamp,
+		CompactedContent: &core.Content{
+			Role:  "user",
+			Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Summary of the earlier conversation:\n" + summary)}},
+		},
+	}
+	if previous := older[0].Actions.Compaction; previous != nil {
+		compaction.StartTimestamp = previous.StartTimestamp
+	}
+	return compaction, responseUsage(response, time.Since(started)), nil
+}
+
+// compactHistory runs the agent's history compactor, if any, and records a
+// new compaction as an event of the agent. A failed compaction only means
+// the history stays longer, so it is logged rather than returned.
+func (a *LLMAgent) compactHistory(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event) error {
+	compactor, ok := a.config.History.(HistoryCompactor)
+	if !ok {
+		return nil
+	}
+	conn, err := a.LLMConnection()
+	if err != nil {
+		return err
+	}
+
+	compaction, usage, err := compactor.Compact(invocationCtx, conn, invocationCtx.Session.Events)
+	if err != nil {
+		log.Printf("History compaction failed: %v", err)
+		return nil
+	}
+	if compaction == nil {
+		return nil
+	}
+
+	event := core.NewEvent(invocationCtx.InvocationID, a.name)
+	event.Actions.Compaction = compaction
+	event.Usage = usage
+	select {
+	case eventChan <- event:
+	case <-invocationCtx.Done():
+		return invocationCtx.Err()
+	}
+	invocationCtx.Session.AddEvent(event)
+	log.Printf("Compacted history up to %s", compaction.EndTimestamp.Format(time.RFC3339Nano))
+	return nil
+}
+
+// applyCompactions replaces the events covered by compactions with the
+// compaction events. A compaction covered by a later one, such as a summary
+// folded into a rolling summary, is dropped.
+func applyCompactions(events []*core.Event) []*core.Event {
+	var compactions []*core.Event
+	for _, event := range events {
+		if event.Actions.Compaction != nil {
+			compactions = append(compactions, event)
+		}
+	}
+	if len(compactions) == 0 {
+		return events
+	}
+
+	// Of compactions covering the same range, the last one wins
+	var active []*core.EventCompaction
+	for i, event := range compactions {
+		compaction := event.Actions.Compaction
+		superseded := false
+		for j, other := range compactions {
+			outer := other.Actions.Compaction
+			if j != i && compactionContains(outer, compaction) && (j > i || !compactionContains(compaction, outer)) {
+				superseded = true
+				break
+			}
+		}
+		if !superseded {
+			active = append(active, compaction)
+		}
+	}
+
+	selected := make([]*core.Event, 0, len(events))
+	emitted := make(map[*core.EventCompaction]bool)
+	for _, event := range events {
+		if event.Actions.Compaction != nil {
+			continue
+		}
+		var covering *core.EventCompaction
+		for _, compaction := range active {
+			if compaction.Covers(event) {
+				covering = compaction
+				break
+			}
+		}
+		if covering == nil {
+			selected = append(selected, event)
+			continue
+		}
+		if !emitted[covering] {
+			emitted[covering] = true
+			selected = append(selected, compactionEvent(compactions, covering))
+		}
+	}
+	return selected
+}
+
+// compactionContains reports whether outer covers the range of inner.
+func compactionContains(outer, inner *core.EventCompaction) bool {
+	return !inner.StartTimestamp.Before(outer.StartTimestamp) && !inner.EndTimestamp.After(outer.EndTimestamp)
+}
+
+// compactionEvent returns the event carrying a compaction.
+func compactionEvent(events []*core.Event, compaction *core.EventCompaction) *core.Event {
+	for _, event := range events {
+		if event.Actions.Compaction == compaction {
+			return event
+		}
+	}
+	return nil
+}
+
+// eventContent returns the content an event contributes to the history: the
+// compacted content of a compaction event, the event content otherwise.
+func eventContent(event *core.Event) *core.Content {
+	if event.Actions.Compaction != nil {
+		return event.Actions.Compaction.CompactedContent
+	}
+	return event.Content
+}
+
+// isTurnStart reports whether an event is a user message starting a turn,
+// as opposed to a function response sent on the user's behalf.
+func isTurnStart(event *core.Event) bool {
+	if event.Author != "user" || event.Content == nil {
+		return false
+	}
+	for _, part := range event.Content.Parts {
+		if part.FunctionResponse != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// turnStarts returns the indexes of the events starting a turn.
+func turnStarts(events []*core.Event) []int {
+	var starts []int
+	for i, event := range events {
+		if isTurnStart(event) {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// historyUnits groups events that must stay together: an event carrying
+// function responses belongs with the events before it, back to the one
+// that made the calls.
+func historyUnits(events []*core.Event) [][]*core.Event {
+	var units [][]*core.Event
+	for _, event := range events {
+		if len(units) > 0 && event.Actions.Compaction == nil && len(event.GetFunctionResponses()) > 0 {
+			units[len(units)-1] = append(units[len(units)-1], event)
+			continue
+		}
+		units = append(units, []*core.Event{event})
+	}
+	return units
+}
+
+// transcript renders events as plain text for the summarizing model.
+func transcript(events []*core.Event) string {
+	var b strings.Builder
+	for _, event := range events {
+		if event.Actions.Compaction != nil {
+			fmt.Fprintf(&b, "%s\n\n", contentText(event.Actions.Compaction.CompactedContent))
+			continue
+		}
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			switch {
+			case part.Text != nil:
+				fmt.Fprintf(&b, "%s: %s\n", event.Author, *part.Text)
+			case part.FunctionCall != nil:
+				args, _ := json.Marshal(part.FunctionCall.Args)
+				fmt.Fprintf(&b, "%s called %s(%s)\n", event.Author, part.FunctionCall.Name, args)
+			case part.FunctionResponse != nil:
+				response, _ := json.Marshal(part.FunctionResponse.Response)
+				fmt.Fprintf(&b, "%s returned %s\n", part.FunctionResponse.Name, response)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,232 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect/fake"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// historyEvents builds session events one second apart, an hour ago.
+type historyEvents struct {
+	events []*core.Event
+	start  time.Time
+}
+
+func newHistoryEvents() *historyEvents {
+	return &historyEvents{start: time.Now().Add(-time.Hour)}
+}
+
+func (h *historyEvents) add(author string, parts ...core.Part) *core.Event {
+	role := "model"
+	if author == "user" {
+		role = "user"
+	}
+	event := core.NewEvent("test-invocation", author)
+	event.Timestamp = h.start.Add(time.Duration(len(h.events)) * time.Second)
+	event.Content = &core.Content{Role: role, Parts: parts}
+	h.events = append(h.events, event)
+	return event
+}
+
+func (h *historyEvents) turn(question, answer string) {
+	h.add("user", core.Part{Type: "text", Text: ptr.Ptr(question)})
+	h.add("agent", core.Part{Type: "text", Text: ptr.Ptr(answer)})
+}
+
+func (h *historyEvents) toolTurn(question, tool, answer string) {
+	h.add("user", core.Part{Type: "text", Text: ptr.Ptr(question)})
+	h.add("agent", core.Part{Type: "function_call", FunctionCall: &core.FunctionCall{ID: tool, Name: tool}})
+	h.add("agent", core.Part{Type: "function_response", FunctionResponse: &core.FunctionResponse{ID: tool, Name: tool,
+		Response: map[string]any{"result": "found"}}})
+	h.add("agent", core.Part{Type: "text", Text: ptr.Ptr(answer)})
+}
+
+func historyTexts(events []*core.Event) []string {
+	var texts []string
+	for _, event := range events {
+		content := eventContent(event)
+		for _, part := range content.Parts {
+			switch {
+			case part.Text != nil:
+				texts = append(texts, *part.Text)
+			case part.FunctionCall != nil:
+				texts = append(texts, "call:"+part.FunctionCall.Name)
+			case part.FunctionResponse != nil:
+				texts = append(texts, "response:"+part.FunctionResponse.Name)
+			}
+		}
+	}
+	return texts
+}
+
+func TestSlidingWindowHistory(t *testing.T) {
+	history := newHistoryEvents()
+	history.turn("q1", "a1")
+	history.toolTurn("q2", "lookup", "a2")
+	history.turn("q3", "a3")
+
+	selected := (&SlidingWindowHistory{MaxTurns: 2}).SelectHistory(history.events)
+	got := strings.Join(historyTexts(selected), ",")
+	if want := "q2,call:lookup,response:lookup,a2,q3,a3"; got != want {
+		t.Errorf("Unexpected history: got %s, want %s", got, want)
+	}
+
+	selected = (&SlidingWindowHistory{MaxTurns: 5}).SelectHistory(history.events)
+	if len(selected) != len(history.events) {
+		t.Errorf("Expected all %d events, got %d", len(history.events), len(selected))
+	}
+}
+
+func TestTokenBudgetHistory(t *testing.T) {
+	history := newHistoryEvents()
+	history.turn("q1", "a1")
+	history.toolTurn("q2", "lookup", "a2")
+	history.turn("q3", "a3")
+
+	// A token per part, ten per function response
+	estimate := func(content *core.Content) int {
+		tokens := 0
+		for _, part := range content.Parts {
+			tokens++
+			if part.FunctionResponse != nil {
+				tokens += 9
+			}
+		}
+		return tokens
+	}
+
+	tests := []struct {
+		name      string
+		maxTokens int
+		want      string
+	}{
+		{"unlimited", 0, "q1,a1,q2,call:lookup,response:lookup,a2,q3,a3"},
+		{"recent turn", 3, "q3,a3"},
+		// The function response would fit without its call, and the
+		// history then starts at the next user message
+		{"tool pair kept together", 13, "q3,a3"},
+		{"whole tool turn", 15, "q2,call:lookup,response:lookup,a2,q3,a3"},
+		{"last exchange over budget", 1, "a3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := &TokenBudgetHistory{MaxTokens: tt.maxTokens, Estimator: estimate}
+			selected := strategy.SelectHistory(history.events)
+			if got := strings.Join(historyTexts(selected), ","); got != tt.want {
+				t.Errorf("Unexpected history: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	content := &core.Content{Role: "user", Parts: []core.Part{
+		{Type: "text", Text: ptr.Ptr(strings.Repeat("x", 400))},
+		core.NewInlineDataPart("image/png", []byte("image")),
+	}}
+	if got, want := EstimateTokens(content), 2*partOverheadTokens+100+blobTokens; got != want {
+		t.Errorf("Unexpected estimate: got %d, want %d", got, want)
+	}
+	if got := EstimateTokens(nil); got != 0 {
+		t.Errorf("Expected no tokens for nil content, got %d", got)
+	}
+}
+
+func TestApplyCompactions(t *testing.T) {
+	history := newHistoryEvents()
+	history.turn("q1", "a1")
+	history.turn("q2", "a2")
+	history.turn("q3", "a3")
+	events := history.events
+
+	compaction := func(text string, start, end int) *core.Event {
+		event := core.NewEvent("test-invocation", "agent")
+		event.Actions.Compaction = &core.EventCompaction{
+			StartTimestamp:   events[start].Timestamp,
+			EndTimestamp:     events[end].Timestamp,
+			CompactedContent: &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}},
+		}
+		return event
+	}
+	events = append(events, compaction("s1", 0, 1), compaction("s2", 0, 3))
+
+	got := strings.Join(historyTexts(applyCompactions(events)), ",")
+	if want := "s2,q3,a3"; got != want {
+		t.Errorf("Unexpected history: got %s, want %s", got, want)
+	}
+}
+
+func TestLLMAgent_SummarizingHistory(t *testing.T) {
+	history := newHistoryEvents()
+	history.turn("q1", "a1")
+	history.toolTurn("q2", "lookup", "a2")
+	history.turn("q3", "a3")
+
+	conn := fake.New(
+		fake.Turn{Response: fake.Text("The user asked three questions.")},
+		fake.Turn{Response: fake.Text("a4")},
+		fake.Turn{Response: fake.Text("a5")},
+	)
+	agent := NewLLMAgent("summarizing-agent", "Summarizes its history", &LlmAgentConfig{
+		Model:         "test-model",
+		MaxToolCalls:  5,
+		RetryAttempts: 1,
+		History:       &SummarizingHistory{KeepTurns: 1, CompactTurns: 2},
+	})
+	agent.SetLLMConnection(conn)
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	session.Events = history.events
+	ask := func(question string) []*core.Event {
+		t.Helper()
+		message := &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(question)}}}
+		event := core.NewEvent("test-invocation", "user")
+		event.Content = message
+		session.AddEvent(event)
+
+		invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+		invocationCtx.UserContent = message
+		events, err := agent.Run(invocationCtx)
+		if err != nil {
+			t.Fatalf("Agent run failed: %v", err)
+		}
+		return events
+	}
+
+	events := ask("q4")
+	if len(events) != 2 || events[0].Actions.Compaction == nil {
+		t.Fatalf("Expected a compaction event before the response, got %d events", len(events))
+	}
+	if events[0].Author != "summarizing-agent" || events[0].Usage == nil || events[0].Usage.LLMCalls != 1 {
+		t.Errorf("Unexpected compaction event: author %s, usage %+v", events[0].Author, events[0].Usage)
+	}
+	summaryPrompt := fake.LastUserMessage(conn.Requests()[0])
+	if !strings.Contains(summaryPrompt, "user: q3") || !strings.Contains(summaryPrompt, "lookup returned") || strings.Contains(summaryPrompt, "q4") {
+		t.Errorf("Unexpected summary prompt: %s", summaryPrompt)
+	}
+	if got, want := contentsText(conn.Requests()[1].Contents), "Summary of the earlier conversation:\nThe user asked three questions.,q4"; got != want {
+		t.Errorf("Unexpected request contents: got %s, want %s", got, want)
+	}
+
+	// The stored summary is reused rather than written again
+	ask("q5")
+	conn.AssertDone(t)
+	if got, want := contentsText(conn.Requests()[2].Contents), "Summary of the earlier conversation:\nThe user asked three questions.,q4,a4,q5"; got != want {
+		t.Errorf("Unexpected request contents: got %s, want %s", got, want)
+	}
+}
+
+func contentsText(contents []core.Content) string {
+	var texts []string
+	for _, content := range contents {
+		if text := contentText(&content); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, ",")
+}
@@ -16,13 +16,17 @@ package agents
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect"
 	"github.com/agent-protocol/adk-golang/pkg/ptr"
+	"github.com/agent-protocol/adk-golang/pkg/tools"
 )
 
 var _ core.BaseAgent = (*LLMAgent)(nil)
@@ -42,6 +46,12 @@ func formatContent(content *core.Content) string {
 			} else {
 				parts = append(parts, fmt.Sprintf("Part[%d]:text=<nil>", i))
 			}
+		case "thought":
+			if part.Thought != nil {
+				parts = append(parts, fmt.Sprintf("Part[%d]:thought=%q", i, *part.Thought))
+			} else {
+				parts = append(parts, fmt.Sprintf("Part[%d]:thought=<nil>", i))
+			}
 		case "function_call":
 			if part.FunctionCall != nil {
 				parts = append(parts, fmt.Sprintf("Part[%d]:function_call={name=%s, args=%+v}", i, part.FunctionCall.Name, part.FunctionCall.Args))
@@ -74,8 +84,35 @@ type LlmAgentConfig struct {
 	ToolCallTimeout   time.Duration `json:"tool_call_timeout,omitempty"`
 	RetryAttempts     int           `json:"retry_attempts,omitempty"`
 	StreamingEnabled  bool          `json:"streaming_enabled,omitempty"`
+
+	// OutputSchema constrains the final response to a JSON schema, for
+	// example one derived from a Go struct with tools.SchemaFor. It is
+	// forwarded to providers supporting structured output and the final
+	// response is validated against it.
+	OutputSchema map[string]any `json:"output_schema,omitempty"`
+
+	// OutputKey is the session state key the final response is stored
+	// under: the parsed JSON value with an OutputSchema, the text otherwise.
+	OutputKey string `json:"output_key,omitempty"`
+
+	// Thinking enables the reasoning of models that support it. The
+	// model's reasoning is emitted as thought parts of the agent's events.
+	Thinking *core.ThinkingConfig `json:"thinking,omitempty"`
+
+	// History selects the session events sent to the model, for example
+	// a SlidingWindowHistory, TokenBudgetHistory or SummarizingHistory.
+	// The whole session is sent when nil.
+	History HistoryStrategy `json:"-"`
 }
 
+// ErrorCodeOutputSchema is the error code of a final event whose response
+// does not match the agent's OutputSchema.
+const ErrorCodeOutputSchema = "OUTPUT_SCHEMA_VIOLATION"
+
+// ErrorCodeResponseBlocked is the error code of a final event whose response
+// the model's provider withheld, for example by a safety filter.
+const ErrorCodeResponseBlocked = "RESPONSE_BLOCKED"
+
 // DefaultLlmAgentConfig returns a default configuration for LLM agents.
 func DefaultLlmAgentConfig() *LlmAgentConfig {
 	return &LlmAgentConfig{
@@ -89,12 +126,40 @@ func DefaultLlmAgentConfig() *LlmAgentConfig {
 	}
 }
 
+// BeforeModelCallback is called before each model call with the request,
+// which it may modify. Returning a response skips the call and uses that
+// response instead, e.g. to serve a cached answer or block a request.
+type BeforeModelCallback func(invocationCtx *core.InvocationContext, request *core.LLMRequest) (*core.LLMResponse, error)
+
+// AfterModelCallback is called with each complete model response before it
+// becomes an event. Returning a response replaces it. When streaming, the
+// partial events have already been published, unless a
+// PartialResponseCallback dropped them.
+type AfterModelCallback func(invocationCtx *core.InvocationContext, response *core.LLMResponse) (*core.LLMResponse, error)
+
+// PartialResponseCallback is called with each partial response of a
+// streaming model call and reports whether to publish it as a partial event.
+// Dropped partials still count towards the complete response, which goes
+// through the AfterModelCallback as usual.
+type PartialResponseCallback func(invocationCtx *core.InvocationContext, response *core.LLMResponse) (bool, error)
+
+// BeforeToolCallback is called before each tool call with the arguments,
+// which it may modify. Returning a result skips the tool and uses that
+// result as its response.
+type BeforeToolCallback func(toolCtx *core.ToolContext, tool core.BaseTool, args map[string]any) (map[string]any, error)
+
+// AfterToolCallback is called with the response of each tool call,
+// including error responses. Returning a result replaces the response.
+type AfterToolCallback func(toolCtx *core.ToolContext, tool core.BaseTool, args map[string]any, result map[string]any) (map[string]any, error)
+
 // LlmAgentCallbacks contains callback functions for LLM agent lifecycle events.
+// An error returned by a callback ends the agent's run.
 type LlmAgentCallbacks struct {
-	BeforeModelCallback core.BeforeAgentCallback
-	AfterModelCallback  core.AfterAgentCallback
-	BeforeToolCallback  core.BeforeAgentCallback
-	AfterToolCallback   core.AfterAgentCallback
+	BeforeModelCallback     BeforeModelCallback
+	AfterModelCallback      AfterModelCallback
+	PartialResponseCallback PartialResponseCallback
+	BeforeToolCallback      BeforeToolCallback
+	AfterToolCallback       AfterToolCallback
 }
 
 // LLMAgent is an enhanced implementation of an LLM-based agent with comprehensive tool execution.
@@ -105,6 +170,13 @@ type LLMAgent struct {
 	toolMap       map[string]core.BaseTool
 	llmConnection core.LLMConnection
 	callbacks     *LlmAgentCallbacks
+
+	// connMu guards llmConnection, which is resolved lazily from the model
+	// name through registry (default llmconnect.DefaultRegistry) when no
+	// connection was set explicitly.
+	connMu   sync.Mutex
+	registry *llmconnect.Registry
+	resolved bool
 }
 
 // NewLLMAgent creates a new enhanced LLM agent with the specified configuration.
@@ -149,9 +221,17 @@ func (a *LLMAgent) Model() string {
 	return a.config.Model
 }
 
-// SetModel sets the LLM model name.
+// SetModel sets the LLM model name. A connection previously resolved from
+// the old model name is dropped so the new one is resolved on the next run.
 func (a *LLMAgent) SetModel(model string) {
 	a.config.Model = model
+
+	a.connMu.Lock()
+	defer a.connMu.Unlock()
+	if a.resolved {
+		a.llmConnection = nil
+		a.resolved = false
+	}
 }
 
 // Tools returns the available tools for this agent.
@@ -190,9 +270,50 @@ func (a *LLMAgent) GetTool(name string) (core.BaseTool, bool) {
 	return tool, exists
 }
 
-// SetLLMConnection sets the LLM connection for this agent.
+// SetLLMConnection sets the LLM connection for this agent. Without one, the
+// connection is resolved from the model name through the llmconnect registry.
 func (a *LLMAgent) SetLLMConnection(conn core.LLMConnection) {
+	a.connMu.Lock()
+	defer a.connMu.Unlock()
 	a.llmConnection = conn
+	a.resolved = false
+}
+
+// SetRegistry sets the registry the connection is resolved from when none was
+// set with SetLLMConnection. A connection previously resolved from another
+// registry is dropped.
+func (a *LLMAgent) SetRegistry(registry *llmconnect.Registry) {
+	a.connMu.Lock()
+	defer a.connMu.Unlock()
+	a.registry = registry
+	if a.resolved {
+		a.llmConnection = nil
+		a.resolved = false
+	}
+}
+
+// LLMConnection returns the connection used by this agent, resolving it from
+// the model name on first use.
+func (a *LLMAgent) LLMConnection() (core.LLMConnection, error) {
+	a.connMu.Lock()
+	defer a.connMu.Unlock()
+
+	if a.llmConnection != nil {
+		return a.llmConnection, nil
+	}
+
+	registry := a.registry
+	if registry == nil {
+		registry = llmconnect.DefaultRegistry()
+	}
+	conn, err := registry.Resolve(a.config.Model)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Resolved LLM connection for agent %s from model %q", a.name, a.config.Model)
+	a.llmConnection = conn
+	a.resolved = true
+	return conn, nil
 }
 
 // SetCallbacks sets the callback functions for this agent.
@@ -200,6 +321,11 @@ func (a *LLMAgent) SetCallbacks(callbacks *LlmAgentCallbacks) {
 	a.callbacks = callbacks
 }
 
+// Callbacks returns the callback functions of this agent.
+func (a *LLMAgent) Callbacks() *LlmAgentCallbacks {
+	return a.callbacks
+}
+
 // Run is a synchronous wrapper around RunAsync.
 func (a *LLMAgent) Run(invocationCtx *core.InvocationContext) ([]*core.Event, error) {
 	return a.CustomAgent.Run(invocationCtx)
@@ -208,9 +334,9 @@ func (a *LLMAgent) Run(invocationCtx *core.InvocationContext) ([]*core.Event, er
 // RunAsync executes the LLM agent with comprehensive tool execution pipeline.
 func (a *LLMAgent) RunAsync(invocationCtx *core.InvocationContext) (core.EventStream, error) {
 	log.Printf("Starting RunAsync for agent: %s", a.name)
-	if a.llmConnection == nil {
-		log.Printf("LLM connection not configured for agent: %s", a.name)
-		return nil, fmt.Errorf("LLM connection not configured for agent %s", a.name)
+	if _, err := a.LLMConnection(); err != nil {
+		log.Printf("LLM connection not configured for agent %s: %v", a.name, err)
+		return nil, fmt.Errorf("LLM connection not configured for agent %s: %w", a.name, err)
 	}
 
 	return a.CustomAgent.RunAsync(invocationCtx)
@@ -231,7 +357,7 @@ func (a *LLMAgent) executeConversationFlow(invocationCtx *core.InvocationContext
 		}
 
 		// Process LLM turn
-		event, shouldContinue, err := a.processLLMTurn(invocationCtx, turn)
+		event, shouldContinue, err := a.processLLMTurn(invocationCtx, eventChan, turn)
 		if err != nil {
 			return err
 		}
@@ -298,13 +424,19 @@ func (a *LLMAgent) executeConversationFlow(invocationCtx *core.InvocationContext
 	return nil
 }
 
-// processLLMTurn processes a single LLM turn and returns the event and whether to continue
-func (a *LLMAgent) processLLMTurn(invocationCtx *core.InvocationContext, turn int) (*core.Event, bool, error) {
+// processLLMTurn processes a single LLM turn and returns the event and whether to continue.
+// When streaming, partial events are published to eventChan as the response arrives.
+func (a *LLMAgent) processLLMTurn(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event, turn int) (*core.Event, bool, error) {
 	// Log user input if present
 	if invocationCtx.UserContent != nil {
 		log.Printf("User input: %s", formatContent(invocationCtx.UserContent))
 	}
 
+	// Compact older history before it is sent again
+	if err := a.compactHistory(invocationCtx, eventChan); err != nil {
+		return nil, false, err
+	}
+
 	// Build LLM request from conversation history
 	log.Println("Building LLM request...")
 	request, err := a.buildLLMRequest(invocationCtx)
@@ -313,19 +445,47 @@ func (a *LLMAgent) processLLMTurn(invocationCtx *core.InvocationContext, turn in
 		return nil, false, fmt.Errorf("failed to build LLM request: %w", err)
 	}
 
-	// Execute before-model callback
+	// Execute before-model callback, which may answer in place of the model
+	var response *core.LLMResponse
 	if a.callbacks.BeforeModelCallback != nil {
-		if err := a.callbacks.BeforeModelCallback(invocationCtx); err != nil {
+		response, err = a.callbacks.BeforeModelCallback(invocationCtx, request)
+		if err != nil {
 			return nil, false, fmt.Errorf("before-model callback failed: %w", err)
 		}
 	}
 
-	// Make LLM call with retry logic
-	log.Println("Making LLM call...")
-	response, err := a.makeRetriableLLMCall(invocationCtx, request)
-	if err != nil {
-		log.Printf("LLM request failed: %v", err)
-		return nil, false, fmt.Errorf("LLM request failed: %w", err)
+	var usage *core.Usage
+	if response != nil {
+		log.Println("Before-model callback provided the response - skipping LLM call")
+		usage = response.Usage
+	} else {
+		// Make LLM call with retry logic, streaming partial events if enabled
+		log.Println("Making LLM call...")
+		started := time.Now()
+		if a.streamingEnabled(invocationCtx) {
+			response, err = a.makeStreamingLLMCall(invocationCtx, eventChan, request)
+		} else {
+			response, err = a.makeRetriableLLMCall(invocationCtx, request)
+		}
+		if err != nil {
+			log.Printf("LLM request failed: %v", err)
+			return nil, false, fmt.Errorf("LLM request failed: %w", err)
+		}
+		usage = responseUsage(response, time.Since(started))
+		if blocked, _ := response.Metadata["blocked"].(bool); blocked {
+			response = blockedResponse(response)
+		}
+	}
+
+	// Execute after-model callback, which may replace the response
+	if a.callbacks.AfterModelCallback != nil {
+		replacement, err := a.callbacks.AfterModelCallback(invocationCtx, response)
+		if err != nil {
+			return nil, false, fmt.Errorf("after-model callback failed: %w", err)
+		}
+		if replacement != nil {
+			response = replacement
+		}
 	}
 
 	log.Printf("LLM response content: %s", formatContent(response.Content))
@@ -333,13 +493,18 @@ func (a *LLMAgent) processLLMTurn(invocationCtx *core.InvocationContext, turn in
 	// Create event from LLM response
 	event := core.NewEvent(invocationCtx.InvocationID, a.name)
 	event.Content = response.Content
-
-	// Execute after-model callback
-	if a.callbacks.AfterModelCallback != nil {
-		if err := a.callbacks.AfterModelCallback(invocationCtx, []*core.Event{event}); err != nil {
-			return nil, false, fmt.Errorf("after-model callback failed: %w", err)
+	if backend, ok := response.Metadata[llmconnect.BackendMetadataKey]; ok {
+		event.CustomMetadata = map[string]any{llmconnect.BackendMetadataKey: backend}
+	}
+	for key, value := range response.CustomMetadata {
+		if event.CustomMetadata == nil {
+			event.CustomMetadata = make(map[string]any)
 		}
+		event.CustomMetadata[key] = value
 	}
+	event.ErrorCode = response.ErrorCode
+	event.ErrorMessage = response.ErrorMessage
+	event.Usage = usage
 
 	// Check for function calls
 	functionCalls := event.GetFunctionCalls()
@@ -348,6 +513,7 @@ func (a *LLMAgent) processLLMTurn(invocationCtx *core.InvocationContext, turn in
 		// No tool calls - this is a final response
 		log.Println("No function calls found - marking as final response")
 		event.TurnComplete = ptr.Ptr(true)
+		a.processFinalOutput(event)
 		return event, false, nil
 	}
 
@@ -355,6 +521,116 @@ func (a *LLMAgent) processLLMTurn(invocationCtx *core.InvocationContext, turn in
 	return event, true, nil
 }
 
+// responseUsage returns the usage of an LLM response for its event. The
+// measured latency is used when the connection reported none, and a
+// connection that reports no calls still counts as one call. Only responses
+// served from a cache count zero calls.
+func responseUsage(response *core.LLMResponse, latency time.Duration) *core.Usage {
+	var usage core.Usage
+	if response.Usage != nil {
+		usage = *response.Usage
+	}
+	if cacheHit, _ := response.Metadata[llmconnect.CacheHitMetadataKey].(bool); cacheHit {
+		usage.LLMCalls = 0
+	} else if usage.LLMCalls == 0 {
+		usage.LLMCalls = 1
+	}
+	if usage.Latency == 0 {
+		usage.Latency = latency
+	}
+	return &usage
+}
+
+// blockedResponse returns a copy of a blocked response flagged with
+// ErrorCodeResponseBlocked, unless the connection set an error already.
+func blockedResponse(response *core.LLMResponse) *core.LLMResponse {
+	blocked := *response
+	if blocked.ErrorCode != nil {
+		return &blocked
+	}
+
+	message := "Response blocked by the model provider"
+	for _, key := range []string{"block_reason", "finish_reason"} {
+		if reason, ok := response.Metadata[key]; ok {
+			message = fmt.Sprintf("%s: %v", message, reason)
+			break
+		}
+	}
+	blocked.ErrorCode = ptr.Ptr(ErrorCodeResponseBlocked)
+	blocked.ErrorMessage = ptr.Ptr(message)
+	return &blocked
+}
+
+// processFinalOutput validates the final response against the output schema
+// and stores it in session state under the output key. A response that does
+// not match the schema is flagged on the event and not stored.
+func (a *LLMAgent) processFinalOutput(event *core.Event) {
+	if a.config.OutputSchema == nil && a.config.OutputKey == "" {
+		return
+	}
+	if event.ErrorCode != nil {
+		return
+	}
+
+	var output any = eventText(event)
+	if a.config.OutputSchema != nil {
+		parsed, err := ParseStructuredOutput(output.(string), a.config.OutputSchema)
+		if err != nil {
+			log.Printf("Final response does not match the output schema: %v", err)
+			event.ErrorCode = ptr.Ptr(ErrorCodeOutputSchema)
+			event.ErrorMessage = ptr.Ptr(err.Error())
+			return
+		}
+		output = parsed
+	}
+
+	if a.config.OutputKey != "" {
+		if event.Actions.StateDelta == nil {
+			event.Actions.StateDelta = make(map[string]any)
+		}
+		event.Actions.StateDelta[a.config.OutputKey] = output
+	}
+}
+
+// ParseStructuredOutput decodes a JSON response and validates it against
+// schema. Markdown code fences around the JSON are tolerated.
+func ParseStructuredOutput(text string, schema map[string]any) (any, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+
+	var parsed any
+	if err := json.Unmarshal([]byte(text), &parsed); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	if err := tools.ValidateJSONSchema(parsed, schema); err != nil {
+		return nil, fmt.Errorf("response does not match the output schema: %w", err)
+	}
+	return parsed, nil
+}
+
+// eventText joins the text parts of an event.
+func eventText(event *core.Event) string {
+	return contentText(event.Content)
+}
+
+// contentText joins the text parts of a content.
+func contentText(content *core.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != nil {
+			texts = append(texts, *part.Text)
+		}
+	}
+	return strings.Join(texts, "")
+}
+
 // ErrConversationComplete is a special error that indicates the conversation has completed gracefully
 type ErrConversationComplete struct {
 	Reason string
@@ -413,14 +689,15 @@ func (a *LLMAgent) processToolCalls(invocationCtx *core.InvocationContext, event
 	// Add event to session for next iteration
 	invocationCtx.Session.AddEvent(event)
 
+	// Create tool response event; tools record their actions on it
+	responseEvent := core.NewEvent(invocationCtx.InvocationID, a.name)
+
 	// Execute tools and collect responses
-	toolResponses, err := a.executeToolCalls(invocationCtx, functionCalls, eventChan)
+	toolResponses, err := a.executeToolCalls(invocationCtx, functionCalls, eventChan, &responseEvent.Actions)
 	if err != nil {
 		return fmt.Errorf("tool execution failed: %w", err)
 	}
 
-	// Create tool response event
-	responseEvent := core.NewEvent(invocationCtx.InvocationID, a.name)
 	responseEvent.Content = &core.Content{
 		Role:  "agent",
 		Parts: toolResponses,
@@ -442,16 +719,10 @@ func (a *LLMAgent) processToolCalls(invocationCtx *core.InvocationContext, event
 }
 
 // executeToolCalls executes all function calls and returns their responses.
-func (a *LLMAgent) executeToolCalls(invocationCtx *core.InvocationContext, functionCalls []*core.FunctionCall, eventChan chan<- *core.Event) ([]core.Part, error) {
+// The actions recorded by the tools are merged into actions.
+func (a *LLMAgent) executeToolCalls(invocationCtx *core.InvocationContext, functionCalls []*core.FunctionCall, eventChan chan<- *core.Event, actions *core.EventActions) ([]core.Part, error) {
 	log.Println("Starting tool execution...")
 
-	// Execute before-tool callback
-	if a.callbacks.BeforeToolCallback != nil {
-		if err := a.callbacks.BeforeToolCallback(invocationCtx); err != nil {
-			return nil, fmt.Errorf("before-tool callback failed: %w", err)
-		}
-	}
-
 	toolResponses := make([]core.Part, 0, len(functionCalls))
 
 	for _, funcCall := range functionCalls {
@@ -473,38 +744,12 @@ func (a *LLMAgent) executeToolCalls(invocationCtx *core.InvocationContext, funct
 			continue
 		}
 
-		log.Printf("Executing tool: %s", tool.Name())
 		toolCtx := core.NewToolContext(invocationCtx)
 		toolCtx.FunctionCallID = &funcCall.ID
 
-		result, err := a.executeToolWithTimeout(toolCtx, tool, funcCall.Args)
+		response, err := a.callTool(toolCtx, tool, funcCall.Args)
 		if err != nil {
-			log.Printf("Tool execution failed for %s: %v", tool.Name(), err)
-			toolResponses = append(toolResponses, core.Part{
-				Type: "function_response",
-				FunctionResponse: &core.FunctionResponse{
-					ID:   funcCall.ID,
-					Name: funcCall.Name,
-					Response: map[string]any{
-						"error": err.Error(),
-					},
-				},
-			})
-			continue
-		}
-
-		log.Printf("Tool execution succeeded for %s: %v", tool.Name(), result)
-
-		// Format the response properly for the LLM
-		var response map[string]any
-		if resultMap, ok := result.(map[string]interface{}); ok {
-			// If result is already a map, use it directly
-			response = resultMap
-		} else {
-			// Otherwise wrap it in a result field
-			response = map[string]any{
-				"result": result,
-			}
+			return nil, err
 		}
 
 		toolResponses = append(toolResponses, core.Part{
@@ -521,31 +766,101 @@ func (a *LLMAgent) executeToolCalls(invocationCtx *core.InvocationContext, funct
 			log.Printf("Applying state delta from tool %s: %v", tool.Name(), toolCtx.Actions.StateDelta)
 			invocationCtx.Session.UpdateState(toolCtx.Actions.StateDelta)
 		}
+		mergeToolActions(actions, toolCtx.Actions)
 	}
 
 	log.Println("Tool execution completed.")
 
-	// Execute after-tool callback
-	if a.callbacks.AfterToolCallback != nil {
-		// Create events for the tool responses to pass to the callback
-		var toolEvents []*core.Event
-		for _, part := range toolResponses {
-			if part.Type == "function_response" && part.FunctionResponse != nil {
-				event := core.NewEvent(invocationCtx.InvocationID, a.name)
-				event.Content = &core.Content{
-					Role:  "agent",
-					Parts: []core.Part{part},
+	return toolResponses, nil
+}
+
+// callTool runs a tool call between the before- and after-tool callbacks and
+// returns the response for the LLM. A failed tool is reported to the LLM in
+// the response; only a failed callback returns an error.
+func (a *LLMAgent) callTool(toolCtx *core.ToolContext, tool core.BaseTool, args map[string]any) (map[string]any, error) {
+	// Execute before-tool callback, which may answer in place of the tool
+	var response map[string]any
+	if a.callbacks.BeforeToolCallback != nil {
+		result, err := a.callbacks.BeforeToolCallback(toolCtx, tool, args)
+		if err != nil {
+			return nil, fmt.Errorf("before-tool callback failed for %s: %w", tool.Name(), err)
+		}
+		response = result
+	}
+
+	if response != nil {
+		log.Printf("Before-tool callback provided the result of %s - skipping execution", tool.Name())
+	} else {
+		log.Printf("Executing tool: %s", tool.Name())
+		result, err := a.executeToolWithTimeout(toolCtx, tool, args)
+		if err != nil {
+			log.Printf("Tool execution failed for %s: %v", tool.Name(), err)
+			response = map[string]any{
+				"error": err.Error(),
+			}
+		} else {
+			log.Printf("Tool execution succeeded for %s: %v", tool.Name(), result)
+
+			// Format the response properly for the LLM
+			if resultMap, ok := result.(map[string]interface{}); ok {
+				// If result is already a map, use it directly
+				response = resultMap
+			} else {
+				// Otherwise wrap it in a result field
+				response = map[string]any{
+					"result": result,
 				}
-				toolEvents = append(toolEvents, event)
 			}
 		}
+	}
 
-		if err := a.callbacks.AfterToolCallback(invocationCtx, toolEvents); err != nil {
-			return nil, fmt.Errorf("after-tool callback failed: %w", err)
+	// Execute after-tool callback, which may replace the response
+	if a.callbacks.AfterToolCallback != nil {
+		replacement, err := a.callbacks.AfterToolCallback(toolCtx, tool, args, response)
+		if err != nil {
+			return nil, fmt.Errorf("after-tool callback failed for %s: %w", tool.Name(), err)
+		}
+		if replacement != nil {
+			response = replacement
 		}
 	}
 
-	return toolResponses, nil
+	return response, nil
+}
+
+// mergeToolActions copies the actions recorded by a tool into dst so the runner
+// can act on them (artifact tracking, auth requests, transfers).
+func mergeToolActions(dst, src *core.EventActions) {
+	if dst == nil || src == nil {
+		return
+	}
+	for key, value := range src.StateDelta {
+		if dst.StateDelta == nil {
+			dst.StateDelta = make(map[string]any)
+		}
+		dst.StateDelta[key] = value
+	}
+	for filename, version := range src.ArtifactDelta {
+		if dst.ArtifactDelta == nil {
+			dst.ArtifactDelta = make(map[string]int)
+		}
+		dst.ArtifactDelta[filename] = version
+	}
+	for credentialID, authConfig := range src.RequestedAuthConfigs {
+		if dst.RequestedAuthConfigs == nil {
+			dst.RequestedAuthConfigs = make(map[string]core.AuthConfig)
+		}
+		dst.RequestedAuthConfigs[credentialID] = authConfig
+	}
+	if src.TransferToAgent != nil {
+		dst.TransferToAgent = src.TransferToAgent
+	}
+	if src.Escalate != nil {
+		dst.Escalate = src.Escalate
+	}
+	if src.SkipSummarization != nil {
+		dst.SkipSummarization = src.SkipSummarization
+	}
 }
 
 // executeToolWithTimeout executes a tool with the configured timeout.
@@ -565,8 +880,13 @@ func (a *LLMAgent) executeToolWithTimeout(toolCtx *core.ToolContext, tool core.B
 func (a *LLMAgent) makeRetriableLLMCall(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
 	var lastErr error
 
+	conn, err := a.LLMConnection()
+	if err != nil {
+		return nil, err
+	}
+
 	for attempt := 0; attempt < a.config.RetryAttempts; attempt++ {
-		response, err := a.llmConnection.GenerateContent(ctx, request)
+		response, err := conn.GenerateContent(ctx, request)
 		if err == nil {
 			return response, nil
 		}
@@ -632,20 +952,100 @@ func (a *LLMAgent) buildLLMRequest(invocationCtx *core.InvocationContext) (*core
 	// Step 4: Add current user content (with deduplication)
 	contents = a.addUserContentIfNew(contents, invocationCtx.UserContent)
 
-	// Step 5: Build tool declarations
+	// Step 5: Load the blobs offloaded to the artifact service
+	contents, err := a.loadArtifactParts(invocationCtx, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 6: Build tool declarations
 	tools := a.buildToolDeclarations()
 
-	// Step 6: Create LLM configuration
+	// Step 7: Create LLM configuration
 	llmConfig := a.createLLMConfig(tools)
 
-	// Step 7: Log final contents for debugging
-	a.logRequestContents(contents)
-
-	return &core.LLMRequest{
+	request := &core.LLMRequest{
 		Contents: contents,
 		Config:   llmConfig,
 		Tools:    tools,
-	}, nil
+	}
+
+	// Step 8: Let tools adjust the request (e.g. add instructions or context)
+	if err := a.processToolRequests(invocationCtx, request); err != nil {
+		return nil, err
+	}
+
+	// Step 9: Log final contents for debugging
+	a.logRequestContents(request.Contents)
+
+	return request, nil
+}
+
+// loadArtifactParts replaces the file_data parts referencing artifacts, such as
+// input blobs saved with RunConfig.SaveInputBlobsAsArtifacts, by the artifacts'
+// data, as models cannot fetch artifact:// URIs. The parts are copied since
+// they are shared with the session events.
+func (a *LLMAgent) loadArtifactParts(invocationCtx *core.InvocationContext, contents []core.Content) ([]core.Content, error) {
+	artifactService := invocationCtx.ArtifactService
+	if artifactService == nil {
+		return contents, nil
+	}
+
+	session := invocationCtx.Session
+	for i, content := range contents {
+		var parts []core.Part
+		for j, part := range content.Parts {
+			if part.FileData == nil || !strings.HasPrefix(part.FileData.FileURI, core.ArtifactURIScheme) {
+				continue
+			}
+
+			filename := strings.TrimPrefix(part.FileData.FileURI, core.ArtifactURIScheme)
+			req := &core.LoadArtifactRequest{
+				AppName:   session.AppName,
+				UserID:    session.UserID,
+				SessionID: session.ID,
+				Filename:  filename,
+			}
+			// The version is a float64 once the session went through JSON
+			switch version := part.Metadata["artifact_version"].(type) {
+			case int:
+				req.Version = &version
+			case float64:
+				req.Version = ptr.Ptr(int(version))
+			}
+
+			data, err := artifactService.LoadArtifact(invocationCtx, req)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load artifact %s: %w", filename, err)
+			}
+			if data == nil {
+				// A deleted artifact leaves the model with the reference only
+				log.Printf("Artifact %s referenced by the conversation was not found", filename)
+				continue
+			}
+
+			if parts == nil {
+				parts = append([]core.Part(nil), content.Parts...)
+			}
+			parts[j] = core.NewInlineDataPart(part.FileData.MimeType, data)
+			parts[j].InlineData.DisplayName = part.FileData.DisplayName
+		}
+		if parts != nil {
+			contents[i].Parts = parts
+		}
+	}
+	return contents, nil
+}
+
+// processToolRequests gives every tool a chance to modify the LLM request.
+func (a *LLMAgent) processToolRequests(invocationCtx *core.InvocationContext, request *core.LLMRequest) error {
+	toolCtx := core.NewToolContext(invocationCtx)
+	for _, tool := range a.tools {
+		if err := tool.ProcessLLMRequest(toolCtx, request); err != nil {
+			return fmt.Errorf("tool %s failed to process LLM request: %w", tool.Name(), err)
+		}
+	}
+	return nil
 }
 
 // addSystemInstruction adds system instruction to contents if present.
@@ -668,10 +1068,14 @@ func (a *LLMAgent) addSystemInstruction(contents []core.Content) []core.Content
 }
 
 // addSessionHistory adds session events to contents, excluding system messages.
+// The agent's history strategy, if any, selects the events.
 func (a *LLMAgent) addSessionHistory(contents []core.Content, events []*core.Event) []core.Content {
+	if a.config != nil && a.config.History != nil {
+		events = a.config.History.SelectHistory(events)
+	}
 	for _, event := range events {
-		if event.Content != nil && event.Content.Role != "system" {
-			contents = append(contents, *event.Content)
+		if content := eventContent(event); content != nil && content.Role != "system" {
+			contents = append(contents, *content)
 		}
 	}
 	log.Printf("Added %d session events to contents", len(events))
@@ -759,6 +1163,11 @@ func (a *LLMAgent) createLLMConfig(tools []*core.FunctionDeclaration) *core.LLMC
 		TopK:              a.config.TopK,
 		Tools:             tools,
 		SystemInstruction: &a.instruction,
+		Thinking:          a.config.Thinking,
+	}
+	if a.config.OutputSchema != nil {
+		config.ResponseMIMEType = "application/json"
+		config.ResponseSchema = a.config.OutputSchema
 	}
 
 	log.Printf("Created LLM config: Model=%s, Tools=%d", config.Model, len(tools))
@@ -776,6 +1185,10 @@ func (a *LLMAgent) logRequestContents(contents []core.Content) {
 				if part.Text != nil {
 					log.Printf("    Part[%d]: text='%s'", j, *part.Text)
 				}
+			case "thought":
+				if part.Thought != nil {
+					log.Printf("    Part[%d]: thought='%s'", j, *part.Thought)
+				}
 			case "function_call":
 				if part.FunctionCall != nil {
 					log.Printf("    Part[%d]: function_call=%s(%v)", j, part.FunctionCall.Name, part.FunctionCall.Args)
@@ -9,7 +9,10 @@ import (
 	"time"
 
 	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect/fake"
 	"github.com/agent-protocol/adk-golang/pkg/ptr"
+	"github.com/agent-protocol/adk-golang/pkg/tools"
 )
 
 // MockLLMConnection is a mock implementation for testing.
@@ -389,21 +392,21 @@ func TestLlmAgent_CallbackExecution(t *testing.T) {
 	var beforeToolCalled, afterToolCalled bool
 
 	callbacks := &LlmAgentCallbacks{
-		BeforeModelCallback: func(invocationCtx *core.InvocationContext) error {
+		BeforeModelCallback: func(invocationCtx *core.InvocationContext, request *core.LLMRequest) (*core.LLMResponse, error) {
 			beforeModelCalled = true
-			return nil
+			return nil, nil
 		},
-		AfterModelCallback: func(invocationCtx *core.InvocationContext, events []*core.Event) error {
+		AfterModelCallback: func(invocationCtx *core.InvocationContext, response *core.LLMResponse) (*core.LLMResponse, error) {
 			afterModelCalled = true
-			return nil
+			return nil, nil
 		},
-		BeforeToolCallback: func(invocationCtx *core.InvocationContext) error {
+		BeforeToolCallback: func(toolCtx *core.ToolContext, tool core.BaseTool, args map[string]any) (map[string]any, error) {
 			beforeToolCalled = true
-			return nil
+			return nil, nil
 		},
-		AfterToolCallback: func(invocationCtx *core.InvocationContext, events []*core.Event) error {
+		AfterToolCallback: func(toolCtx *core.ToolContext, tool core.BaseTool, args, result map[string]any) (map[string]any, error) {
 			afterToolCalled = true
-			return nil
+			return nil, nil
 		},
 	}
 
@@ -476,6 +479,110 @@ func TestLlmAgent_CallbackExecution(t *testing.T) {
 	}
 }
 
+func TestLlmAgent_CallbacksRewriteAndShortCircuit(t *testing.T) {
+	conn := fake.New(
+		fake.Turn{
+			Response: fake.Call("lookup", map[string]any{"query": "account 1234"}),
+			Expect:   fake.ExpectSystemInstruction("Be brief."),
+		},
+		fake.Turn{
+			Response: fake.Text("Your balance is 42, card 4111 1111 1111 1111."),
+			Expect:   fake.ExpectFunctionResponse("lookup"),
+		},
+	)
+	agent := NewLLMAgent("callback-agent", "Agent with callbacks", &LlmAgentConfig{
+		Model:         "test-model",
+		MaxToolCalls:  5,
+		RetryAttempts: 1,
+	})
+	agent.SetLLMConnection(conn)
+	tool := NewMockTool("lookup", map[string]any{"balance": 42})
+	agent.AddTool(tool)
+
+	var toolArgs, toolResult map[string]any
+	agent.SetCallbacks(&LlmAgentCallbacks{
+		BeforeModelCallback: func(invocationCtx *core.InvocationContext, request *core.LLMRequest) (*core.LLMResponse, error) {
+			if strings.Contains(fake.LastUserMessage(request), "password") {
+				return fake.Text("I cannot help with that."), nil
+			}
+			request.Contents = append([]core.Content{{Role: "system", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Be brief.")}}}}, request.Contents...)
+			return nil, nil
+		},
+		AfterModelCallback: func(invocationCtx *core.InvocationContext, response *core.LLMResponse) (*core.LLMResponse, error) {
+			text := contentText(response.Content)
+			if !strings.Contains(text, "4111") {
+				return nil, nil
+			}
+			return fake.Text(strings.ReplaceAll(text, "4111 1111 1111 1111", "[REDACTED]")), nil
+		},
+		BeforeToolCallback: func(toolCtx *core.ToolContext, tool core.BaseTool, args map[string]any) (map[string]any, error) {
+			args["query"] = strings.ReplaceAll(args["query"].(string), "1234", "****")
+			toolArgs = args
+			return nil, nil
+		},
+		AfterToolCallback: func(toolCtx *core.ToolContext, tool core.BaseTool, args, result map[string]any) (map[string]any, error) {
+			toolResult = result
+			return map[string]any{"balance": result["balance"], "cached": true}, nil
+		},
+	})
+
+	run := func(text string) []*core.Event {
+		t.Helper()
+		session := core.NewSession("test-session", "test-app", "test-user")
+		invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+		invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}}
+		events, err := agent.Run(invocationCtx)
+		if err != nil {
+			t.Fatalf("Agent run failed: %v", err)
+		}
+		return events
+	}
+
+	events := run("What is my balance?")
+	conn.AssertDone(t)
+	if toolArgs["query"] != "account ****" || toolResult["balance"] != 42 {
+		t.Errorf("Unexpected tool args %v or result %v", toolArgs, toolResult)
+	}
+	responses := events[1].GetFunctionResponses()
+	if len(responses) != 1 || responses[0].Response["cached"] != true {
+		t.Errorf("Expected the after-tool callback's response, got %+v", responses)
+	}
+	if got := eventText(events[len(events)-1]); got != "Your balance is 42, card [REDACTED]." {
+		t.Errorf("Expected a redacted final response, got %q", got)
+	}
+
+	// A short-circuited call never reaches the model
+	events = run("What is my password?")
+	if len(conn.Requests()) != 2 {
+		t.Errorf("Expected no further model call, got %d calls", len(conn.Requests()))
+	}
+	final := events[len(events)-1]
+	if got := eventText(final); got != "I cannot help with that." {
+		t.Errorf("Unexpected final response %q", got)
+	}
+	if final.Usage != nil && final.Usage.LLMCalls != 0 {
+		t.Errorf("Expected no LLM call in the usage, got %+v", final.Usage)
+	}
+}
+
+func TestLlmAgent_CallbackErrorEndsRun(t *testing.T) {
+	agent := NewLLMAgent("callback-agent", "Agent with callbacks", &LlmAgentConfig{Model: "test-model", RetryAttempts: 1})
+	agent.SetLLMConnection(fake.New())
+	agent.SetCallbacks(&LlmAgentCallbacks{
+		BeforeModelCallback: func(invocationCtx *core.InvocationContext, request *core.LLMRequest) (*core.LLMResponse, error) {
+			return nil, fmt.Errorf("blocked")
+		},
+	})
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Hi")}}}
+	_, err := agent.Run(invocationCtx)
+	if err == nil || !strings.Contains(err.Error(), "before-model callback failed: blocked") {
+		t.Errorf("Expected the callback error to end the run, got %v", err)
+	}
+}
+
 // Test loop detection functionality
 func TestLoopDetector_CheckToolCallLimit(t *testing.T) {
 	detector := NewLoopDetector()
@@ -1801,3 +1908,346 @@ func (m *MockToolWithDeclaration) RunAsync(toolCtx *core.ToolContext, args map[s
 func (m *MockToolWithDeclaration) ProcessLLMRequest(toolCtx *core.ToolContext, request *core.LLMRequest) error {
 	return nil
 }
+
+func TestLLMAgent_ResolvesConnectionFromModel(t *testing.T) {
+	mockLLM := NewMockLLMConnection(&core.LLMResponse{
+		Content: &core.Content{Role: "assistant", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("resolved")}}},
+	})
+	var resolvedModel string
+	registry := llmconnect.NewRegistry("")
+	registry.Register("agents-test", []string{"agents-test/*"}, func(model string, settings llmconnect.ProviderSettings) (core.LLMConnection, error) {
+		resolvedModel = model
+		return mockLLM, nil
+	})
+
+	config := DefaultLlmAgentConfig()
+	config.Model = "agents-test/tiny"
+	agent := NewLLMAgent("resolving-agent", "Resolves its model", config)
+	agent.SetRegistry(registry)
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Hi")}}}
+
+	if _, err := agent.Run(invocationCtx); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if resolvedModel != "tiny" {
+		t.Errorf("Expected model 'tiny' to be resolved, got %q", resolvedModel)
+	}
+
+	agent.SetModel("unknown/model")
+	if _, err := agent.RunAsync(invocationCtx); err == nil {
+		t.Error("Expected an error for an unregistered model")
+	}
+}
+
+func TestLLMAgent_RecordsUsageOnEvents(t *testing.T) {
+	mockLLM := NewMockLLMConnection(&core.LLMResponse{
+		Content: &core.Content{Role: "assistant", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("done")}}},
+		Usage:   &core.Usage{InputTokens: 12, OutputTokens: 3, TotalTokens: 15, LLMCalls: 1},
+	})
+	agent := NewLLMAgent("usage-agent", "Records usage", nil)
+	agent.SetLLMConnection(mockLLM)
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Hi")}}}
+
+	events, err := agent.Run(invocationCtx)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	usage := events[len(events)-1].Usage
+	if usage == nil || usage.InputTokens != 12 || usage.OutputTokens != 3 || usage.LLMCalls != 1 || usage.Latency <= 0 {
+		t.Errorf("Expected the response usage with measured latency, got %+v", usage)
+	}
+
+	summary := core.SummarizeUsage(session.Events)
+	if summary.ByInvocation["test-invocation"] == nil || summary.ByInvocation["test-invocation"].TotalTokens != 15 {
+		t.Errorf("Unexpected invocation usage: %+v", summary.ByInvocation)
+	}
+}
+
+func TestResponseUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *core.LLMResponse
+		calls    int
+	}{
+		{"no usage", &core.LLMResponse{}, 1},
+		{"usage without calls", &core.LLMResponse{Usage: &core.Usage{InputTokens: 5}}, 1},
+		{"reported calls", &core.LLMResponse{Usage: &core.Usage{LLMCalls: 2}}, 2},
+		{"cache hit", &core.LLMResponse{
+			Usage:    &core.Usage{},
+			Metadata: map[string]any{llmconnect.CacheHitMetadataKey: true},
+		}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := responseUsage(tt.response, time.Millisecond)
+			if usage.LLMCalls != tt.calls || usage.Latency != time.Millisecond {
+				t.Errorf("Expected %d calls with the measured latency, got %+v", tt.calls, usage)
+			}
+		})
+	}
+}
+
+func TestLLMAgent_WithFakeConnection(t *testing.T) {
+	conn := fake.New(
+		fake.Turn{
+			LastUserMessage: "Melbourne",
+			Response:        fake.Call("get_weather", map[string]any{"input": "Melbourne"}),
+			Expect:          fake.ExpectTools("get_weather"),
+		},
+		fake.Turn{
+			Response: fake.Text("It is sunny in Melbourne."),
+			Expect:   fake.ExpectFunctionResponse("get_weather"),
+		},
+	)
+	agent := NewLLMAgent("weather-agent", "Answers weather questions", &LlmAgentConfig{
+		Model:             "test-model",
+		SystemInstruction: ptr.Ptr("You report the weather."),
+		MaxToolCalls:      5,
+		RetryAttempts:     1,
+	})
+	agent.SetLLMConnection(conn)
+	tool := NewMockTool("get_weather", map[string]any{"forecast": "sunny"})
+	agent.AddTool(tool)
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Weather in Melbourne?")}}}
+
+	events, err := agent.Run(invocationCtx)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	conn.AssertDone(t)
+
+	if tool.callCount != 1 {
+		t.Errorf("Expected the tool to be called once, got %d", tool.callCount)
+	}
+	if err := fake.ExpectSystemInstruction("You report the weather.")(conn.Requests()[0]); err != nil {
+		t.Error(err)
+	}
+	final := events[len(events)-1]
+	if final.Content == nil || *final.Content.Parts[0].Text != "It is sunny in Melbourne." {
+		t.Errorf("Unexpected final event: %+v", final.Content)
+	}
+}
+
+func TestLLMAgent_OutputSchema(t *testing.T) {
+	type weatherReport struct {
+		City  string  `json:"city"`
+		HighC float64 `json:"high_c"`
+	}
+	schema, err := tools.SchemaFor(weatherReport{})
+	if err != nil {
+		t.Fatalf("SchemaFor failed: %v", err)
+	}
+
+	run := func(response string) (*core.Event, *core.Session, *fake.Connection) {
+		conn := fake.New(fake.Turn{Response: fake.Text(response)})
+		agent := NewLLMAgent("report-agent", "Reports weather", &LlmAgentConfig{
+			Model:         "test-model",
+			RetryAttempts: 1,
+			OutputSchema:  schema,
+			OutputKey:     "report",
+		})
+		agent.SetLLMConnection(conn)
+
+		session := core.NewSession("test-session", "test-app", "test-user")
+		invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+		invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Weather in Melbourne?")}}}
+		events, err := agent.Run(invocationCtx)
+		if err != nil {
+			t.Fatalf("Agent run failed: %v", err)
+		}
+		return events[len(events)-1], session, conn
+	}
+
+	final, session, conn := run("```json\n{\"city\": \"Melbourne\", \"high_c\": 24}\n```")
+	config := conn.LastRequest().Config
+	if config.ResponseMIMEType != "application/json" || config.ResponseSchema["type"] != "object" {
+		t.Errorf("Expected the schema to be forwarded, got %+v", config)
+	}
+	if final.ErrorCode != nil {
+		t.Fatalf("Unexpected error: %s", *final.ErrorMessage)
+	}
+	report, ok := session.State["report"].(map[string]any)
+	if !ok || report["city"] != "Melbourne" || report["high_c"] != float64(24) {
+		t.Errorf("Expected the parsed report in session state, got %v", session.State["report"])
+	}
+
+	final, session, _ = run(`{"city": "Melbourne"}`)
+	if final.ErrorCode == nil || *final.ErrorCode != ErrorCodeOutputSchema {
+		t.Errorf("Expected a schema violation, got %+v", final)
+	}
+	if _, stored := session.State["report"]; stored {
+		t.Error("Expected an invalid response not to be stored")
+	}
+}
+
+func TestLLMAgent_Thinking(t *testing.T) {
+	response := fake.Text("42")
+	response.Content.Parts = append([]core.Part{core.NewThoughtPart("Six times seven.")}, response.Content.Parts...)
+	conn := fake.New(fake.Turn{
+		Response: response,
+		Expect: func(request *core.LLMRequest) error {
+			if request.Config == nil || request.Config.Thinking == nil {
+				return fmt.Errorf("expected thinking to be enabled")
+			}
+			return nil
+		},
+	})
+	agent := NewLLMAgent("thinking-agent", "Thinks before answering", &LlmAgentConfig{
+		Model:         "test-model",
+		RetryAttempts: 1,
+		OutputKey:     "answer",
+		Thinking:      &core.ThinkingConfig{BudgetTokens: 2048},
+	})
+	agent.SetLLMConnection(conn)
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("What is 6 x 7?")}}}
+
+	events, err := agent.Run(invocationCtx)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	conn.AssertDone(t)
+
+	final := events[len(events)-1]
+	if !final.HasThoughts() || *final.Content.Parts[0].Thought != "Six times seven." {
+		t.Errorf("Expected the thought in the final event, got %+v", final.Content)
+	}
+	if final.Actions.StateDelta["answer"] != "42" {
+		t.Errorf("Expected thoughts to be left out of the output, got %v", final.Actions.StateDelta["answer"])
+	}
+	if stripped := final.WithoutThoughts(); stripped.HasThoughts() || *stripped.Content.Parts[0].Text != "42" || !final.HasThoughts() {
+		t.Errorf("Unexpected event without thoughts: %+v", stripped.Content)
+	}
+}
+
+func TestLLMAgent_Streaming(t *testing.T) {
+	conn := fake.New(
+		fake.Turn{Response: fake.Call("get_weather", map[string]any{"input": "Melbourne"})},
+		fake.Turn{Response: fake.Text("It is sunny."), Partials: []string{"It is ", "sunny."}},
+	)
+	agent := NewLLMAgent("weather-agent", "Answers weather questions", &LlmAgentConfig{
+		Model:         "test-model",
+		MaxToolCalls:  5,
+		RetryAttempts: 1,
+	})
+	agent.SetLLMConnection(conn)
+	agent.AddTool(NewMockTool("get_weather", map[string]any{"forecast": "sunny"}))
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Weather in Melbourne?")}}}
+	invocationCtx.RunConfig = &core.RunConfig{StreamingMode: core.StreamingModeSSE}
+
+	events, err := agent.Run(invocationCtx)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	conn.AssertDone(t)
+
+	var partials []string
+	for _, event := range events {
+		if event.Partial != nil && *event.Partial {
+			partials = append(partials, *event.Content.Parts[0].Text)
+		}
+	}
+	if strings.Join(partials, "|") != "It is |sunny." {
+		t.Errorf("Expected the text to be streamed as partial events, got %q", partials)
+	}
+
+	final := events[len(events)-1]
+	if final.Partial != nil || *final.Content.Parts[0].Text != "It is sunny." || final.TurnComplete == nil {
+		t.Errorf("Expected an aggregated final event, got %+v", final)
+	}
+	for _, event := range session.Events {
+		if event.Partial != nil && *event.Partial {
+			t.Error("Partial events must not be added to the session")
+		}
+	}
+}
+
+func TestLLMAgent_BlockedResponse(t *testing.T) {
+	for _, streaming := range []bool{true, false} {
+		t.Run(fmt.Sprintf("streaming=%v", streaming), func(t *testing.T) {
+			conn := fake.New(fake.Turn{
+				Response: &core.LLMResponse{Metadata: map[string]any{"blocked": true, "finish_reason": "SAFETY"}},
+				Partials: []string{"The first half ", "of an answer"},
+			})
+			agent := NewLLMAgent("blocked-agent", "Gets blocked", &LlmAgentConfig{
+				Model:            "test-model",
+				RetryAttempts:    1,
+				StreamingEnabled: streaming,
+				OutputSchema:     map[string]any{"type": "object"},
+				OutputKey:        "answer",
+			})
+			agent.SetLLMConnection(conn)
+
+			session := core.NewSession("test-session", "test-app", "test-user")
+			invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+			invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Tell me")}}}
+
+			events, err := agent.Run(invocationCtx)
+			if err != nil {
+				t.Fatalf("Agent run failed: %v", err)
+			}
+
+			final := events[len(events)-1]
+			if final.Partial != nil || final.Content != nil {
+				t.Errorf("Expected the partial answer to be dropped, got %+v", final.Content)
+			}
+			if final.ErrorCode == nil || *final.ErrorCode != ErrorCodeResponseBlocked {
+				t.Errorf("Expected error code %s, got %v", ErrorCodeResponseBlocked, final.ErrorCode)
+			}
+			if final.ErrorMessage == nil || !strings.Contains(*final.ErrorMessage, "SAFETY") {
+				t.Errorf("Expected the block reason in the error message, got %v", final.ErrorMessage)
+			}
+			if _, stored := final.Actions.StateDelta["answer"]; stored {
+				t.Error("Expected a blocked response not to be stored under the output key")
+			}
+		})
+	}
+}
+
+func TestStreamAggregator_AssemblesToolCalls(t *testing.T) {
+	partial := func(parts ...core.Part) *core.LLMResponse {
+		return &core.LLMResponse{Content: &core.Content{Role: "model", Parts: parts}, Partial: ptr.Ptr(true)}
+	}
+	aggregator := &streamAggregator{}
+	aggregator.add(partial(core.Part{Type: "text", Text: ptr.Ptr("Checking ")}))
+	aggregator.add(partial(core.Part{Type: "function_call", FunctionCall: &core.FunctionCall{ID: "c1", Name: "lookup", Args: map[string]any{"q": "go"}}}))
+	aggregator.add(partial(core.Part{Type: "function_call", FunctionCall: &core.FunctionCall{Args: map[string]any{"limit": 3}}}))
+	aggregator.add(partial(core.Part{Type: "function_call", FunctionCall: &core.FunctionCall{ID: "c2", Name: "fetch"}}))
+	aggregator.add(partial(core.Part{Type: "text", Text: ptr.Ptr("both.")}))
+	aggregator.final = &core.LLMResponse{Usage: &core.Usage{OutputTokens: 9}}
+
+	response, err := aggregator.response()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parts := response.Content.Parts
+	if len(parts) != 3 || *parts[0].Text != "Checking both." {
+		t.Fatalf("Unexpected assembled content: %+v", response.Content)
+	}
+	first, second := parts[1].FunctionCall, parts[2].FunctionCall
+	if first.ID != "c1" || first.Args["q"] != "go" || first.Args["limit"] != 3 || second.Name != "fetch" {
+		t.Errorf("Unexpected assembled calls: %+v, %+v", first, second)
+	}
+	if response.Usage.OutputTokens != 9 {
+		t.Errorf("Expected the usage of the final response, got %+v", response.Usage)
+	}
+
+	failed := &streamAggregator{final: &core.LLMResponse{Metadata: map[string]any{"error": "connection reset"}}}
+	if _, err := failed.response(); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("Expected the stream error to be returned, got %v", err)
+	}
+}
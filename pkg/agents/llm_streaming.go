@@ -0,0 +1,211 @@
+package agents
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// streamingEnabled reports whether the agent streams its responses, either
+// because it is configured to or because the run asks for partial events.
+func (a *LLMAgent) streamingEnabled(invocationCtx *core.InvocationContext) bool {
+	if a.config.StreamingEnabled {
+		return true
+	}
+	return invocationCtx.RunConfig != nil && invocationCtx.RunConfig.StreamingMode == core.StreamingModeSSE
+}
+
+// makeStreamingLLMCall streams an LLM response, publishing a partial event
+// for each chunk as it arrives, and returns the aggregated response. Like
+// makeRetriableLLMCall it retries failed calls, but only as long as no
+// partial event has been published.
+func (a *LLMAgent) makeStreamingLLMCall(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event, request *core.LLMRequest) (*core.LLMResponse, error) {
+	var lastErr error
+
+	conn, err := a.LLMConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < a.config.RetryAttempts; attempt++ {
+		response, published, err := a.streamLLMResponse(invocationCtx, eventChan, conn, request)
+		if err == nil {
+			return response, nil
+		}
+
+		lastErr = err
+
+		// Partial events cannot be taken back, so a broken stream is final
+		if published || !a.isRetryableError(err) {
+			break
+		}
+
+		// Wait before retry (exponential backoff)
+		if attempt < a.config.RetryAttempts-1 {
+			waitTime := time.Duration(attempt+1) * time.Second
+			select {
+			case <-invocationCtx.Done():
+				return nil, invocationCtx.Err()
+			case <-time.After(waitTime):
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("LLM call failed after %d attempts: %w", a.config.RetryAttempts, lastErr)
+}
+
+// streamLLMResponse makes a single streaming call. It reports whether any
+// partial event was published.
+func (a *LLMAgent) streamLLMResponse(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event,
+	conn core.LLMConnection, request *core.LLMRequest) (*core.LLMResponse, bool, error) {
+
+	stream, err := conn.GenerateContentStream(invocationCtx, request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	aggregator := &streamAggregator{}
+	published := false
+	for response := range stream {
+		if response == nil {
+			continue
+		}
+		if response.Partial == nil || !*response.Partial {
+			aggregator.final = response
+			continue
+		}
+
+		aggregator.add(response)
+		if response.Content == nil || len(response.Content.Parts) == 0 {
+			continue
+		}
+		if callback := a.callbacks.PartialResponseCallback; callback != nil {
+			publish, err := callback(invocationCtx, response)
+			if err != nil {
+				return nil, published, fmt.Errorf("partial-response callback failed: %w", err)
+			}
+			if !publish {
+				continue
+			}
+		}
+		event := core.NewEvent(invocationCtx.InvocationID, a.name)
+		event.Content = response.Content
+		event.Partial = ptr.Ptr(true)
+		select {
+		case eventChan <- event:
+			published = true
+		case <-invocationCtx.Done():
+			return nil, published, invocationCtx.Err()
+		}
+	}
+	if err := invocationCtx.Err(); err != nil {
+		return nil, published, err
+	}
+
+	response, err := aggregator.response()
+	return response, published, err
+}
+
+// streamAggregator assembles the chunks of a streamed LLM response. Text and
+// thoughts are concatenated, and function calls are assembled from deltas
+// that share an ID; a delta without ID or name continues the previous call.
+type streamAggregator struct {
+	role     string
+	text     strings.Builder
+	thoughts strings.Builder
+	calls    []*core.FunctionCall
+	partials int
+	final    *core.LLMResponse
+}
+
+// add merges a partial response.
+func (s *streamAggregator) add(response *core.LLMResponse) {
+	s.partials++
+	if response.Content == nil {
+		return
+	}
+	if response.Content.Role != "" {
+		s.role = response.Content.Role
+	}
+	for _, part := range response.Content.Parts {
+		switch {
+		case part.Thought != nil:
+			s.thoughts.WriteString(*part.Thought)
+		case part.FunctionCall != nil:
+			s.addCall(part.FunctionCall)
+		case part.Text != nil:
+			s.text.WriteString(*part.Text)
+		}
+	}
+}
+
+// addCall merges a function call delta.
+func (s *streamAggregator) addCall(delta *core.FunctionCall) {
+	var call *core.FunctionCall
+	for _, existing := range s.calls {
+		if delta.ID != "" && existing.ID == delta.ID {
+			call = existing
+		}
+	}
+	if call == nil && delta.ID == "" && delta.Name == "" && len(s.calls) > 0 {
+		call = s.calls[len(s.calls)-1]
+	}
+	if call == nil {
+		call = &core.FunctionCall{ID: delta.ID, Args: make(map[string]any)}
+		s.calls = append(s.calls, call)
+	}
+	if delta.Name != "" {
+		call.Name = delta.Name
+	}
+	for key, value := range delta.Args {
+		call.Args[key] = value
+	}
+}
+
+// response returns the complete response. Connections finish a stream with
+// a complete response, which is used as is; otherwise the response is
+// assembled from the partial chunks. A final response marked as blocked is
+// authoritative: the chunks streamed before the block are not an answer.
+func (s *streamAggregator) response() (*core.LLMResponse, error) {
+	if s.final != nil {
+		if streamErr, failed := s.final.Metadata["error"]; failed {
+			return nil, fmt.Errorf("LLM stream failed: %v", streamErr)
+		}
+		if blocked, _ := s.final.Metadata["blocked"].(bool); blocked {
+			return s.final, nil
+		}
+		if s.final.Content != nil {
+			return s.final, nil
+		}
+	} else if s.partials == 0 {
+		return nil, fmt.Errorf("LLM stream ended without a response")
+	}
+
+	role := s.role
+	if role == "" {
+		role = "model"
+	}
+	content := &core.Content{Role: role}
+	if s.thoughts.Len() > 0 {
+		content.Parts = append(content.Parts, core.NewThoughtPart(s.thoughts.String()))
+	}
+	if s.text.Len() > 0 {
+		content.Parts = append(content.Parts, core.Part{Type: "text", Text: ptr.Ptr(s.text.String())})
+	}
+	for _, call := range s.calls {
+		content.Parts = append(content.Parts, core.Part{Type: "function_call", FunctionCall: call})
+	}
+
+	response := &core.LLMResponse{Partial: ptr.Ptr(false)}
+	if len(content.Parts) > 0 {
+		response.Content = content
+	}
+	if s.final != nil {
+		response.Usage = s.final.Usage
+		response.Metadata = s.final.Metadata
+	}
+	return response, nil
+}
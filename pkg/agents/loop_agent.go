@@ -0,0 +1,226 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+var _ core.BaseAgent = (*LoopAgent)(nil)
+
+// DefaultIterationStateKey is the session state key holding the iteration
+// of a LoopAgent.
+const DefaultIterationStateKey = "loop_iteration"
+
+// LoopAgentConfig contains configuration options for LoopAgent.
+type LoopAgentConfig struct {
+	// MaxIterations limits the number of iterations (0 = unlimited, the
+	// loop then ends on escalation or StopCondition only).
+	MaxIterations int `json:"max_iterations,omitempty"`
+
+	// StopCondition ends the loop when it returns true for the session
+	// state. It is checked after each sub-agent.
+	StopCondition func(state map[string]any) bool `json:"-"`
+
+	// IterationStateKey is the session state key holding the current
+	// iteration, starting at 1 (default DefaultIterationStateKey).
+	IterationStateKey string `json:"iteration_state_key,omitempty"`
+}
+
+// DefaultLoopAgentConfig returns default configuration for LoopAgent.
+func DefaultLoopAgentConfig() *LoopAgentConfig {
+	return &LoopAgentConfig{
+		MaxIterations:     10,
+		IterationStateKey: DefaultIterationStateKey,
+	}
+}
+
+// LoopAgent is a workflow agent that runs its sub-agents in order, again and
+// again, e.g. a writer and a critic refining a draft. The loop ends when a
+// sub-agent escalates (EventActions.Escalate, set by tools with
+// ToolContext.Escalate), when StopCondition becomes true, or after
+// MaxIterations. The remaining sub-agents of the iteration are skipped.
+//
+// At the start of each iteration, the agent emits an event setting the
+// iteration number in the session state, so that sub-agents can see it.
+// The loop fails when a sub-agent emits an event with an error message.
+type LoopAgent struct {
+	*CustomAgent
+	config *LoopAgentConfig
+	agents []core.BaseAgent
+}
+
+// NewLoopAgent creates a new LoopAgent with the given sub-agents.
+func NewLoopAgent(name, description string, agents []core.BaseAgent, maxIterations int) *LoopAgent {
+	config := DefaultLoopAgentConfig()
+	if maxIterations > 0 {
+		config.MaxIterations = maxIterations
+	}
+	return NewLoopAgentWithConfig(name, description, agents, config)
+}
+
+// NewLoopAgentWithConfig creates a new LoopAgent with custom configuration.
+func NewLoopAgentWithConfig(name, description string, agents []core.BaseAgent, config *LoopAgentConfig) *LoopAgent {
+	if config == nil {
+		config = DefaultLoopAgentConfig()
+	}
+
+	agent := &LoopAgent{
+		CustomAgent: NewCustomAgent(name, description),
+		config:      config,
+		agents:      agents,
+	}
+
+	// Set up sub-agents in the hierarchy
+	for _, subAgent := range agents {
+		agent.AddSubAgent(subAgent)
+	}
+
+	// Set the execution function
+	agent.CustomAgent.SetExecute(agent.executeLoopFlow)
+
+	return agent
+}
+
+// Config returns the agent's configuration.
+func (a *LoopAgent) Config() *LoopAgentConfig {
+	return a.config
+}
+
+// Agents returns the list of sub-agents.
+func (a *LoopAgent) Agents() []core.BaseAgent {
+	return a.agents
+}
+
+// AddAgent adds a sub-agent to the loop.
+func (a *LoopAgent) AddAgent(agent core.BaseAgent) {
+	a.agents = append(a.agents, agent)
+	a.AddSubAgent(agent)
+}
+
+// executeLoopFlow runs the sub-agents until the loop ends.
+func (a *LoopAgent) executeLoopFlow(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event) error {
+	log.Printf("Starting loop agent flow with %d agents for up to %d iterations", len(a.agents), a.config.MaxIterations)
+
+	if len(a.agents) == 0 {
+		return fmt.Errorf("no sub-agents configured for loop execution")
+	}
+
+	userContent := invocationCtx.UserContent
+	for iteration := 1; a.config.MaxIterations <= 0 || iteration <= a.config.MaxIterations; iteration++ {
+		// Check for cancellation
+		select {
+		case <-invocationCtx.Done():
+			return invocationCtx.Err()
+		default:
+		}
+
+		if err := a.sendIterationEvent(invocationCtx, eventChan, iteration); err != nil {
+			return err
+		}
+
+		for _, agent := range a.agents {
+			log.Printf("Executing agent %s in iteration %d", agent.Name(), iteration)
+
+			// Only the first sub-agent gets the user's message; the others
+			// find it in the session with the replies so far
+			agentCtx := invocationCtx.Clone()
+			agentCtx.Agent = agent
+			agentCtx.UserContent = userContent
+			userContent = nil
+
+			escalated, err := a.executeAgent(agentCtx, agent, eventChan)
+			if err != nil {
+				return fmt.Errorf("iteration %d failed: %w", iteration, err)
+			}
+			if escalated {
+				log.Printf("Agent %s escalated in iteration %d - ending loop", agent.Name(), iteration)
+				return nil
+			}
+			if a.config.StopCondition != nil && a.config.StopCondition(invocationCtx.Session.CopyState()) {
+				log.Printf("Stop condition met after agent %s in iteration %d - ending loop", agent.Name(), iteration)
+				return nil
+			}
+		}
+	}
+
+	log.Printf("Loop agent reached the maximum of %d iterations", a.config.MaxIterations)
+	return nil
+}
+
+// sendIterationEvent records the iteration in the session state.
+func (a *LoopAgent) sendIterationEvent(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event, iteration int) error {
+	key := a.config.IterationStateKey
+	if key == "" {
+		key = DefaultIterationStateKey
+	}
+
+	event := core.NewEvent(invocationCtx.InvocationID, a.Name())
+	event.Branch = invocationCtx.Branch
+	event.Actions.StateDelta = map[string]any{key: iteration}
+	invocationCtx.Session.UpdateState(event.Actions.StateDelta)
+
+	select {
+	case eventChan <- event:
+		return nil
+	case <-invocationCtx.Done():
+		return invocationCtx.Err()
+	}
+}
+
+// executeAgent runs a sub-agent, forwarding its events, and reports whether
+// it escalated. State changes of the events are applied to the session right
+// away so that the stop condition sees them.
+func (a *LoopAgent) executeAgent(agentCtx *core.InvocationContext, agent core.BaseAgent, eventChan chan<- *core.Event) (bool, error) {
+	stream, err := agent.RunAsync(agentCtx)
+	if err != nil {
+		return false, fmt.Errorf("agent %s failed: %w", agent.Name(), err)
+	}
+
+	escalated := false
+	var agentErr error
+	for event := range stream {
+		if event.Actions.Escalate != nil && *event.Actions.Escalate {
+			escalated = true
+		}
+		if event.ErrorMessage != nil && agentErr == nil {
+			agentErr = fmt.Errorf("agent %s failed: %s", agent.Name(), *event.ErrorMessage)
+		}
+		if len(event.Actions.StateDelta) > 0 {
+			agentCtx.Session.UpdateState(event.Actions.StateDelta)
+		}
+
+		select {
+		case eventChan <- event:
+		case <-agentCtx.Done():
+			return false, agentCtx.Err()
+		}
+	}
+
+	return escalated, agentErr
+}
+
+// Run executes the loop agent synchronously.
+func (a *LoopAgent) Run(invocationCtx *core.InvocationContext) ([]*core.Event, error) {
+	return a.CustomAgent.Run(invocationCtx)
+}
+
+// RunAsync executes the loop agent asynchronously.
+func (a *LoopAgent) RunAsync(invocationCtx *core.InvocationContext) (core.EventStream, error) {
+	return a.CustomAgent.RunAsync(invocationCtx)
+}
+
+// Cleanup performs cleanup operations for the loop agent and its sub-agents.
+func (a *LoopAgent) Cleanup(ctx context.Context) error {
+	// Cleanup all sub-agents
+	for _, agent := range a.agents {
+		if err := agent.Cleanup(ctx); err != nil {
+			log.Printf("Failed to cleanup agent %s: %v", agent.Name(), err)
+		}
+	}
+
+	// Call parent cleanup
+	return a.CustomAgent.Cleanup(ctx)
+}
@@ -0,0 +1,137 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect/fake"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// escalateTool ends a loop by escalating.
+type escalateTool struct {
+	*MockTool
+}
+
+func (t *escalateTool) RunAsync(toolCtx *core.ToolContext, args map[string]any) (any, error) {
+	toolCtx.Escalate()
+	return t.MockTool.RunAsync(toolCtx, args)
+}
+
+// newCountingAgent creates an agent that reports the loop iteration it
+// sees as its score.
+func newCountingAgent(name string, runs *int) *CustomAgent {
+	agent := NewCustomAgent(name, "Counts iterations")
+	agent.SetExecute(func(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event) error {
+		*runs++
+		iteration, _ := invocationCtx.Session.GetState(DefaultIterationStateKey)
+		event := core.NewEvent(invocationCtx.InvocationID, name)
+		event.Actions.StateDelta = map[string]any{"score": iteration}
+		eventChan <- event
+		return nil
+	})
+	return agent
+}
+
+func TestLoopAgent_EndsOnEscalation(t *testing.T) {
+	writerConn := fake.New(
+		fake.Turn{Response: fake.Text("draft 1")},
+		fake.Turn{Response: fake.Text("draft 2")},
+	)
+	writer := NewLLMAgent("writer", "Writes drafts", &LlmAgentConfig{Model: "test-model", MaxToolCalls: 5, RetryAttempts: 1})
+	writer.SetLLMConnection(writerConn)
+
+	criticConn := fake.New(
+		fake.Turn{Response: fake.Text("needs work")},
+		fake.Turn{Response: fake.Call("approve", map[string]any{"input": "draft 2"})},
+		fake.Turn{Response: fake.Text("approved"), Expect: fake.ExpectFunctionResponse("approve")},
+	)
+	critic := NewLLMAgent("critic", "Reviews drafts", &LlmAgentConfig{Model: "test-model", MaxToolCalls: 5, RetryAttempts: 1})
+	critic.SetLLMConnection(criticConn)
+	critic.AddTool(&escalateTool{NewMockTool("approve", "ok")})
+
+	agent := NewLoopAgent("refine", "Refines a draft", []core.BaseAgent{writer, critic}, 5)
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	invocationCtx.UserContent = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Write a haiku")}}}
+
+	events, err := agent.Run(invocationCtx)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	writerConn.AssertDone(t)
+	criticConn.AssertDone(t)
+
+	if iteration, _ := session.GetState(DefaultIterationStateKey); iteration != 2 {
+		t.Errorf("Expected the loop to end in iteration 2, got %v", iteration)
+	}
+	if got := eventText(events[len(events)-1]); got != "approved" {
+		t.Errorf("Expected the critic's last response to end the loop, got %q", got)
+	}
+	iterations := 0
+	for _, event := range events {
+		if event.Author == "refine" && event.Actions.StateDelta[DefaultIterationStateKey] != nil {
+			iterations++
+		}
+	}
+	if iterations != 2 {
+		t.Errorf("Expected an iteration event per iteration, got %d", iterations)
+	}
+}
+
+func TestLoopAgent_EndsOnStopCondition(t *testing.T) {
+	var scorerRuns, otherRuns int
+	agent := NewLoopAgentWithConfig("refine", "Refines until good enough", []core.BaseAgent{
+		newCountingAgent("scorer", &scorerRuns),
+		newCountingAgent("other", &otherRuns),
+	}, &LoopAgentConfig{
+		MaxIterations: 10,
+		StopCondition: func(state map[string]any) bool {
+			score, _ := state["score"].(int)
+			return score >= 3
+		},
+	})
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	if _, err := agent.Run(invocationCtx); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	if scorerRuns != 3 || otherRuns != 2 {
+		t.Errorf("Expected the loop to stop right after the scorer's third run, got %d and %d runs", scorerRuns, otherRuns)
+	}
+}
+
+func TestLoopAgent_MaxIterations(t *testing.T) {
+	var runs int
+	agent := NewLoopAgentWithConfig("refine", "Refines", []core.BaseAgent{newCountingAgent("scorer", &runs)},
+		&LoopAgentConfig{MaxIterations: 3, IterationStateKey: "round"})
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	if _, err := agent.Run(invocationCtx); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	if runs != 3 {
+		t.Errorf("Expected 3 iterations, got %d", runs)
+	}
+	if round, _ := session.GetState("round"); round != 3 {
+		t.Errorf("Expected the iteration under the configured key, got %v", round)
+	}
+}
+
+func TestLoopAgent_FailsOnSubAgentError(t *testing.T) {
+	agent := NewLoopAgent("refine", "Refines", []core.BaseAgent{newScriptedAgent("broken", 0, "no model")}, 5)
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	if _, err := agent.Run(invocationCtx); err == nil {
+		t.Error("Expected the sub-agent's failure to end the loop")
+	}
+	if iteration, _ := session.GetState(DefaultIterationStateKey); iteration != 1 {
+		t.Errorf("Expected the loop to stop in iteration 1, got %v", iteration)
+	}
+}
@@ -0,0 +1,293 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+var _ core.BaseAgent = (*ParallelAgent)(nil)
+
+// ParallelErrorMode selects how a ParallelAgent handles a failing sub-agent.
+type ParallelErrorMode string
+
+const (
+	// ParallelFailFast cancels the other sub-agents on the first failure.
+	ParallelFailFast ParallelErrorMode = "fail_fast"
+
+	// ParallelCollectAll lets the other sub-agents finish and reports all
+	// failures together.
+	ParallelCollectAll ParallelErrorMode = "collect_all"
+)
+
+// ParallelAgentConfig contains configuration options for ParallelAgent.
+type ParallelAgentConfig struct {
+	// MaxConcurrency limits the number of sub-agents running at once
+	// (0 = unlimited).
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+
+	// ErrorMode selects how failures are handled (default ParallelFailFast).
+	ErrorMode ParallelErrorMode `json:"error_mode,omitempty"`
+}
+
+// DefaultParallelAgentConfig returns default configuration for ParallelAgent.
+func DefaultParallelAgentConfig() *ParallelAgentConfig {
+	return &ParallelAgentConfig{
+		MaxConcurrency: 0, // unlimited
+		ErrorMode:      ParallelFailFast,
+	}
+}
+
+// ParallelAgent is a workflow agent that runs its sub-agents concurrently,
+// e.g. to consult several specialists at once. Each sub-agent runs on its
+// own branch with its own copy of the session, so that the sub-agents do not
+// see each other's events.
+//
+// The events are merged into one stream in a deterministic order: all events
+// of the first sub-agent, then all events of the second, and so on. The
+// events of the first sub-agent still running are forwarded as they arrive,
+// the others are buffered until their turn. A sub-agent fails when it emits
+// an event with an error message.
+type ParallelAgent struct {
+	*CustomAgent
+	config *ParallelAgentConfig
+	agents []core.BaseAgent
+}
+
+// NewParallelAgent creates a new ParallelAgent with the given sub-agents.
+func NewParallelAgent(name, description string, agents []core.BaseAgent) *ParallelAgent {
+	return NewParallelAgentWithConfig(name, description, agents, nil)
+}
+
+// NewParallelAgentWithConfig creates a new ParallelAgent with custom configuration.
+func NewParallelAgentWithConfig(name, description string, agents []core.BaseAgent, config *ParallelAgentConfig) *ParallelAgent {
+	if config == nil {
+		config = DefaultParallelAgentConfig()
+	}
+
+	agent := &ParallelAgent{
+		CustomAgent: NewCustomAgent(name, description),
+		config:      config,
+		agents:      agents,
+	}
+
+	// Set up sub-agents in the hierarchy
+	for _, subAgent := range agents {
+		agent.AddSubAgent(subAgent)
+	}
+
+	// Set the execution function
+	agent.CustomAgent.SetExecute(agent.executeParallelFlow)
+
+	return agent
+}
+
+// Config returns the agent's configuration.
+func (a *ParallelAgent) Config() *ParallelAgentConfig {
+	return a.config
+}
+
+// Agents returns the list of sub-agents.
+func (a *ParallelAgent) Agents() []core.BaseAgent {
+	return a.agents
+}
+
+// AddAgent adds a sub-agent.
+func (a *ParallelAgent) AddAgent(agent core.BaseAgent) {
+	a.agents = append(a.agents, agent)
+	a.AddSubAgent(agent)
+}
+
+// parallelBranch buffers the events of a sub-agent until they are merged.
+type parallelBranch struct {
+	agent core.BaseAgent
+	ctx   *core.InvocationContext
+
+	mu      sync.Mutex
+	events  []*core.Event
+	done    bool
+	err     error
+	updated chan struct{}
+}
+
+// push buffers an event of the branch.
+func (b *parallelBranch) push(event *core.Event) {
+	b.mu.Lock()
+	b.events = append(b.events, event)
+	b.mu.Unlock()
+	b.notify()
+}
+
+// finish marks the branch as done.
+func (b *parallelBranch) finish(err error) {
+	b.mu.Lock()
+	b.done = true
+	b.err = err
+	b.mu.Unlock()
+	b.notify()
+}
+
+// notify wakes up the merge loop if it waits for the branch.
+func (b *parallelBranch) notify() {
+	select {
+	case b.updated <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the buffered events and whether the branch is done.
+func (b *parallelBranch) take() ([]*core.Event, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := b.events
+	b.events = nil
+	return events, b.done
+}
+
+// executeParallelFlow runs the sub-agents concurrently and merges their events.
+func (a *ParallelAgent) executeParallelFlow(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event) error {
+	log.Printf("Starting parallel agent flow with %d agents", len(a.agents))
+
+	if len(a.agents) == 0 {
+		return fmt.Errorf("no sub-agents configured for parallel execution")
+	}
+
+	ctx, cancel := context.WithCancel(invocationCtx.Context)
+	defer cancel()
+
+	// Every branch gets a copy of the session before any branch starts, as
+	// the runner updates the session with the events already forwarded
+	branches := make([]*parallelBranch, len(a.agents))
+	for i, agent := range a.agents {
+		agentCtx := invocationCtx.CreateSubContext(agent, a.Name()+"."+agent.Name())
+		agentCtx.Context = ctx
+		agentCtx.Session = invocationCtx.Session.Clone()
+		branches[i] = &parallelBranch{agent: agent, ctx: agentCtx, updated: make(chan struct{}, 1)}
+	}
+
+	// The first failure, which cancels the other branches in fail-fast mode
+	var failOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		failOnce.Do(func() {
+			firstErr = err
+			if a.config.ErrorMode != ParallelCollectAll {
+				cancel()
+			}
+		})
+	}
+
+	// Start the branches in order, at most MaxConcurrency at a time
+	var slots chan struct{}
+	if a.config.MaxConcurrency > 0 {
+		slots = make(chan struct{}, a.config.MaxConcurrency)
+	}
+	go func() {
+		for _, branch := range branches {
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					branch.finish(ctx.Err())
+					continue
+				}
+			}
+			go func() {
+				if slots != nil {
+					defer func() { <-slots }()
+				}
+				err := a.runBranch(ctx, branch)
+				if err != nil {
+					fail(err)
+				}
+				branch.finish(err)
+			}()
+		}
+	}()
+
+	// Merge the events branch by branch
+	for _, branch := range branches {
+		for {
+			events, done := branch.take()
+			for _, event := range events {
+				select {
+				case eventChan <- event:
+				case <-invocationCtx.Done():
+					return invocationCtx.Err()
+				}
+			}
+			if done {
+				break
+			}
+			select {
+			case <-branch.updated:
+			case <-invocationCtx.Done():
+				return invocationCtx.Err()
+			}
+		}
+	}
+
+	if a.config.ErrorMode != ParallelCollectAll {
+		return firstErr
+	}
+	var errs []error
+	for _, branch := range branches {
+		if branch.err != nil {
+			errs = append(errs, branch.err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runBranch runs a sub-agent on its own branch and buffers its events.
+func (a *ParallelAgent) runBranch(ctx context.Context, branch *parallelBranch) error {
+	agent, agentCtx := branch.agent, branch.ctx
+
+	log.Printf("Executing agent %s on branch %s", agent.Name(), *agentCtx.Branch)
+	stream, err := agent.RunAsync(agentCtx)
+	if err != nil {
+		return fmt.Errorf("agent %s failed: %w", agent.Name(), err)
+	}
+
+	var branchErr error
+	for event := range stream {
+		if event.Branch == nil {
+			event.Branch = agentCtx.Branch
+		}
+		if event.ErrorMessage != nil && branchErr == nil {
+			branchErr = fmt.Errorf("agent %s failed: %s", agent.Name(), *event.ErrorMessage)
+		}
+		branch.push(event)
+	}
+	if branchErr == nil && ctx.Err() != nil {
+		branchErr = ctx.Err()
+	}
+	return branchErr
+}
+
+// Run executes the parallel agent synchronously.
+func (a *ParallelAgent) Run(invocationCtx *core.InvocationContext) ([]*core.Event, error) {
+	return a.CustomAgent.Run(invocationCtx)
+}
+
+// RunAsync executes the parallel agent asynchronously.
+func (a *ParallelAgent) RunAsync(invocationCtx *core.InvocationContext) (core.EventStream, error) {
+	return a.CustomAgent.RunAsync(invocationCtx)
+}
+
+// Cleanup performs cleanup operations for the parallel agent and its sub-agents.
+func (a *ParallelAgent) Cleanup(ctx context.Context) error {
+	// Cleanup all sub-agents
+	for _, agent := range a.agents {
+		if err := agent.Cleanup(ctx); err != nil {
+			log.Printf("Failed to cleanup agent %s: %v", agent.Name(), err)
+		}
+	}
+
+	// Call parent cleanup
+	return a.CustomAgent.Cleanup(ctx)
+}
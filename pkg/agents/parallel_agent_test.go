@@ -0,0 +1,182 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// newScriptedAgent creates an agent that emits texts after a delay, or fails
+// with failure when it is not empty.
+func newScriptedAgent(name string, delay time.Duration, failure string, texts ...string) *CustomAgent {
+	agent := NewCustomAgent(name, "Scripted agent")
+	agent.SetExecute(func(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event) error {
+		select {
+		case <-time.After(delay):
+		case <-invocationCtx.Done():
+			return invocationCtx.Err()
+		}
+		if failure != "" {
+			return fmt.Errorf("%s", failure)
+		}
+		for _, text := range texts {
+			event := core.NewEvent(invocationCtx.InvocationID, name)
+			event.Content = &core.Content{Role: "agent", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}}
+			invocationCtx.Session.AddEvent(event)
+			select {
+			case eventChan <- event:
+			case <-invocationCtx.Done():
+				return invocationCtx.Err()
+			}
+		}
+		return nil
+	})
+	return agent
+}
+
+func runParallel(t *testing.T, agent *ParallelAgent, session *core.Session) ([]*core.Event, error) {
+	t.Helper()
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	return agent.Run(invocationCtx)
+}
+
+func TestParallelAgent_MergesBranchesInOrder(t *testing.T) {
+	agent := NewParallelAgent("research", "Consults specialists", []core.BaseAgent{
+		newScriptedAgent("slow", 30*time.Millisecond, "", "slow 1", "slow 2"),
+		newScriptedAgent("fast", 0, "", "fast 1", "fast 2"),
+	})
+	session := core.NewSession("test-session", "test-app", "test-user")
+
+	events, err := runParallel(t, agent, session)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	var got []string
+	for _, event := range events {
+		got = append(got, fmt.Sprintf("%s@%s", eventText(event), *event.Branch))
+	}
+	want := "slow 1@research.slow,slow 2@research.slow,fast 1@research.fast,fast 2@research.fast"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Unexpected events: got %s, want %s", strings.Join(got, ","), want)
+	}
+	if len(session.Events) != 0 {
+		t.Errorf("Expected the branches to use their own session copies, got %d events", len(session.Events))
+	}
+}
+
+func TestParallelAgent_FailFast(t *testing.T) {
+	agent := NewParallelAgent("research", "Consults specialists", []core.BaseAgent{
+		newScriptedAgent("stuck", time.Minute, "", "never"),
+		newScriptedAgent("broken", 0, "no data"),
+	})
+
+	started := time.Now()
+	_, err := runParallel(t, agent, core.NewSession("test-session", "test-app", "test-user"))
+	if err == nil || !strings.Contains(err.Error(), "agent broken failed") || !strings.Contains(err.Error(), "no data") {
+		t.Errorf("Expected the first failure, got %v", err)
+	}
+	if time.Since(started) > 10*time.Second {
+		t.Error("Expected the other branches to be cancelled")
+	}
+}
+
+func TestParallelAgent_CollectAll(t *testing.T) {
+	agent := NewParallelAgentWithConfig("research", "Consults specialists", []core.BaseAgent{
+		newScriptedAgent("first", 0, "first failed"),
+		newScriptedAgent("ok", 10*time.Millisecond, "", "answer"),
+		newScriptedAgent("second", 0, "second failed"),
+	}, &ParallelAgentConfig{ErrorMode: ParallelCollectAll})
+
+	events, err := runParallel(t, agent, core.NewSession("test-session", "test-app", "test-user"))
+	if err == nil || !strings.Contains(err.Error(), "first failed") || !strings.Contains(err.Error(), "second failed") {
+		t.Errorf("Expected both failures, got %v", err)
+	}
+	found := false
+	for _, event := range events {
+		if eventText(event) == "answer" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the successful branch to finish")
+	}
+}
+
+func TestParallelAgent_MaxConcurrency(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	var subAgents []core.BaseAgent
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("worker%d", i)
+		agent := NewCustomAgent(name, "Worker")
+		agent.SetExecute(func(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event) error {
+			now := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if now <= current || maxRunning.CompareAndSwap(current, now) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			event := core.NewEvent(invocationCtx.InvocationID, name)
+			eventChan <- event
+			return nil
+		})
+		subAgents = append(subAgents, agent)
+	}
+	agent := NewParallelAgentWithConfig("workers", "Runs workers", subAgents, &ParallelAgentConfig{MaxConcurrency: 2})
+
+	events, err := runParallel(t, agent, core.NewSession("test-session", "test-app", "test-user"))
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(events) != 5 {
+		t.Errorf("Expected an event from each worker, got %d", len(events))
+	}
+	for i, event := range events {
+		if want := fmt.Sprintf("worker%d", i); event.Author != want {
+			t.Errorf("Expected event %d from %s, got %s", i, want, event.Author)
+		}
+	}
+	if maxRunning.Load() > 2 {
+		t.Errorf("Expected at most 2 workers at once, got %d", maxRunning.Load())
+	}
+}
+
+func TestParallelAgent_MaxConcurrencyWithStateDeltas(t *testing.T) {
+	var subAgents []core.BaseAgent
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("writer%d", i)
+		agent := NewCustomAgent(name, "Writer")
+		agent.SetExecute(func(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event) error {
+			event := core.NewEvent(invocationCtx.InvocationID, name)
+			event.Actions.StateDelta = map[string]any{name: true}
+			eventChan <- event
+			return nil
+		})
+		subAgents = append(subAgents, agent)
+	}
+	agent := NewParallelAgentWithConfig("writers", "Runs writers", subAgents, &ParallelAgentConfig{MaxConcurrency: 1})
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+
+	stream, err := agent.RunAsync(invocationCtx)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	// Apply events to the session as they arrive, as the runner does
+	for event := range stream {
+		session.AddEvent(event)
+	}
+
+	if session.GetStateSize() != 8 {
+		t.Errorf("Expected state from each writer, got %v", session.State)
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// SaveArtifactRequest represents a request to upload an artifact
+type SaveArtifactRequest struct {
+	Filename string `json:"filename"`
+	MimeType string `json:"mime_type,omitempty"`
+	Data     []byte `json:"data"`
+}
+
+// SaveArtifactResponse is returned after an artifact has been uploaded
+type SaveArtifactResponse struct {
+	Filename string `json:"filename"`
+	Version  int    `json:"version"`
+}
+
+// setupArtifactRoutes configures artifact routes
+func (s *Server) setupArtifactRoutes() {
+	const base = "/apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts"
+	s.router.HandleFunc("GET "+base, s.wrapListArtifacts)
+	s.router.HandleFunc("POST "+base, s.wrapSaveArtifact)
+	s.router.HandleFunc("GET "+base+"/{artifact_name}", s.wrapGetArtifact)
+	s.router.HandleFunc("DELETE "+base+"/{artifact_name}", s.wrapDeleteArtifact)
+	s.router.HandleFunc("GET "+base+"/{artifact_name}/versions", s.wrapListArtifactVersions)
+	s.router.HandleFunc("GET "+base+"/{artifact_name}/versions/{version_id}", s.wrapGetArtifactVersion)
+}
+
+func (s *Server) wrapListArtifacts(w http.ResponseWriter, r *http.Request) {
+	s.handleListArtifacts(w, r, r.PathValue("app_name"), r.PathValue("user_id"), r.PathValue("session_id"))
+}
+
+func (s *Server) wrapSaveArtifact(w http.ResponseWriter, r *http.Request) {
+	s.handleSaveArtifact(w, r, r.PathValue("app_name"), r.PathValue("user_id"), r.PathValue("session_id"))
+}
+
+func (s *Server) wrapGetArtifact(w http.ResponseWriter, r *http.Request) {
+	var version *int
+	if value := r.URL.Query().Get("version"); value != "" {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Invalid version", http.StatusBadRequest)
+			return
+		}
+		version = &v
+	}
+	s.handleGetArtifact(w, r, r.PathValue("app_name"), r.PathValue("user_id"), r.PathValue("session_id"), r.PathValue("artifact_name"), version)
+}
+
+func (s *Server) wrapGetArtifactVersion(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(r.PathValue("version_id"))
+	if err != nil {
+		http.Error(w, "Invalid version", http.StatusBadRequest)
+		return
+	}
+	s.handleGetArtifact(w, r, r.PathValue("app_name"), r.PathValue("user_id"), r.PathValue("session_id"), r.PathValue("artifact_name"), &version)
+}
+
+func (s *Server) wrapListArtifactVersions(w http.ResponseWriter, r *http.Request) {
+	s.handleListArtifactVersions(w, r, r.PathValue("app_name"), r.PathValue("user_id"), r.PathValue("session_id"), r.PathValue("artifact_name"))
+}
+
+func (s *Server) wrapDeleteArtifact(w http.ResponseWriter, r *http.Request) {
+	s.handleDeleteArtifact(w, r, r.PathValue("app_name"), r.PathValue("user_id"), r.PathValue("session_id"), r.PathValue("artifact_name"))
+}
+
+// requireArtifactService writes an error response and returns false if no artifact service is configured
+func (s *Server) requireArtifactService(w http.ResponseWriter) bool {
+	if s.artifactService == nil {
+		http.Error(w, "Artifact service is not configured", http.StatusNotImplemented)
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleListArtifacts(w http.ResponseWriter, r *http.Request, appName, userID, sessionID string) {
+	if !s.requireArtifactService(w) {
+		return
+	}
+
+	keys, err := s.artifactService.ListArtifactKeys(r.Context(), &core.ListArtifactKeysRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list artifacts: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, keys)
+}
+
+func (s *Server) handleSaveArtifact(w http.ResponseWriter, r *http.Request, appName, userID, sessionID string) {
+	if !s.requireArtifactService(w) {
+		return
+	}
+
+	var req SaveArtifactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Filename == "" {
+		http.Error(w, "filename is required", http.StatusBadRequest)
+		return
+	}
+
+	version, err := s.artifactService.SaveArtifact(r.Context(), &core.SaveArtifactRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+		Filename:  req.Filename,
+		Content:   req.Data,
+		MimeType:  req.MimeType,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save artifact: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, &SaveArtifactResponse{Filename: req.Filename, Version: version})
+}
+
+func (s *Server) handleGetArtifact(w http.ResponseWriter, r *http.Request, appName, userID, sessionID, artifactName string, version *int) {
+	if !s.requireArtifactService(w) {
+		return
+	}
+
+	artifact, err := s.artifactService.GetArtifact(r.Context(), &core.LoadArtifactRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+		Filename:  artifactName,
+		Version:   version,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load artifact: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if artifact == nil {
+		http.Error(w, "Artifact not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, artifact)
+}
+
+func (s *Server) handleListArtifactVersions(w http.ResponseWriter, r *http.Request, appName, userID, sessionID, artifactName string) {
+	if !s.requireArtifactService(w) {
+		return
+	}
+
+	versions, err := s.artifactService.ListVersions(r.Context(), &core.ListVersionsRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+		Filename:  artifactName,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list artifact versions: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, versions)
+}
+
+func (s *Server) handleDeleteArtifact(w http.ResponseWriter, r *http.Request, appName, userID, sessionID, artifactName string) {
+	if !s.requireArtifactService(w) {
+		return
+	}
+
+	err := s.artifactService.DeleteArtifact(r.Context(), &core.DeleteArtifactRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+		Filename:  artifactName,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete artifact: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
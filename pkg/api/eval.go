@@ -0,0 +1,224 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/evaluation"
+)
+
+// setupEvalRoutes configures eval set and eval result routes
+func (s *Server) setupEvalRoutes() {
+	s.router.HandleFunc("GET /apps/{app_name}/eval_sets", s.wrapListEvalSets)
+	s.router.HandleFunc("POST /apps/{app_name}/eval_sets/{eval_set_id}", s.wrapCreateEvalSet)
+	s.router.HandleFunc("GET /apps/{app_name}/eval_sets/{eval_set_id}", s.wrapGetEvalSet)
+	s.router.HandleFunc("POST /apps/{app_name}/eval_sets/{eval_set_id}/add_session", s.wrapAddSessionToEvalSet)
+	s.router.HandleFunc("GET /apps/{app_name}/eval_sets/{eval_set_id}/evals", s.wrapListEvalCases)
+	s.router.HandleFunc("GET /apps/{app_name}/eval_sets/{eval_set_id}/evals/{eval_case_id}", s.wrapGetEvalCase)
+	s.router.HandleFunc("DELETE /apps/{app_name}/eval_sets/{eval_set_id}/evals/{eval_case_id}", s.wrapDeleteEvalCase)
+	s.router.HandleFunc("GET /apps/{app_name}/eval_results", s.wrapListEvalResults)
+	s.router.HandleFunc("GET /apps/{app_name}/eval_results/{eval_result_id}", s.wrapGetEvalResult)
+}
+
+func (s *Server) wrapListEvalSets(w http.ResponseWriter, r *http.Request) {
+	s.handleListEvalSets(w, r, r.PathValue("app_name"))
+}
+
+func (s *Server) wrapCreateEvalSet(w http.ResponseWriter, r *http.Request) {
+	s.handleCreateEvalSet(w, r, r.PathValue("app_name"), r.PathValue("eval_set_id"))
+}
+
+func (s *Server) wrapGetEvalSet(w http.ResponseWriter, r *http.Request) {
+	s.handleGetEvalSet(w, r, r.PathValue("app_name"), r.PathValue("eval_set_id"))
+}
+
+func (s *Server) wrapAddSessionToEvalSet(w http.ResponseWriter, r *http.Request) {
+	s.handleAddSessionToEvalSet(w, r, r.PathValue("app_name"), r.PathValue("eval_set_id"))
+}
+
+func (s *Server) wrapListEvalCases(w http.ResponseWriter, r *http.Request) {
+	s.handleListEvalCases(w, r, r.PathValue("app_name"), r.PathValue("eval_set_id"))
+}
+
+func (s *Server) wrapGetEvalCase(w http.ResponseWriter, r *http.Request) {
+	s.handleGetEvalCase(w, r, r.PathValue("app_name"), r.PathValue("eval_set_id"), r.PathValue("eval_case_id"))
+}
+
+func (s *Server) wrapDeleteEvalCase(w http.ResponseWriter, r *http.Request) {
+	s.handleDeleteEvalCase(w, r, r.PathValue("app_name"), r.PathValue("eval_set_id"), r.PathValue("eval_case_id"))
+}
+
+func (s *Server) wrapListEvalResults(w http.ResponseWriter, r *http.Request) {
+	s.handleListEvalResults(w, r, r.PathValue("app_name"))
+}
+
+func (s *Server) wrapGetEvalResult(w http.ResponseWriter, r *http.Request) {
+	s.handleGetEvalResult(w, r, r.PathValue("app_name"), r.PathValue("eval_result_id"))
+}
+
+// requireEvalStore writes an error response and returns false if no eval store is configured
+func (s *Server) requireEvalStore(w http.ResponseWriter) bool {
+	if s.evalStore == nil {
+		http.Error(w, "Eval storage is not configured", http.StatusNotImplemented)
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleListEvalSets(w http.ResponseWriter, r *http.Request, appName string) {
+	if !s.requireEvalStore(w) {
+		return
+	}
+
+	ids, err := s.evalStore.ListEvalSets(r.Context(), appName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list eval sets: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, ids)
+}
+
+func (s *Server) handleCreateEvalSet(w http.ResponseWriter, r *http.Request, appName, evalSetID string) {
+	if !s.requireEvalStore(w) {
+		return
+	}
+
+	evalSet, err := s.evalStore.CreateEvalSet(r.Context(), appName, evalSetID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create eval set: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, evalSet)
+}
+
+func (s *Server) handleGetEvalSet(w http.ResponseWriter, r *http.Request, appName, evalSetID string) {
+	if !s.requireEvalStore(w) {
+		return
+	}
+
+	evalSet, err := s.evalStore.GetEvalSet(r.Context(), appName, evalSetID)
+	if err != nil || evalSet == nil {
+		http.Error(w, "Eval set not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, evalSet)
+}
+
+func (s *Server) handleAddSessionToEvalSet(w http.ResponseWriter, r *http.Request, appName, evalSetID string) {
+	if !s.requireEvalStore(w) {
+		return
+	}
+
+	var req AddSessionToEvalSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.EvalID == "" || req.SessionID == "" || req.UserID == "" {
+		http.Error(w, "eval_id, session_id and user_id are required", http.StatusBadRequest)
+		return
+	}
+
+	session, err := s.sessionService.GetSession(r.Context(), &core.GetSessionRequest{
+		AppName:   appName,
+		UserID:    req.UserID,
+		SessionID: req.SessionID,
+	})
+	if err != nil || session == nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	evalCase := evaluation.NewEvalCaseFromSession(req.EvalID, session)
+	if err := s.evalStore.AddEvalCase(r.Context(), appName, evalSetID, evalCase); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to add session to eval set: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, evalCase)
+}
+
+func (s *Server) handleListEvalCases(w http.ResponseWriter, r *http.Request, appName, evalSetID string) {
+	if !s.requireEvalStore(w) {
+		return
+	}
+
+	evalSet, err := s.evalStore.GetEvalSet(r.Context(), appName, evalSetID)
+	if err != nil || evalSet == nil {
+		http.Error(w, "Eval set not found", http.StatusNotFound)
+		return
+	}
+
+	ids := make([]string, 0, len(evalSet.EvalCases))
+	for _, evalCase := range evalSet.EvalCases {
+		ids = append(ids, evalCase.EvalID)
+	}
+
+	writeJSON(w, ids)
+}
+
+func (s *Server) handleGetEvalCase(w http.ResponseWriter, r *http.Request, appName, evalSetID, evalCaseID string) {
+	if !s.requireEvalStore(w) {
+		return
+	}
+
+	evalCase, err := s.evalStore.GetEvalCase(r.Context(), appName, evalSetID, evalCaseID)
+	if err != nil || evalCase == nil {
+		http.Error(w, "Eval case not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, evalCase)
+}
+
+func (s *Server) handleDeleteEvalCase(w http.ResponseWriter, r *http.Request, appName, evalSetID, evalCaseID string) {
+	if !s.requireEvalStore(w) {
+		return
+	}
+
+	if err := s.evalStore.DeleteEvalCase(r.Context(), appName, evalSetID, evalCaseID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete eval case: %v", err), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListEvalResults(w http.ResponseWriter, r *http.Request, appName string) {
+	if !s.requireEvalStore(w) {
+		return
+	}
+
+	ids, err := s.evalStore.ListEvalSetResults(r.Context(), appName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list eval results: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, ids)
+}
+
+func (s *Server) handleGetEvalResult(w http.ResponseWriter, r *http.Request, appName, evalResultID string) {
+	if !s.requireEvalStore(w) {
+		return
+	}
+
+	result, err := s.evalStore.GetEvalSetResult(r.Context(), appName, evalResultID)
+	if err != nil || result == nil {
+		http.Error(w, "Eval result not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+// writeJSON writes a JSON response body
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
@@ -12,37 +12,44 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/rs/cors"
 
+	"github.com/agent-protocol/adk-golang/pkg/artifacts"
+	"github.com/agent-protocol/adk-golang/pkg/auth"
 	"github.com/agent-protocol/adk-golang/pkg/cli/utils"
 	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/evaluation"
+	"github.com/agent-protocol/adk-golang/pkg/memory"
 	"github.com/agent-protocol/adk-golang/pkg/runners"
 	"github.com/agent-protocol/adk-golang/pkg/sessions"
 )
 
 // ServerConfig contains configuration for the API server
 type ServerConfig struct {
-	Host               string
-	Port               int
-	AgentsDir          string
-	SessionServiceURI  string
-	ArtifactServiceURI string
-	MemoryServiceURI   string
-	EvalStorageURI     string
-	AllowOrigins       []string
-	TraceToCloud       bool
-	A2AEnabled         bool
-	LogLevel           string
+	Host                 string
+	Port                 int
+	AgentsDir            string
+	SessionServiceURI    string
+	ArtifactServiceURI   string
+	MemoryServiceURI     string
+	CredentialServiceURI string
+	EvalStorageURI       string
+	AllowOrigins         []string
+	TraceToCloud         bool
+	A2AEnabled           bool
+	LogLevel             string
 }
 
 // Server represents the HTTP API server
 type Server struct {
-	config          *ServerConfig
-	router          *http.ServeMux
-	sessionService  core.SessionService
-	artifactService core.ArtifactService
-	memoryService   core.MemoryService
-	agentLoader     *utils.AgentLoader
-	runnerCache     map[string]*runners.RunnerImpl
-	upgrader        websocket.Upgrader
+	config            *ServerConfig
+	router            *http.ServeMux
+	sessionService    core.SessionService
+	artifactService   artifacts.ArtifactService
+	memoryService     core.MemoryService
+	credentialService core.CredentialService
+	evalStore         evaluation.EvalStore
+	agentLoader       *utils.AgentLoader
+	runnerCache       map[string]*runners.RunnerImpl
+	upgrader          websocket.Upgrader
 }
 
 // AgentRunRequest represents a request to run an agent
@@ -52,6 +59,10 @@ type AgentRunRequest struct {
 	SessionID  string        `json:"session_id"`
 	NewMessage *core.Content `json:"new_message"`
 	Streaming  bool          `json:"streaming,omitempty"`
+
+	// HideThoughts strips the reasoning of thinking models from the
+	// returned events. The session keeps the thoughts either way.
+	HideThoughts bool `json:"hide_thoughts,omitempty"`
 }
 
 // CreateSessionRequest represents a request to create a session
@@ -90,6 +101,16 @@ func NewServer(config *ServerConfig) (*Server, error) {
 		return nil, fmt.Errorf("failed to create memory service: %w", err)
 	}
 
+	credentialService, err := auth.NewCredentialServiceFromURI(config.CredentialServiceURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credential service: %w", err)
+	}
+
+	evalStore, err := evaluation.NewEvalStoreFromURI(config.EvalStorageURI, config.AgentsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eval store: %w", err)
+	}
+
 	// Initialize agent loader
 	agentLoader := utils.NewAgentLoader(config.AgentsDir)
 
@@ -102,13 +123,15 @@ func NewServer(config *ServerConfig) (*Server, error) {
 	}
 
 	server := &Server{
-		config:          config,
-		sessionService:  sessionService,
-		artifactService: artifactService,
-		memoryService:   memoryService,
-		agentLoader:     agentLoader,
-		runnerCache:     make(map[string]*runners.RunnerImpl),
-		upgrader:        upgrader,
+		config:            config,
+		sessionService:    sessionService,
+		artifactService:   artifactService,
+		memoryService:     memoryService,
+		credentialService: credentialService,
+		evalStore:         evalStore,
+		agentLoader:       agentLoader,
+		runnerCache:       make(map[string]*runners.RunnerImpl),
+		upgrader:          upgrader,
 	}
 
 	server.setupRoutes()
@@ -135,9 +158,14 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("POST /apps/{app_name}/users/{user_id}/sessions/{session_id}", s.wrapCreateSessionWithID)
 	s.router.HandleFunc("DELETE /apps/{app_name}/users/{user_id}/sessions/{session_id}", s.wrapDeleteSession)
 
-	// Future artifact routes (TODO: implement)
-	s.router.HandleFunc("GET /apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts", s.handleNotImplemented)
-	s.router.HandleFunc("POST /apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts", s.handleNotImplemented)
+	// Artifact routes
+	s.setupArtifactRoutes()
+
+	// Eval set and eval result routes
+	s.setupEvalRoutes()
+
+	// Token usage routes
+	s.setupUsageRoutes()
 
 	// A2A routes (if enabled)
 	if s.config.A2AEnabled {
@@ -258,6 +286,19 @@ func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Partial events are superseded by the complete events that follow them
+	visible := make([]*core.Event, 0, len(events))
+	for _, event := range events {
+		if event.Partial != nil && *event.Partial {
+			continue
+		}
+		if req.HideThoughts {
+			event = visibleEvent(event)
+		}
+		visible = append(visible, event)
+	}
+	events = visible
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(events)
 }
@@ -294,12 +335,15 @@ func (s *Server) handleRunSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create run request
+	// Create run request; streaming requests receive partial events live
 	runReq := &core.RunRequest{
 		UserID:     req.UserID,
 		SessionID:  req.SessionID,
 		NewMessage: req.NewMessage,
 	}
+	if req.Streaming {
+		runReq.RunConfig = &core.RunConfig{StreamingMode: core.StreamingModeSSE}
+	}
 
 	// Execute agent and stream events
 	eventStream, err := runner.RunAsync(r.Context(), runReq)
@@ -310,6 +354,11 @@ func (s *Server) handleRunSSE(w http.ResponseWriter, r *http.Request) {
 
 	// Stream events as SSE
 	for event := range eventStream {
+		if req.HideThoughts {
+			if event = visibleEvent(event); event == nil {
+				continue
+			}
+		}
 		eventJSON, err := json.Marshal(event)
 		if err != nil {
 			fmt.Fprintf(w, "data: {\"error\": \"Failed to encode event\"}\n\n")
@@ -325,6 +374,17 @@ func (s *Server) handleRunSSE(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// visibleEvent strips the thought parts of an event for clients hiding them.
+// It returns nil when nothing is left to show: a partial event that only
+// carried thoughts.
+func visibleEvent(event *core.Event) *core.Event {
+	stripped := event.WithoutThoughts()
+	if stripped.Content == nil && event.Content != nil && event.Partial != nil && *event.Partial {
+		return nil
+	}
+	return stripped
+}
+
 // handleRunLive handles WebSocket connections for live agent interactions
 func (s *Server) handleRunLive(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
@@ -469,6 +529,9 @@ func (s *Server) getRunner(appName string) (*runners.RunnerImpl, error) {
 	if s.memoryService != nil {
 		runner.SetMemoryService(s.memoryService)
 	}
+	if s.credentialService != nil {
+		runner.SetCredentialService(s.credentialService)
+	}
 
 	s.runnerCache[appName] = runner
 	return runner, nil
@@ -476,21 +539,15 @@ func (s *Server) getRunner(appName string) (*runners.RunnerImpl, error) {
 
 // Helper functions for service creation
 func createSessionService(uri string) (core.SessionService, error) {
-	if uri == "" {
-		return sessions.NewInMemorySessionService(), nil
-	}
-	// TODO: Implement database session service
-	return sessions.NewInMemorySessionService(), nil
+	return sessions.NewSessionServiceFromURI(uri, nil)
 }
 
-func createArtifactService(uri string) (core.ArtifactService, error) {
-	// TODO: Implement artifact service
-	return nil, nil
+func createArtifactService(uri string) (artifacts.ArtifactService, error) {
+	return artifacts.NewArtifactServiceFromURI(uri)
 }
 
 func createMemoryService(uri string) (core.MemoryService, error) {
-	// TODO: Implement memory service
-	return nil, nil
+	return memory.NewMemoryServiceFromURI(uri)
 }
 
 // convertToContent converts a generic message to Content
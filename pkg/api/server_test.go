@@ -1,11 +1,19 @@
 package api
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/agent-protocol/adk-golang/pkg/artifacts"
 	"github.com/agent-protocol/adk-golang/pkg/cli/utils"
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/evaluation"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
 	"github.com/agent-protocol/adk-golang/pkg/runners"
 	"github.com/agent-protocol/adk-golang/pkg/sessions"
 )
@@ -131,3 +139,258 @@ func TestPathValueExtraction(t *testing.T) {
 			http.StatusOK, w.Code)
 	}
 }
+
+func TestEvalRoutes_AddSessionToEvalSet(t *testing.T) {
+	evalStore, err := evaluation.NewLocalEvalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create eval store: %v", err)
+	}
+
+	config := &ServerConfig{AgentsDir: "test-agents"}
+	server := &Server{
+		config:         config,
+		sessionService: sessions.NewInMemorySessionService(),
+		evalStore:      evalStore,
+		agentLoader:    utils.NewAgentLoader(config.AgentsDir),
+		runnerCache:    make(map[string]*runners.RunnerImpl),
+	}
+	server.setupRoutes()
+
+	ctx := context.Background()
+	session, err := server.sessionService.CreateSession(ctx, &core.CreateSessionRequest{
+		AppName: "weather",
+		UserID:  "alice",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	userEvent := core.NewEvent("inv-1", "user")
+	userEvent.Content = &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Weather in Paris?")}}}
+	callEvent := core.NewEvent("inv-1", "weather_agent")
+	callEvent.Content = &core.Content{Role: "model", Parts: []core.Part{{
+		Type:         "function_call",
+		FunctionCall: &core.FunctionCall{Name: "get_weather", Args: map[string]any{"city": "Paris"}},
+	}}}
+	answerEvent := core.NewEvent("inv-1", "weather_agent")
+	answerEvent.Content = &core.Content{Role: "model", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Sunny.")}}}
+	for _, event := range []*core.Event{userEvent, callEvent, answerEvent} {
+		if err := server.sessionService.AppendEvent(ctx, session, event); err != nil {
+			t.Fatalf("Failed to append event: %v", err)
+		}
+	}
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve("POST", "/apps/weather/eval_sets/regressions", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected eval set to be created, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve("POST", "/apps/weather/eval_sets/regressions", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected duplicate eval set to be rejected, got %d", w.Code)
+	}
+
+	body := `{"user_id": "alice", "session_id": "` + session.ID + `", "eval_id": "paris"}`
+	if w := serve("POST", "/apps/weather/eval_sets/regressions/add_session", body); w.Code != http.StatusOK {
+		t.Fatalf("Expected session to be added, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := serve("GET", "/apps/weather/eval_sets/regressions/evals/paris", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected eval case to be found, got %d", w.Code)
+	}
+	var evalCase evaluation.EvalCase
+	if err := json.Unmarshal(w.Body.Bytes(), &evalCase); err != nil {
+		t.Fatalf("Failed to decode eval case: %v", err)
+	}
+	if len(evalCase.Conversation) != 1 {
+		t.Fatalf("Expected one invocation, got %d", len(evalCase.Conversation))
+	}
+	invocation := evalCase.Conversation[0]
+	if uses := invocation.ToolUses(); len(uses) != 1 || uses[0].Name != "get_weather" {
+		t.Errorf("Expected get_weather tool use, got %+v", uses)
+	}
+	if evaluation.ContentText(invocation.FinalResponse) != "Sunny." {
+		t.Errorf("Unexpected final response: %q", evaluation.ContentText(invocation.FinalResponse))
+	}
+
+	w = serve("GET", "/apps/weather/eval_sets", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "regressions") {
+		t.Errorf("Expected eval set listing to contain regressions, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if err := evalStore.SaveEvalSetResult(ctx, "weather", &evaluation.EvalSetResult{
+		EvalSetResultID: "weather_regressions_1",
+		EvalSetID:       "regressions",
+	}); err != nil {
+		t.Fatalf("Failed to save eval result: %v", err)
+	}
+	w = serve("GET", "/apps/weather/eval_results", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "weather_regressions_1") {
+		t.Errorf("Expected eval results listing to contain the run, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve("GET", "/apps/weather/eval_results/missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected missing eval result to return 404, got %d", w.Code)
+	}
+}
+
+func TestArtifactRoutes(t *testing.T) {
+	artifactService, err := artifacts.NewFileArtifactService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create artifact service: %v", err)
+	}
+
+	config := &ServerConfig{AgentsDir: "test-agents"}
+	server := &Server{
+		config:          config,
+		sessionService:  sessions.NewInMemorySessionService(),
+		artifactService: artifactService,
+		agentLoader:     utils.NewAgentLoader(config.AgentsDir),
+		runnerCache:     make(map[string]*runners.RunnerImpl),
+	}
+	server.setupRoutes()
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	const base = "/apps/app/users/alice/sessions/s1/artifacts"
+	for _, text := range []string{"first", "second"} {
+		body := `{"filename": "notes.txt", "mime_type": "text/plain", "data": "` + base64.StdEncoding.EncodeToString([]byte(text)) + `"}`
+		if w := serve("POST", base, body); w.Code != http.StatusOK {
+			t.Fatalf("Expected artifact to be saved, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := serve("GET", base, "")
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `["notes.txt"]` {
+		t.Errorf("Unexpected artifact listing %d: %s", w.Code, w.Body.String())
+	}
+
+	w = serve("GET", base+"/notes.txt/versions", "")
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `[0,1]` {
+		t.Errorf("Unexpected version listing %d: %s", w.Code, w.Body.String())
+	}
+
+	var artifact artifacts.Artifact
+	w = serve("GET", base+"/notes.txt", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &artifact); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Failed to get latest artifact %d: %v", w.Code, err)
+	}
+	if string(artifact.Content) != "second" || artifact.Version != 1 || artifact.MimeType != "text/plain" {
+		t.Errorf("Unexpected latest artifact: %+v", artifact)
+	}
+
+	w = serve("GET", base+"/notes.txt/versions/0", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &artifact); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Failed to get artifact version %d: %v", w.Code, err)
+	}
+	if string(artifact.Content) != "first" {
+		t.Errorf("Expected version 0 content, got %q", artifact.Content)
+	}
+
+	if w := serve("GET", base+"/notes.txt/versions/7", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing version, got %d", w.Code)
+	}
+	if w := serve("DELETE", base+"/notes.txt", ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 on delete, got %d", w.Code)
+	}
+	if w := serve("GET", base+"/notes.txt", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 after delete, got %d", w.Code)
+	}
+}
+
+func TestUsageRoutes(t *testing.T) {
+	sessionService := sessions.NewInMemorySessionService()
+	config := &ServerConfig{AgentsDir: "test-agents"}
+	server := &Server{
+		config:         config,
+		sessionService: sessionService,
+		agentLoader:    utils.NewAgentLoader(config.AgentsDir),
+		runnerCache:    make(map[string]*runners.RunnerImpl),
+	}
+	server.setupRoutes()
+
+	ctx := context.Background()
+	usages := map[string][]*core.Usage{
+		"s1": {
+			{InputTokens: 10, OutputTokens: 5, TotalTokens: 15, LLMCalls: 1},
+			{InputTokens: 20, OutputTokens: 7, CachedTokens: 4, TotalTokens: 27, LLMCalls: 1},
+		},
+		"s2": {
+			{InputTokens: 3, OutputTokens: 2, TotalTokens: 5, LLMCalls: 1},
+		},
+	}
+	for sessionID, sessionUsages := range usages {
+		session, err := sessionService.CreateSession(ctx, &core.CreateSessionRequest{
+			AppName: "app", UserID: "alice", SessionID: ptr.Ptr(sessionID),
+		})
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		for i, usage := range sessionUsages {
+			event := core.NewEvent("inv-"+sessionID+"-"+string(rune('a'+i)), "agent")
+			event.Usage = usage
+			if err := sessionService.AppendEvent(ctx, session, event); err != nil {
+				t.Fatalf("Failed to append event: %v", err)
+			}
+		}
+		// Events without usage (e.g. user messages) are ignored
+		sessionService.AppendEvent(ctx, session, core.NewEvent("inv-user", "user"))
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	var summary core.UsageSummary
+	w := serve("/apps/app/users/alice/sessions/s1/usage")
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Failed to get session usage %d: %v", w.Code, err)
+	}
+	if summary.Total.InputTokens != 30 || summary.Total.OutputTokens != 12 || summary.Total.CachedTokens != 4 ||
+		summary.Total.LLMCalls != 2 || len(summary.ByInvocation) != 2 {
+		t.Errorf("Unexpected session usage: %+v", summary)
+	}
+
+	var user UserUsageResponse
+	w = serve("/apps/app/users/alice/usage")
+	if err := json.Unmarshal(w.Body.Bytes(), &user); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Failed to get user usage %d: %v", w.Code, err)
+	}
+	if user.Total.TotalTokens != 47 || user.Total.LLMCalls != 3 || user.BySession["s2"].TotalTokens != 5 {
+		t.Errorf("Unexpected user usage: %+v", user)
+	}
+
+	if w := serve("/apps/app/users/alice/sessions/missing/usage"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing session, got %d", w.Code)
+	}
+}
+
+func TestVisibleEvent(t *testing.T) {
+	thought := core.NewEvent("inv", "agent")
+	thought.Content = &core.Content{Role: "model", Parts: []core.Part{core.NewThoughtPart("Hmm.")}}
+	thought.Partial = ptr.Ptr(true)
+	if visibleEvent(thought) != nil {
+		t.Error("Expected a partial event with only thoughts to be hidden")
+	}
+
+	final := core.NewEvent("inv", "agent")
+	final.Content = &core.Content{Role: "model", Parts: []core.Part{core.NewThoughtPart("Hmm."), {Type: "text", Text: ptr.Ptr("Done")}}}
+	visible := visibleEvent(final)
+	if visible == nil || len(visible.Content.Parts) != 1 || *visible.Content.Parts[0].Text != "Done" {
+		t.Errorf("Expected only the answer to remain, got %+v", visible)
+	}
+	if len(final.Content.Parts) != 2 {
+		t.Error("Expected the original event to be left untouched")
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// UserUsageResponse aggregates the LLM usage of all sessions of a user
+type UserUsageResponse struct {
+	AppName   string                 `json:"app_name"`
+	UserID    string                 `json:"user_id"`
+	Total     core.Usage             `json:"total"`
+	BySession map[string]*core.Usage `json:"by_session"`
+}
+
+// setupUsageRoutes configures token usage routes
+func (s *Server) setupUsageRoutes() {
+	s.router.HandleFunc("GET /apps/{app_name}/users/{user_id}/usage", s.wrapGetUserUsage)
+	s.router.HandleFunc("GET /apps/{app_name}/users/{user_id}/sessions/{session_id}/usage", s.wrapGetSessionUsage)
+}
+
+func (s *Server) wrapGetUserUsage(w http.ResponseWriter, r *http.Request) {
+	s.handleGetUserUsage(w, r, r.PathValue("app_name"), r.PathValue("user_id"))
+}
+
+func (s *Server) wrapGetSessionUsage(w http.ResponseWriter, r *http.Request) {
+	s.handleGetSessionUsage(w, r, r.PathValue("app_name"), r.PathValue("user_id"), r.PathValue("session_id"))
+}
+
+// handleGetSessionUsage returns the usage of a session, in total and per invocation
+func (s *Server) handleGetSessionUsage(w http.ResponseWriter, r *http.Request, appName, userID, sessionID string) {
+	session, err := s.sessionService.GetSession(r.Context(), &core.GetSessionRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil || session == nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, core.SummarizeUsage(session.Events))
+}
+
+// handleGetUserUsage returns the usage of all sessions of a user
+func (s *Server) handleGetUserUsage(w http.ResponseWriter, r *http.Request, appName, userID string) {
+	list, err := s.sessionService.ListSessions(r.Context(), &core.ListSessionsRequest{
+		AppName: appName,
+		UserID:  userID,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list sessions: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := &UserUsageResponse{
+		AppName:   appName,
+		UserID:    userID,
+		BySession: make(map[string]*core.Usage),
+	}
+	for _, listed := range list.Sessions {
+		// Listings omit events, so each session is loaded in full
+		session, err := s.sessionService.GetSession(r.Context(), &core.GetSessionRequest{
+			AppName:   appName,
+			UserID:    userID,
+			SessionID: listed.ID,
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get session %s: %v", listed.ID, err), http.StatusInternalServerError)
+			return
+		}
+		if session == nil {
+			continue
+		}
+
+		summary := core.SummarizeUsage(session.Events)
+		response.Total.Add(&summary.Total)
+		response.BySession[session.ID] = &summary.Total
+	}
+
+	writeJSON(w, response)
+}
@@ -0,0 +1,214 @@
+package artifacts
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+func TestInMemoryArtifactService(t *testing.T) {
+	testArtifactService(t, NewInMemoryArtifactService())
+}
+
+func TestFileArtifactService(t *testing.T) {
+	service, err := NewFileArtifactService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create file artifact service: %v", err)
+	}
+	testArtifactService(t, service)
+}
+
+func TestFileArtifactService_Persistence(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	service, err := NewFileArtifactService(dir)
+	if err != nil {
+		t.Fatalf("Failed to create file artifact service: %v", err)
+	}
+	if _, err := service.SaveArtifact(ctx, &core.SaveArtifactRequest{
+		AppName: "app", UserID: "user", SessionID: "s1",
+		Filename: "reports/summary.txt", Content: []byte("hello"), MimeType: "text/plain",
+	}); err != nil {
+		t.Fatalf("SaveArtifact failed: %v", err)
+	}
+
+	reopened, err := NewFileArtifactService(dir)
+	if err != nil {
+		t.Fatalf("Failed to reopen file artifact service: %v", err)
+	}
+	artifact, err := reopened.GetArtifact(ctx, &core.LoadArtifactRequest{
+		AppName: "app", UserID: "user", SessionID: "s1", Filename: "reports/summary.txt",
+	})
+	if err != nil || artifact == nil {
+		t.Fatalf("GetArtifact after reopen: artifact=%v err=%v", artifact, err)
+	}
+	if string(artifact.Content) != "hello" || artifact.MimeType != "text/plain" || artifact.Size != 5 {
+		t.Errorf("Unexpected artifact after reopen: %+v", artifact)
+	}
+
+	keys, err := reopened.ListArtifactKeys(ctx, &core.ListArtifactKeysRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("ListArtifactKeys failed: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"reports/summary.txt"}) {
+		t.Errorf("Expected escaped filename to round-trip, got %v", keys)
+	}
+}
+
+func TestNewArtifactServiceFromURI(t *testing.T) {
+	if service, err := NewArtifactServiceFromURI(""); err != nil {
+		t.Errorf("Empty URI failed: %v", err)
+	} else if _, ok := service.(*InMemoryArtifactService); !ok {
+		t.Errorf("Expected in-memory service for empty URI, got %T", service)
+	}
+
+	service, err := NewArtifactServiceFromURI("file://" + t.TempDir())
+	if err != nil {
+		t.Fatalf("file URI failed: %v", err)
+	}
+	if _, ok := service.(*FileArtifactService); !ok {
+		t.Errorf("Expected file service, got %T", service)
+	}
+
+	if _, err := NewArtifactServiceFromURI("gs://bucket"); err == nil {
+		t.Error("Expected error for unsupported scheme")
+	}
+}
+
+// testArtifactService runs the behaviour every ArtifactService implementation must provide.
+func testArtifactService(t *testing.T, service ArtifactService) {
+	ctx := context.Background()
+	save := func(sessionID, filename, content, mimeType string) int {
+		t.Helper()
+		version, err := service.SaveArtifact(ctx, &core.SaveArtifactRequest{
+			AppName: "app", UserID: "user", SessionID: sessionID,
+			Filename: filename, Content: []byte(content), MimeType: mimeType,
+		})
+		if err != nil {
+			t.Fatalf("SaveArtifact(%s) failed: %v", filename, err)
+		}
+		return version
+	}
+
+	t.Run("Versioning", func(t *testing.T) {
+		if v := save("s1", "doc.txt", "first", "text/plain"); v != 0 {
+			t.Errorf("Expected first version 0, got %d", v)
+		}
+		if v := save("s1", "doc.txt", "second", "text/markdown"); v != 1 {
+			t.Errorf("Expected second version 1, got %d", v)
+		}
+
+		latest, err := service.LoadArtifact(ctx, &core.LoadArtifactRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "doc.txt",
+		})
+		if err != nil || string(latest) != "second" {
+			t.Errorf("Expected latest content 'second', got %q (err=%v)", latest, err)
+		}
+
+		first, err := service.GetArtifact(ctx, &core.LoadArtifactRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "doc.txt", Version: ptr.Ptr(0),
+		})
+		if err != nil || first == nil {
+			t.Fatalf("GetArtifact version 0: artifact=%v err=%v", first, err)
+		}
+		if string(first.Content) != "first" || first.MimeType != "text/plain" || first.Version != 0 {
+			t.Errorf("Unexpected version 0: %+v", first)
+		}
+
+		versions, err := service.ListVersions(ctx, &core.ListVersionsRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "doc.txt",
+		})
+		if err != nil || !reflect.DeepEqual(versions, []int{0, 1}) {
+			t.Errorf("Expected versions [0 1], got %v (err=%v)", versions, err)
+		}
+	})
+
+	t.Run("DefaultMimeType", func(t *testing.T) {
+		save("s1", "blob", "data", "")
+		artifact, err := service.GetArtifact(ctx, &core.LoadArtifactRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "blob",
+		})
+		if err != nil || artifact == nil || artifact.MimeType != DefaultMimeType {
+			t.Errorf("Expected default mime type, got %+v (err=%v)", artifact, err)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		content, err := service.LoadArtifact(ctx, &core.LoadArtifactRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "missing",
+		})
+		if err != nil || content != nil {
+			t.Errorf("Expected (nil, nil) for missing artifact, got %q, %v", content, err)
+		}
+
+		artifact, err := service.GetArtifact(ctx, &core.LoadArtifactRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "doc.txt", Version: ptr.Ptr(99),
+		})
+		if err != nil || artifact != nil {
+			t.Errorf("Expected (nil, nil) for missing version, got %v, %v", artifact, err)
+		}
+	})
+
+	t.Run("Scoping", func(t *testing.T) {
+		save("s2", "other.txt", "other", "text/plain")
+		save("s2", "user:profile.json", "{}", "application/json")
+
+		keys, err := service.ListArtifactKeys(ctx, &core.ListArtifactKeysRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+		if err != nil {
+			t.Fatalf("ListArtifactKeys failed: %v", err)
+		}
+		expected := []string{"blob", "doc.txt", "user:profile.json"}
+		if !reflect.DeepEqual(keys, expected) {
+			t.Errorf("Expected keys %v, got %v", expected, keys)
+		}
+
+		// User-scoped artifacts are visible from every session of the user
+		content, err := service.LoadArtifact(ctx, &core.LoadArtifactRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "user:profile.json",
+		})
+		if err != nil || string(content) != "{}" {
+			t.Errorf("Expected user-scoped artifact from another session, got %q (err=%v)", content, err)
+		}
+
+		keys, err = service.ListArtifactKeys(ctx, &core.ListArtifactKeysRequest{AppName: "app", UserID: "someone-else", SessionID: "s1"})
+		if err != nil || len(keys) != 0 {
+			t.Errorf("Expected no keys for another user, got %v (err=%v)", keys, err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := service.DeleteArtifact(ctx, &core.DeleteArtifactRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "doc.txt",
+		})
+		if err != nil {
+			t.Fatalf("DeleteArtifact failed: %v", err)
+		}
+
+		versions, err := service.ListVersions(ctx, &core.ListVersionsRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "doc.txt",
+		})
+		if err != nil || len(versions) != 0 {
+			t.Errorf("Expected no versions after delete, got %v (err=%v)", versions, err)
+		}
+	})
+
+	t.Run("InvalidIdentifiers", func(t *testing.T) {
+		_, err := service.SaveArtifact(ctx, &core.SaveArtifactRequest{
+			AppName: "app", UserID: "../user", SessionID: "s1", Filename: "x",
+		})
+		if err == nil {
+			t.Error("Expected error for path traversal user ID")
+		}
+
+		_, err = service.SaveArtifact(ctx, &core.SaveArtifactRequest{
+			AppName: "app", UserID: "user", SessionID: "s1", Filename: "..",
+		})
+		if err == nil {
+			t.Error("Expected error for path traversal filename")
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// FileArtifactService stores artifacts on the local filesystem.
+//
+// Layout:
+//
+//	<base>/<app>/<user>/sessions/<session>/artifacts/<filename>/<version>.bin
+//	<base>/<app>/<user>/user/artifacts/<filename>/<version>.bin
+//
+// Each version has a <version>.json file next to it holding its metadata.
+// Filenames are path-escaped so they always map to a single directory.
+type FileArtifactService struct {
+	baseDir string
+	mu      sync.RWMutex
+}
+
+var _ ArtifactService = (*FileArtifactService)(nil)
+
+// NewFileArtifactService creates a new file-based artifact service rooted at baseDir.
+func NewFileArtifactService(baseDir string) (*FileArtifactService, error) {
+	if baseDir == "" {
+		return nil, fmt.Errorf("artifact base directory is required")
+	}
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	return &FileArtifactService{baseDir: baseDir}, nil
+}
+
+// artifactDir returns the directory holding all versions of an artifact.
+func (s *FileArtifactService) artifactDir(appName, userID, sessionID, filename string) string {
+	return filepath.Join(s.artifactsRoot(appName, userID, sessionID, isUserScoped(filename)), url.PathEscape(filename))
+}
+
+// artifactsRoot returns the directory holding the session or user scoped artifacts.
+func (s *FileArtifactService) artifactsRoot(appName, userID, sessionID string, userScoped bool) string {
+	if userScoped {
+		return filepath.Join(s.baseDir, appName, userID, "user", "artifacts")
+	}
+	return filepath.Join(s.baseDir, appName, userID, "sessions", sessionID, "artifacts")
+}
+
+// SaveArtifact stores a new version of an artifact and returns its version number.
+func (s *FileArtifactService) SaveArtifact(ctx context.Context, req *core.SaveArtifactRequest) (int, error) {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return 0, err
+	}
+	if err := validateFilename(req.Filename); err != nil {
+		return 0, err
+	}
+
+	mimeType := req.MimeType
+	if mimeType == "" {
+		mimeType = DefaultMimeType
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := s.artifactDir(req.AppName, req.UserID, req.SessionID, req.Filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	versions, err := listVersionsInDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	if len(versions) > 0 {
+		version = versions[len(versions)-1] + 1
+	}
+
+	if err := writeFileAtomic(filepath.Join(dir, strconv.Itoa(version)+".bin"), req.Content); err != nil {
+		return 0, fmt.Errorf("failed to write artifact: %w", err)
+	}
+
+	metadata, err := json.MarshalIndent(&Artifact{
+		Filename:   req.Filename,
+		Version:    version,
+		MimeType:   mimeType,
+		Size:       len(req.Content),
+		CreateTime: time.Now(),
+	}, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal artifact metadata: %w", err)
+	}
+	// The metadata file is written last; a version only exists once it is present.
+	if err := writeFileAtomic(filepath.Join(dir, strconv.Itoa(version)+".json"), metadata); err != nil {
+		return 0, fmt.Errorf("failed to write artifact metadata: %w", err)
+	}
+
+	return version, nil
+}
+
+// LoadArtifact returns the content of an artifact version, or the latest version
+// when none is given. It returns (nil, nil) when the artifact does not exist.
+func (s *FileArtifactService) LoadArtifact(ctx context.Context, req *core.LoadArtifactRequest) ([]byte, error) {
+	artifact, err := s.GetArtifact(ctx, req)
+	if err != nil || artifact == nil {
+		return nil, err
+	}
+	return artifact.Content, nil
+}
+
+// GetArtifact returns an artifact version together with its metadata.
+func (s *FileArtifactService) GetArtifact(ctx context.Context, req *core.LoadArtifactRequest) (*Artifact, error) {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return nil, err
+	}
+	if err := validateFilename(req.Filename); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dir := s.artifactDir(req.AppName, req.UserID, req.SessionID, req.Filename)
+	versions, err := listVersionsInDir(dir)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+
+	version := versions[len(versions)-1]
+	if req.Version != nil {
+		version = *req.Version
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, strconv.Itoa(version)+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read artifact metadata: %w", err)
+	}
+
+	var artifact Artifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal artifact metadata: %w", err)
+	}
+
+	artifact.Content, err = os.ReadFile(filepath.Join(dir, strconv.Itoa(version)+".bin"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+
+	return &artifact, nil
+}
+
+// ListArtifactKeys returns the sorted filenames visible from a session,
+// including user-scoped artifacts.
+func (s *FileArtifactService) ListArtifactKeys(ctx context.Context, req *core.ListArtifactKeysRequest) ([]string, error) {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0)
+	for _, userScoped := range []bool{false, true} {
+		root := s.artifactsRoot(req.AppName, req.UserID, req.SessionID, userScoped)
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read artifact directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			filename, err := url.PathUnescape(entry.Name())
+			if err != nil {
+				continue
+			}
+			versions, err := listVersionsInDir(filepath.Join(root, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if len(versions) > 0 {
+				keys = append(keys, filename)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// DeleteArtifact removes all versions of an artifact.
+func (s *FileArtifactService) DeleteArtifact(ctx context.Context, req *core.DeleteArtifactRequest) error {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return err
+	}
+	if err := validateFilename(req.Filename); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.RemoveAll(s.artifactDir(req.AppName, req.UserID, req.SessionID, req.Filename)); err != nil {
+		return fmt.Errorf("failed to delete artifact: %w", err)
+	}
+	return nil
+}
+
+// ListVersions returns all version numbers of an artifact in ascending order.
+func (s *FileArtifactService) ListVersions(ctx context.Context, req *core.ListVersionsRequest) ([]int, error) {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return nil, err
+	}
+	if err := validateFilename(req.Filename); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, err := listVersionsInDir(s.artifactDir(req.AppName, req.UserID, req.SessionID, req.Filename))
+	if err != nil {
+		return nil, err
+	}
+	if versions == nil {
+		versions = make([]int, 0)
+	}
+	return versions, nil
+}
+
+// listVersionsInDir returns the sorted versions that have a metadata file in dir.
+func listVersionsInDir(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read artifact directory: %w", err)
+	}
+
+	var versions []int
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if version, err := strconv.Atoi(name); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	sort.Ints(versions)
+
+	return versions, nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place.
+func writeFileAtomic(path string, data []byte) error {
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Package artifacts provides artifact storage implementations.
+// Artifacts are versioned binary blobs (files) produced or consumed by tools.
+// Every save creates a new version, starting at 0. Filenames prefixed with
+// "user:" are scoped to the user and visible from all of the user's sessions.
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// UserScopePrefix marks an artifact filename as user-scoped rather than session-scoped.
+const UserScopePrefix = "user:"
+
+// DefaultMimeType is used when an artifact is saved without a mime type.
+const DefaultMimeType = "application/octet-stream"
+
+// ArtifactService extends the core ArtifactService with metadata access.
+type ArtifactService interface {
+	core.ArtifactService
+
+	// GetArtifact returns an artifact version together with its metadata.
+	// It returns (nil, nil) when the artifact or version does not exist.
+	GetArtifact(ctx context.Context, req *core.LoadArtifactRequest) (*Artifact, error)
+}
+
+// Artifact is a single stored version of an artifact.
+type Artifact struct {
+	Filename   string    `json:"filename"`
+	Version    int       `json:"version"`
+	MimeType   string    `json:"mime_type"`
+	Size       int       `json:"size"`
+	CreateTime time.Time `json:"create_time"`
+	Content    []byte    `json:"data,omitempty"`
+}
+
+// isUserScoped reports whether the filename refers to a user-scoped artifact.
+func isUserScoped(filename string) bool {
+	return strings.HasPrefix(filename, UserScopePrefix)
+}
+
+// validateIDs rejects app, user and session identifiers that are empty or could
+// escape a storage directory.
+func validateIDs(appName, userID, sessionID string) error {
+	for _, id := range []string{appName, userID, sessionID} {
+		if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+			return fmt.Errorf("invalid artifact scope identifier: %q", id)
+		}
+	}
+	return nil
+}
+
+// validateFilename rejects empty filenames and path traversal names.
+func validateFilename(filename string) error {
+	name := strings.TrimPrefix(filename, UserScopePrefix)
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("invalid artifact filename: %q", filename)
+	}
+	return nil
+}
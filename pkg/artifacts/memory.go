@@ -0,0 +1,164 @@
+package artifacts
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// InMemoryArtifactService is an in-memory implementation of ArtifactService.
+// It is intended for testing and development; artifacts are lost on restart.
+type InMemoryArtifactService struct {
+	mu        sync.RWMutex
+	artifacts map[string][]*Artifact // path -> versions
+}
+
+var _ ArtifactService = (*InMemoryArtifactService)(nil)
+
+// NewInMemoryArtifactService creates a new in-memory artifact service.
+func NewInMemoryArtifactService() *InMemoryArtifactService {
+	return &InMemoryArtifactService{
+		artifacts: make(map[string][]*Artifact),
+	}
+}
+
+// artifactPath returns the storage key for an artifact.
+func artifactPath(appName, userID, sessionID, filename string) string {
+	if isUserScoped(filename) {
+		return appName + "/" + userID + "/user/" + filename
+	}
+	return appName + "/" + userID + "/sessions/" + sessionID + "/" + filename
+}
+
+// SaveArtifact stores a new version of an artifact and returns its version number.
+func (s *InMemoryArtifactService) SaveArtifact(ctx context.Context, req *core.SaveArtifactRequest) (int, error) {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return 0, err
+	}
+	if err := validateFilename(req.Filename); err != nil {
+		return 0, err
+	}
+
+	mimeType := req.MimeType
+	if mimeType == "" {
+		mimeType = DefaultMimeType
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := artifactPath(req.AppName, req.UserID, req.SessionID, req.Filename)
+	version := len(s.artifacts[path])
+	content := make([]byte, len(req.Content))
+	copy(content, req.Content)
+
+	s.artifacts[path] = append(s.artifacts[path], &Artifact{
+		Filename:   req.Filename,
+		Version:    version,
+		MimeType:   mimeType,
+		Size:       len(content),
+		CreateTime: time.Now(),
+		Content:    content,
+	})
+
+	return version, nil
+}
+
+// LoadArtifact returns the content of an artifact version, or the latest version
+// when none is given. It returns (nil, nil) when the artifact does not exist.
+func (s *InMemoryArtifactService) LoadArtifact(ctx context.Context, req *core.LoadArtifactRequest) ([]byte, error) {
+	artifact, err := s.GetArtifact(ctx, req)
+	if err != nil || artifact == nil {
+		return nil, err
+	}
+	return artifact.Content, nil
+}
+
+// GetArtifact returns an artifact version together with its metadata.
+func (s *InMemoryArtifactService) GetArtifact(ctx context.Context, req *core.LoadArtifactRequest) (*Artifact, error) {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := s.artifacts[artifactPath(req.AppName, req.UserID, req.SessionID, req.Filename)]
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	version := len(versions) - 1
+	if req.Version != nil {
+		version = *req.Version
+	}
+	if version < 0 || version >= len(versions) {
+		return nil, nil
+	}
+
+	artifact := *versions[version]
+	artifact.Content = make([]byte, len(versions[version].Content))
+	copy(artifact.Content, versions[version].Content)
+	return &artifact, nil
+}
+
+// ListArtifactKeys returns the sorted filenames visible from a session,
+// including user-scoped artifacts.
+func (s *InMemoryArtifactService) ListArtifactKeys(ctx context.Context, req *core.ListArtifactKeysRequest) ([]string, error) {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessionPrefix := req.AppName + "/" + req.UserID + "/sessions/" + req.SessionID + "/"
+	userPrefix := req.AppName + "/" + req.UserID + "/user/"
+
+	keys := make([]string, 0)
+	for path, versions := range s.artifacts {
+		if len(versions) == 0 {
+			continue
+		}
+		if strings.HasPrefix(path, sessionPrefix) || strings.HasPrefix(path, userPrefix) {
+			keys = append(keys, versions[0].Filename)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// DeleteArtifact removes all versions of an artifact.
+func (s *InMemoryArtifactService) DeleteArtifact(ctx context.Context, req *core.DeleteArtifactRequest) error {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.artifacts, artifactPath(req.AppName, req.UserID, req.SessionID, req.Filename))
+	return nil
+}
+
+// ListVersions returns all version numbers of an artifact in ascending order.
+func (s *InMemoryArtifactService) ListVersions(ctx context.Context, req *core.ListVersionsRequest) ([]int, error) {
+	if err := validateIDs(req.AppName, req.UserID, req.SessionID); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := s.artifacts[artifactPath(req.AppName, req.UserID, req.SessionID, req.Filename)]
+	result := make([]int, len(versions))
+	for i := range versions {
+		result[i] = i
+	}
+	return result, nil
+}
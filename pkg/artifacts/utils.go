@@ -0,0 +1,29 @@
+package artifacts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NewArtifactServiceFromURI creates an artifact service from an --artifact-service-uri value.
+// Supported forms are:
+//
+//	""                        in-memory storage
+//	memory://                 in-memory storage
+//	file:///path/to/dir       versioned local-disk storage
+func NewArtifactServiceFromURI(uri string) (ArtifactService, error) {
+	scheme, rest, found := strings.Cut(uri, "://")
+	if uri == "" || (found && scheme == "memory") {
+		return NewInMemoryArtifactService(), nil
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid artifact service URI: %s", uri)
+	}
+
+	switch strings.ToLower(scheme) {
+	case "file":
+		return NewFileArtifactService(rest)
+	default:
+		return nil, fmt.Errorf("unsupported artifact service URI scheme: %s", scheme)
+	}
+}
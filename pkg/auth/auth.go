@@ -0,0 +1,204 @@
+// Package auth provides credential storage and the auth-request flow for tools.
+//
+// A tool that needs credentials calls ToolContext.RequestCredential with an
+// AuthConfig. The runner then pauses the invocation and emits an event with an
+// adk_request_credential function call carrying the AuthConfig. The client
+// completes authorization and posts back a function response with the same
+// name; the runner turns it into a Credential, stores it and resumes.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// RequestCredentialFunctionName is the function call emitted when the runner
+// pauses for credentials, and the function response name used to resume.
+const RequestCredentialFunctionName = "adk_request_credential"
+
+// Supported auth schemes.
+const (
+	SchemeOAuth2     = "oauth2"
+	SchemeAPIKey     = "api_key"
+	SchemeHTTPBearer = "http_bearer"
+)
+
+// Supported OAuth2 grant types.
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+)
+
+// AuthRequest is the argument of an adk_request_credential function call and
+// the response posted back by the client.
+type AuthRequest struct {
+	CredentialID string          `json:"credential_id"`
+	AuthConfig   core.AuthConfig `json:"auth_config"`
+}
+
+// NeedsUserInteraction reports whether the auth config can only be completed by
+// the client. Client-credentials OAuth2 is resolved by the runner directly.
+func NeedsUserInteraction(config core.AuthConfig) bool {
+	if config.Scheme != SchemeOAuth2 {
+		return true
+	}
+	return configString(config.Config, "grant_type") != GrantClientCredentials
+}
+
+// RedactAuthConfig returns a copy of the auth config without its secrets, the
+// OAuth2 client secret and any credential, so that it can be stored in session
+// history or sent to the client.
+func RedactAuthConfig(config core.AuthConfig) core.AuthConfig {
+	redacted := core.AuthConfig{
+		Scheme: config.Scheme,
+		Config: make(map[string]any, len(config.Config)),
+	}
+	for key, value := range config.Config {
+		if key == "client_secret" {
+			continue
+		}
+		redacted.Config[key] = value
+	}
+	return redacted
+}
+
+// PrepareAuthRequest returns a copy of the auth config ready to send to the
+// client. For the OAuth2 authorization-code grant it adds an authorization_url
+// and the state parameter expected on the redirect.
+func PrepareAuthRequest(config core.AuthConfig) (core.AuthConfig, error) {
+	// Secrets stay on the server
+	prepared := RedactAuthConfig(config)
+
+	if config.Scheme != SchemeOAuth2 {
+		return prepared, nil
+	}
+
+	oauthConfig, err := NewOAuth2Config(config)
+	if err != nil {
+		return prepared, err
+	}
+	state, err := randomState()
+	if err != nil {
+		return prepared, err
+	}
+	prepared.Config["state"] = state
+	prepared.Config["authorization_url"] = oauthConfig.AuthorizationURL(state)
+
+	return prepared, nil
+}
+
+// CredentialFromAuthResponse turns the auth config posted back by the client
+// into a credential. requested is the config the tool originally asked for,
+// kept on the server; it supplies secrets such as the OAuth2 client secret and,
+// for the authorization-code grant, the state sent to the client. The client
+// must return that state with the code, in the auth_response_uri or next to a
+// bare code.
+func CredentialFromAuthResponse(ctx context.Context, credentialID string, requested, response core.AuthConfig) (*core.Credential, error) {
+	data, err := credentialData(response.Credential)
+	if err != nil {
+		return nil, err
+	}
+
+	switch requested.Scheme {
+	case SchemeOAuth2:
+		oauthConfig, err := NewOAuth2Config(requested)
+		if err != nil {
+			return nil, err
+		}
+
+		// The client may have exchanged the code itself
+		if token := configString(data, "access_token"); token != "" {
+			return oauthConfig.credentialFromToken(credentialID, data)
+		}
+
+		code, state := configString(data, "code"), configString(data, "state")
+		if redirect := configString(data, "auth_response_uri"); redirect != "" {
+			parsed, err := url.Parse(redirect)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth_response_uri: %w", err)
+			}
+			code, state = parsed.Query().Get("code"), parsed.Query().Get("state")
+		}
+		if code == "" {
+			return nil, fmt.Errorf("auth response contains neither an access token nor an authorization code")
+		}
+		// Every authorization code must come back with the state the server issued
+		expected := configString(requested.Config, "state")
+		if expected == "" || subtle.ConstantTimeCompare([]byte(state), []byte(expected)) != 1 {
+			return nil, fmt.Errorf("OAuth2 state mismatch")
+		}
+		return oauthConfig.Exchange(ctx, credentialID, code)
+
+	case SchemeAPIKey, SchemeHTTPBearer:
+		if len(data) == 0 {
+			return nil, fmt.Errorf("auth response contains no credential")
+		}
+		return &core.Credential{ID: credentialID, Type: requested.Scheme, Data: data}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported auth scheme: %s", requested.Scheme)
+	}
+}
+
+// credentialData converts the loosely typed AuthConfig.Credential into a map.
+func credentialData(credential any) (map[string]any, error) {
+	switch value := credential.(type) {
+	case nil:
+		return map[string]any{}, nil
+	case map[string]any:
+		return value, nil
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credential: %w", err)
+		}
+		result := make(map[string]any)
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("invalid credential: %w", err)
+		}
+		return result, nil
+	}
+}
+
+// configString returns a string value from a loosely typed map.
+func configString(config map[string]any, key string) string {
+	value, _ := config[key].(string)
+	return value
+}
+
+// configStrings returns a string list from a loosely typed map. Space separated
+// strings are accepted as well.
+func configStrings(config map[string]any, key string) []string {
+	switch value := config[key].(type) {
+	case []string:
+		return value
+	case []any:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	case string:
+		return strings.Fields(value)
+	default:
+		return nil
+	}
+}
+
+func randomState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+func TestEncryptedFileCredentialService(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	salt, err := LoadOrCreateSalt(dir)
+	if err != nil {
+		t.Fatalf("Failed to create salt: %v", err)
+	}
+	if again, _ := LoadOrCreateSalt(dir); string(again) != string(salt) {
+		t.Error("Expected the stored salt to be reused")
+	}
+	key, err := KeyFromPassphrase("correct horse", salt)
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+	service, err := NewEncryptedFileCredentialService(dir, key)
+	if err != nil {
+		t.Fatalf("Failed to create credential service: %v", err)
+	}
+
+	credential := &core.Credential{ID: "github", Type: SchemeHTTPBearer, Data: map[string]any{"token": "s3cr3t-token"}}
+	if err := service.StoreCredential(ctx, &core.StoreCredentialRequest{AppName: "app", UserID: "alice", Credential: credential}); err != nil {
+		t.Fatalf("StoreCredential failed: %v", err)
+	}
+
+	// The secret is not stored in plain text
+	data, err := os.ReadFile(filepath.Join(dir, "app", "alice", "github.cred"))
+	if err != nil {
+		t.Fatalf("Failed to read credential file: %v", err)
+	}
+	if strings.Contains(string(data), "s3cr3t-token") {
+		t.Error("Credential is stored unencrypted")
+	}
+
+	loaded, err := service.GetCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "alice", CredentialID: "github"})
+	if err != nil || loaded == nil || loaded.Data["token"] != "s3cr3t-token" {
+		t.Errorf("Expected credential to round-trip, got %+v (err=%v)", loaded, err)
+	}
+
+	missing, err := service.GetCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "bob", CredentialID: "github"})
+	if err != nil || missing != nil {
+		t.Errorf("Expected (nil, nil) for another user, got %+v, %v", missing, err)
+	}
+
+	// The store can be moved, but files cannot be swapped between users
+	moved := filepath.Join(t.TempDir(), "moved")
+	if err := os.Rename(dir, moved); err != nil {
+		t.Fatalf("Failed to move credential store: %v", err)
+	}
+	dir = moved
+	service, _ = NewEncryptedFileCredentialService(dir, key)
+	loaded, err = service.GetCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "alice", CredentialID: "github"})
+	if err != nil || loaded == nil || loaded.Data["token"] != "s3cr3t-token" {
+		t.Errorf("Expected credential to survive moving the store, got %+v (err=%v)", loaded, err)
+	}
+	os.MkdirAll(filepath.Join(dir, "app", "bob"), 0700)
+	os.WriteFile(filepath.Join(dir, "app", "bob", "github.cred"), data, 0600)
+	if _, err := service.GetCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "bob", CredentialID: "github"}); err == nil {
+		t.Error("Expected a credential copied to another user to fail decryption")
+	}
+
+	wrongSalt, _ := KeyFromPassphrase("correct horse", []byte("another-salt-value"))
+	if wrongSalt == nil || string(wrongSalt) == string(key) {
+		t.Error("Expected a different salt to derive a different key")
+	}
+	wrong, _ := KeyFromPassphrase("wrong", salt)
+	wrongKey, _ := NewEncryptedFileCredentialService(dir, wrong)
+	if _, err := wrongKey.GetCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "alice", CredentialID: "github"}); err == nil {
+		t.Error("Expected decryption with the wrong key to fail")
+	}
+
+	if err := service.DeleteCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "alice", CredentialID: "github"}); err != nil {
+		t.Fatalf("DeleteCredential failed: %v", err)
+	}
+	if loaded, _ := service.GetCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "alice", CredentialID: "github"}); loaded != nil {
+		t.Error("Expected credential to be deleted")
+	}
+}
+
+// newTokenServer returns a token endpoint that issues numbered access tokens.
+func newTokenServer(t *testing.T) (*httptest.Server, *[]string) {
+	var grants []string
+	issued := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "client" || r.Form.Get("client_secret") != "secret" {
+			http.Error(w, "invalid client", http.StatusUnauthorized)
+			return
+		}
+		grants = append(grants, r.Form.Get("grant_type"))
+		issued++
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": 3600}`, issued)
+	}))
+	t.Cleanup(server.Close)
+	return server, &grants
+}
+
+func TestClientCredentialsAndRefresh(t *testing.T) {
+	ctx := context.Background()
+	server, grants := newTokenServer(t)
+
+	authConfig := core.AuthConfig{Scheme: SchemeOAuth2, Config: map[string]any{
+		"client_id":     "client",
+		"client_secret": "secret",
+		"token_url":     server.URL,
+		"grant_type":    GrantClientCredentials,
+		"scopes":        []any{"read"},
+	}}
+	if NeedsUserInteraction(authConfig) {
+		t.Error("Client credentials should not need user interaction")
+	}
+
+	oauthConfig, err := NewOAuth2Config(authConfig)
+	if err != nil {
+		t.Fatalf("NewOAuth2Config failed: %v", err)
+	}
+	credential, err := oauthConfig.ClientCredentials(ctx, "api")
+	if err != nil || credential.Data["access_token"] != "token-1" {
+		t.Fatalf("ClientCredentials: %+v (err=%v)", credential, err)
+	}
+
+	// Expire the credential; the refreshing service renews it on read
+	store := NewInMemoryCredentialService()
+	service := NewRefreshingCredentialService(store)
+	expired := time.Now().Add(-time.Minute)
+	credential.ExpiresAt = &expired
+	if err := service.StoreCredential(ctx, &core.StoreCredentialRequest{AppName: "app", UserID: "alice", Credential: credential}); err != nil {
+		t.Fatalf("StoreCredential failed: %v", err)
+	}
+
+	refreshed, err := service.GetCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "alice", CredentialID: "api"})
+	if err != nil || refreshed.Data["access_token"] != "token-2" || !refreshed.ExpiresAt.After(time.Now()) {
+		t.Fatalf("Expected refreshed credential, got %+v (err=%v)", refreshed, err)
+	}
+	stored, _ := store.GetCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "alice", CredentialID: "api"})
+	if stored.Data["access_token"] != "token-2" {
+		t.Error("Expected refreshed credential to be stored")
+	}
+
+	// A valid credential is returned without a token request
+	if _, err := service.GetCredential(ctx, &core.CredentialRequest{AppName: "app", UserID: "alice", CredentialID: "api"}); err != nil {
+		t.Fatalf("GetCredential failed: %v", err)
+	}
+	if len(*grants) != 2 {
+		t.Errorf("Expected 2 token requests, got %v", *grants)
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	server, grants := newTokenServer(t)
+	oauthConfig := &OAuth2Config{ClientID: "client", ClientSecret: "secret", TokenURL: server.URL, GrantType: GrantAuthorizationCode}
+
+	credential, err := oauthConfig.credentialFromToken("calendar", map[string]any{"access_token": "old", "refresh_token": "refresh-1"})
+	if err != nil {
+		t.Fatalf("credentialFromToken failed: %v", err)
+	}
+	refreshed, err := oauthConfig.Refresh(context.Background(), credential)
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if (*grants)[0] != "refresh_token" || refreshed.Data["access_token"] != "token-1" {
+		t.Errorf("Unexpected refresh: grants=%v credential=%+v", *grants, refreshed)
+	}
+	if refreshed.Data["refresh_token"] != "refresh-1" {
+		t.Error("Expected the refresh token to be kept when the server omits it")
+	}
+}
+
+func TestCredentialFromAuthResponse_APIKey(t *testing.T) {
+	requested := core.AuthConfig{Scheme: SchemeAPIKey}
+	response := core.AuthConfig{Scheme: SchemeAPIKey, Credential: map[string]any{"api_key": "k"}}
+
+	credential, err := CredentialFromAuthResponse(context.Background(), "weather", requested, response)
+	if err != nil || credential.Type != SchemeAPIKey || credential.Data["api_key"] != "k" {
+		t.Errorf("Unexpected credential %+v (err=%v)", credential, err)
+	}
+
+	if _, err := CredentialFromAuthResponse(context.Background(), "weather", requested, core.AuthConfig{}); err == nil {
+		t.Error("Expected error for empty credential")
+	}
+}
+
+func TestCredentialFromAuthResponse_OAuth2State(t *testing.T) {
+	server, grants := newTokenServer(t)
+	requested := core.AuthConfig{Scheme: SchemeOAuth2, Config: map[string]any{
+		"client_id":     "client",
+		"client_secret": "secret",
+		"auth_url":      "https://auth.example.com/authorize",
+		"token_url":     server.URL,
+		"state":         "issued",
+	}}
+	respond := func(credential map[string]any) error {
+		// The client controls the config it posts back, state included
+		response := core.AuthConfig{Scheme: SchemeOAuth2, Config: map[string]any{"state": "forged"}, Credential: credential}
+		_, err := CredentialFromAuthResponse(context.Background(), "calendar", requested, response)
+		return err
+	}
+
+	if err := respond(map[string]any{"auth_response_uri": "https://app.example.com/callback?code=c&state=forged"}); err == nil {
+		t.Error("Expected a redirect with a forged state to be rejected")
+	}
+	if err := respond(map[string]any{"code": "c"}); err == nil {
+		t.Error("Expected a bare code without state to be rejected")
+	}
+	if err := respond(map[string]any{"code": "c", "state": "forged"}); err == nil {
+		t.Error("Expected a bare code with a forged state to be rejected")
+	}
+	if len(*grants) != 0 {
+		t.Fatalf("Expected no code exchange for rejected responses, got %v", *grants)
+	}
+
+	if err := respond(map[string]any{"code": "c", "state": "issued"}); err != nil {
+		t.Errorf("Unexpected error for a bare code with the issued state: %v", err)
+	}
+	if err := respond(map[string]any{"auth_response_uri": "https://app.example.com/callback?code=c&state=issued"}); err != nil {
+		t.Errorf("Unexpected error for a redirect with the issued state: %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// OAuth2Config holds the OAuth2 client settings read from an AuthConfig.
+//
+// Recognised AuthConfig.Config keys are client_id, client_secret, auth_url,
+// token_url, redirect_uri, scopes and grant_type.
+type OAuth2Config struct {
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	AuthURL      string   `json:"auth_url,omitempty"`
+	TokenURL     string   `json:"token_url"`
+	RedirectURL  string   `json:"redirect_uri,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	GrantType    string   `json:"grant_type"`
+
+	// HTTPClient is used for token requests; http.DefaultClient when nil.
+	HTTPClient *http.Client `json:"-"`
+}
+
+// NewOAuth2Config reads OAuth2 settings from an auth config.
+func NewOAuth2Config(config core.AuthConfig) (*OAuth2Config, error) {
+	if config.Scheme != SchemeOAuth2 {
+		return nil, fmt.Errorf("auth scheme %q is not %s", config.Scheme, SchemeOAuth2)
+	}
+
+	oauthConfig := &OAuth2Config{
+		ClientID:     configString(config.Config, "client_id"),
+		ClientSecret: configString(config.Config, "client_secret"),
+		AuthURL:      configString(config.Config, "auth_url"),
+		TokenURL:     configString(config.Config, "token_url"),
+		RedirectURL:  configString(config.Config, "redirect_uri"),
+		Scopes:       configStrings(config.Config, "scopes"),
+		GrantType:    configString(config.Config, "grant_type"),
+	}
+	if oauthConfig.GrantType == "" {
+		oauthConfig.GrantType = GrantAuthorizationCode
+	}
+
+	if oauthConfig.ClientID == "" || oauthConfig.TokenURL == "" {
+		return nil, fmt.Errorf("OAuth2 config requires client_id and token_url")
+	}
+	if oauthConfig.GrantType == GrantAuthorizationCode && oauthConfig.AuthURL == "" {
+		return nil, fmt.Errorf("OAuth2 authorization code grant requires auth_url")
+	}
+
+	return oauthConfig, nil
+}
+
+// AuthorizationURL returns the URL the user visits to grant access.
+func (c *OAuth2Config) AuthorizationURL(state string) string {
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.ClientID},
+		"state":         {state},
+	}
+	if c.RedirectURL != "" {
+		params.Set("redirect_uri", c.RedirectURL)
+	}
+	if len(c.Scopes) > 0 {
+		params.Set("scope", strings.Join(c.Scopes, " "))
+	}
+
+	separator := "?"
+	if strings.Contains(c.AuthURL, "?") {
+		separator = "&"
+	}
+	return c.AuthURL + separator + params.Encode()
+}
+
+// Exchange trades an authorization code for a token credential.
+func (c *OAuth2Config) Exchange(ctx context.Context, credentialID, code string) (*core.Credential, error) {
+	form := url.Values{
+		"grant_type": {GrantAuthorizationCode},
+		"code":       {code},
+	}
+	if c.RedirectURL != "" {
+		form.Set("redirect_uri", c.RedirectURL)
+	}
+	return c.requestToken(ctx, credentialID, form)
+}
+
+// ClientCredentials obtains a token using the client-credentials grant.
+func (c *OAuth2Config) ClientCredentials(ctx context.Context, credentialID string) (*core.Credential, error) {
+	form := url.Values{"grant_type": {GrantClientCredentials}}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	return c.requestToken(ctx, credentialID, form)
+}
+
+// Refresh obtains a new token for an expired credential. Credentials without a
+// refresh token are renewed with the client-credentials grant if that is how
+// they were obtained.
+func (c *OAuth2Config) Refresh(ctx context.Context, credential *core.Credential) (*core.Credential, error) {
+	refreshToken := configString(credential.Data, "refresh_token")
+	if refreshToken == "" {
+		if c.GrantType == GrantClientCredentials {
+			return c.ClientCredentials(ctx, credential.ID)
+		}
+		return nil, fmt.Errorf("credential %s has no refresh token", credential.ID)
+	}
+
+	refreshed, err := c.requestToken(ctx, credential.ID, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Servers may omit the refresh token when it is unchanged
+	if configString(refreshed.Data, "refresh_token") == "" {
+		refreshed.Data["refresh_token"] = refreshToken
+	}
+	return refreshed, nil
+}
+
+// requestToken posts to the token endpoint and converts the response.
+func (c *OAuth2Config) requestToken(ctx context.Context, credentialID string, form url.Values) (*core.Credential, error) {
+	form.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var token map[string]any
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	return c.credentialFromToken(credentialID, token)
+}
+
+// credentialFromToken builds a credential from a token response. The client
+// settings needed for refresh are stored alongside the token.
+func (c *OAuth2Config) credentialFromToken(credentialID string, token map[string]any) (*core.Credential, error) {
+	accessToken := configString(token, "access_token")
+	if accessToken == "" {
+		return nil, fmt.Errorf("token response contains no access_token")
+	}
+
+	data := map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"token_url":    c.TokenURL,
+		"client_id":    c.ClientID,
+		"grant_type":   c.GrantType,
+	}
+	if tokenType := configString(token, "token_type"); tokenType != "" {
+		data["token_type"] = tokenType
+	}
+	if refreshToken := configString(token, "refresh_token"); refreshToken != "" {
+		data["refresh_token"] = refreshToken
+	}
+	if c.ClientSecret != "" {
+		data["client_secret"] = c.ClientSecret
+	}
+	if len(c.Scopes) > 0 {
+		data["scopes"] = c.Scopes
+	}
+
+	credential := &core.Credential{ID: credentialID, Type: SchemeOAuth2, Data: data}
+	if expiresIn, ok := token["expires_in"].(float64); ok && expiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(expiresIn) * time.Second)
+		credential.ExpiresAt = &expiresAt
+	}
+
+	return credential, nil
+}
+
+// oauth2ConfigFromCredential rebuilds the client settings stored with a token.
+func oauth2ConfigFromCredential(credential *core.Credential) *OAuth2Config {
+	return &OAuth2Config{
+		ClientID:     configString(credential.Data, "client_id"),
+		ClientSecret: configString(credential.Data, "client_secret"),
+		TokenURL:     configString(credential.Data, "token_url"),
+		Scopes:       configStrings(credential.Data, "scopes"),
+		GrantType:    configString(credential.Data, "grant_type"),
+	}
+}
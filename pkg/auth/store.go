@@ -0,0 +1,379 @@
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"golang.org/x/crypto/scrypt"
+)
+
+// CredentialKeyEnv is the environment variable holding the passphrase used to
+// encrypt credentials at rest.
+const CredentialKeyEnv = "ADK_CREDENTIAL_KEY"
+
+// SaltFileName is the file in the base directory of an encrypted credential
+// store holding the salt used to derive its key from a passphrase.
+const SaltFileName = "credentials.salt"
+
+// scrypt parameters for KeyFromPassphrase.
+const (
+	scryptN   = 1 << 15
+	scryptR   = 8
+	scryptP   = 1
+	saltBytes = 16
+)
+
+// InMemoryCredentialService keeps credentials in memory.
+// It is intended for testing and development.
+type InMemoryCredentialService struct {
+	mu          sync.RWMutex
+	credentials map[string]*core.Credential
+}
+
+var _ core.CredentialService = (*InMemoryCredentialService)(nil)
+
+// NewInMemoryCredentialService creates a new in-memory credential service.
+func NewInMemoryCredentialService() *InMemoryCredentialService {
+	return &InMemoryCredentialService{
+		credentials: make(map[string]*core.Credential),
+	}
+}
+
+func credentialKey(appName, userID, credentialID string) string {
+	return appName + "/" + userID + "/" + credentialID
+}
+
+// GetCredential retrieves a credential. Returns nil if not found.
+func (s *InMemoryCredentialService) GetCredential(ctx context.Context, req *core.CredentialRequest) (*core.Credential, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	credential, ok := s.credentials[credentialKey(req.AppName, req.UserID, req.CredentialID)]
+	if !ok {
+		return nil, nil
+	}
+	return copyCredential(credential), nil
+}
+
+// StoreCredential saves a credential.
+func (s *InMemoryCredentialService) StoreCredential(ctx context.Context, req *core.StoreCredentialRequest) error {
+	if req.Credential == nil || req.Credential.ID == "" {
+		return fmt.Errorf("credential with an ID is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.credentials[credentialKey(req.AppName, req.UserID, req.Credential.ID)] = copyCredential(req.Credential)
+	return nil
+}
+
+// DeleteCredential removes a credential.
+func (s *InMemoryCredentialService) DeleteCredential(ctx context.Context, req *core.CredentialRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.credentials, credentialKey(req.AppName, req.UserID, req.CredentialID))
+	return nil
+}
+
+// EncryptedFileCredentialService stores credentials on disk encrypted with
+// AES-256-GCM, one file per credential under <base>/<app>/<user>/.
+type EncryptedFileCredentialService struct {
+	baseDir string
+	aead    cipher.AEAD
+	mu      sync.RWMutex
+}
+
+var _ core.CredentialService = (*EncryptedFileCredentialService)(nil)
+
+// NewEncryptedFileCredentialService creates a credential store rooted at baseDir.
+// The key must be 32 bytes; use KeyFromPassphrase to derive one.
+func NewEncryptedFileCredentialService(baseDir string, key []byte) (*EncryptedFileCredentialService, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("credential encryption key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	if err := os.MkdirAll(baseDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create credential directory: %w", err)
+	}
+
+	return &EncryptedFileCredentialService{baseDir: baseDir, aead: aead}, nil
+}
+
+// KeyFromPassphrase derives a 32-byte encryption key from a passphrase with
+// scrypt. Use LoadOrCreateSalt for the salt of a store.
+func KeyFromPassphrase(passphrase string, salt []byte) ([]byte, error) {
+	if len(salt) == 0 {
+		return nil, fmt.Errorf("salt is required")
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadOrCreateSalt returns the salt stored in SaltFileName under baseDir,
+// creating a random one the first time.
+func LoadOrCreateSalt(baseDir string) ([]byte, error) {
+	path := filepath.Join(baseDir, SaltFileName)
+	salt, err := os.ReadFile(path)
+	if err == nil {
+		if len(salt) < saltBytes {
+			return nil, fmt.Errorf("salt file is corrupt")
+		}
+		return salt, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read salt: %w", err)
+	}
+
+	salt = make([]byte, saltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if err := os.MkdirAll(baseDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create credential directory: %w", err)
+	}
+	// Never replace the salt of an existing store, or its credentials are lost
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return LoadOrCreateSalt(baseDir)
+		}
+		return nil, fmt.Errorf("failed to create salt file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(salt); err != nil {
+		return nil, fmt.Errorf("failed to write salt: %w", err)
+	}
+	return salt, nil
+}
+
+// credentialPath returns the file holding a credential.
+func (s *EncryptedFileCredentialService) credentialPath(appName, userID, credentialID string) (string, error) {
+	for _, id := range []string{appName, userID} {
+		if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+			return "", fmt.Errorf("invalid credential scope identifier: %q", id)
+		}
+	}
+	if credentialID == "" {
+		return "", fmt.Errorf("credential ID is required")
+	}
+	return filepath.Join(s.baseDir, appName, userID, url.PathEscape(credentialID)+".cred"), nil
+}
+
+// GetCredential retrieves and decrypts a credential. Returns nil if not found.
+func (s *EncryptedFileCredentialService) GetCredential(ctx context.Context, req *core.CredentialRequest) (*core.Credential, error) {
+	path, err := s.credentialPath(req.AppName, req.UserID, req.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read credential: %w", err)
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, fmt.Errorf("credential file is corrupt")
+	}
+	// The credential's scope is bound as additional data so files cannot be
+	// swapped between users, while the store itself can still be moved
+	additionalData := []byte(credentialKey(req.AppName, req.UserID, req.CredentialID))
+	plaintext, err := s.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credential: %w", err)
+	}
+
+	var credential core.Credential
+	if err := json.Unmarshal(plaintext, &credential); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credential: %w", err)
+	}
+	return &credential, nil
+}
+
+// StoreCredential encrypts and saves a credential.
+func (s *EncryptedFileCredentialService) StoreCredential(ctx context.Context, req *core.StoreCredentialRequest) error {
+	if req.Credential == nil {
+		return fmt.Errorf("credential is required")
+	}
+	path, err := s.credentialPath(req.AppName, req.UserID, req.Credential.ID)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(req.Credential)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential: %w", err)
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	additionalData := []byte(credentialKey(req.AppName, req.UserID, req.Credential.ID))
+	sealed := s.aead.Seal(nonce, nonce, plaintext, additionalData)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create credential directory: %w", err)
+	}
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, sealed, 0600); err != nil {
+		return fmt.Errorf("failed to write credential: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename credential file: %w", err)
+	}
+	return nil
+}
+
+// DeleteCredential removes a credential.
+func (s *EncryptedFileCredentialService) DeleteCredential(ctx context.Context, req *core.CredentialRequest) error {
+	path, err := s.credentialPath(req.AppName, req.UserID, req.CredentialID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete credential: %w", err)
+	}
+	return nil
+}
+
+// RefreshingCredentialService wraps a credential store and transparently
+// refreshes OAuth2 credentials that are about to expire.
+type RefreshingCredentialService struct {
+	store core.CredentialService
+
+	// RefreshWindow is how long before ExpiresAt a credential is refreshed.
+	RefreshWindow time.Duration
+}
+
+var _ core.CredentialService = (*RefreshingCredentialService)(nil)
+
+// NewRefreshingCredentialService wraps store with OAuth2 token refresh.
+func NewRefreshingCredentialService(store core.CredentialService) *RefreshingCredentialService {
+	return &RefreshingCredentialService{store: store, RefreshWindow: time.Minute}
+}
+
+// GetCredential returns a credential, refreshing and re-storing it first when
+// its ExpiresAt falls within the refresh window.
+func (s *RefreshingCredentialService) GetCredential(ctx context.Context, req *core.CredentialRequest) (*core.Credential, error) {
+	credential, err := s.store.GetCredential(ctx, req)
+	if err != nil || credential == nil {
+		return credential, err
+	}
+	if credential.Type != SchemeOAuth2 || credential.ExpiresAt == nil ||
+		time.Until(*credential.ExpiresAt) > s.RefreshWindow {
+		return credential, nil
+	}
+
+	refreshed, err := oauth2ConfigFromCredential(credential).Refresh(ctx, credential)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh credential %s: %w", credential.ID, err)
+	}
+	if err := s.store.StoreCredential(ctx, &core.StoreCredentialRequest{
+		AppName:    req.AppName,
+		UserID:     req.UserID,
+		Credential: refreshed,
+	}); err != nil {
+		return nil, err
+	}
+	return refreshed, nil
+}
+
+// StoreCredential saves a credential in the underlying store.
+func (s *RefreshingCredentialService) StoreCredential(ctx context.Context, req *core.StoreCredentialRequest) error {
+	return s.store.StoreCredential(ctx, req)
+}
+
+// DeleteCredential removes a credential from the underlying store.
+func (s *RefreshingCredentialService) DeleteCredential(ctx context.Context, req *core.CredentialRequest) error {
+	return s.store.DeleteCredential(ctx, req)
+}
+
+// NewCredentialServiceFromURI creates a credential service from a
+// --credential-service-uri value. The returned service refreshes OAuth2 tokens.
+// Supported forms are:
+//
+//	""                      in-memory storage
+//	memory://               in-memory storage
+//	file:///path/to/dir     encrypted local storage; the key is derived from $ADK_CREDENTIAL_KEY
+//	                        and the salt in the directory's SaltFileName
+func NewCredentialServiceFromURI(uri string) (core.CredentialService, error) {
+	scheme, rest, found := strings.Cut(uri, "://")
+	if uri == "" || (found && scheme == "memory") {
+		return NewRefreshingCredentialService(NewInMemoryCredentialService()), nil
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid credential service URI: %s", uri)
+	}
+
+	switch strings.ToLower(scheme) {
+	case "file":
+		passphrase := os.Getenv(CredentialKeyEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("%s must be set to encrypt stored credentials", CredentialKeyEnv)
+		}
+		salt, err := LoadOrCreateSalt(rest)
+		if err != nil {
+			return nil, err
+		}
+		key, err := KeyFromPassphrase(passphrase, salt)
+		if err != nil {
+			return nil, err
+		}
+		store, err := NewEncryptedFileCredentialService(rest, key)
+		if err != nil {
+			return nil, err
+		}
+		return NewRefreshingCredentialService(store), nil
+	default:
+		return nil, fmt.Errorf("unsupported credential service URI scheme: %s", scheme)
+	}
+}
+
+func copyCredential(credential *core.Credential) *core.Credential {
+	copied := *credential
+	copied.Data = make(map[string]any, len(credential.Data))
+	for key, value := range credential.Data {
+		copied.Data[key] = value
+	}
+	return &copied
+}
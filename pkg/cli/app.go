@@ -5,6 +5,9 @@ import (
 	"os"
 
 	"github.com/urfave/cli/v2"
+
+	// Register the built-in LLM providers so agents can resolve their model
+	_ "github.com/agent-protocol/adk-golang/pkg/llmconnect/providers"
 )
 
 // Version information - will be set during build
@@ -74,15 +77,19 @@ func commonServiceFlags() []cli.Flag {
 	return []cli.Flag{
 		&cli.StringFlag{
 			Name:  "session-service-uri",
-			Usage: "URI of the session service (e.g., 'sqlite://path/to/db.sqlite', 'agentengine://resource_id')",
+			Usage: "URI of the session service (e.g., 'sqlite:///path/to/adk.db', 'file:///path/to/dir')",
 		},
 		&cli.StringFlag{
 			Name:  "artifact-service-uri",
-			Usage: "URI of the artifact service (e.g., 'gs://bucket-name')",
+			Usage: "URI of the artifact service (e.g., 'file:///path/to/artifacts', 'memory://')",
 		},
 		&cli.StringFlag{
 			Name:  "memory-service-uri",
-			Usage: "URI of the memory service (e.g., 'rag://corpus_id', 'agentengine://resource_id')",
+			Usage: "URI of the memory service (e.g., 'file:///path/to/memory.json?embed_model=nomic-embed-text', 'memory://')",
+		},
+		&cli.StringFlag{
+			Name:  "credential-service-uri",
+			Usage: "URI of the credential service (e.g., 'file:///path/to/credentials', encrypted with $ADK_CREDENTIAL_KEY)",
 		},
 		&cli.StringFlag{
 			Name:  "eval-storage-uri",
@@ -1,11 +1,20 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/urfave/cli/v2"
+
+	"github.com/agent-protocol/adk-golang/pkg/cli/utils"
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/evaluation"
 )
 
 // evalCommand creates the 'eval' command
@@ -24,7 +33,7 @@ func evalCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "eval",
 		Usage:     "Evaluates an agent against evaluation sets",
-		ArgsUsage: "AGENT_PATH EVAL_SET_FILES...",
+		ArgsUsage: "AGENT_PATH EVAL_SET_FILE[:EVAL_ID,...]...",
 		Flags:     flags,
 		Action:    evalCommandAction,
 	}
@@ -52,38 +61,153 @@ func evalCommandAction(c *cli.Context) error {
 
 	configFile := c.String("config-file")
 	printDetailed := c.Bool("print-detailed-results")
-	evalStorageURI := c.String("eval-storage-uri")
 
 	fmt.Printf("Evaluating agent: %s\n", absAgentPath)
-	fmt.Printf("Evaluation sets: %v\n", evalSetFiles)
 
-	if configFile != "" {
-		fmt.Printf("Config file: %s\n", configFile)
+	evalConfig, err := evaluation.LoadEvalConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	// Load and validate all eval sets before running anything
+	var evalSets []*evaluation.EvalSet
+	for _, arg := range evalSetFiles {
+		path, selected := evaluation.ParseEvalSetArg(arg)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("eval set file not found: %s", path)
+		}
+
+		evalSet, err := evaluation.LoadEvalSet(path)
+		if err != nil {
+			return err
+		}
+		evalSet, err = evaluation.FilterEvalCases(evalSet, selected)
+		if err != nil {
+			return err
+		}
+		evalSets = append(evalSets, evalSet)
+	}
+
+	// Load the agent
+	agentParentDir := filepath.Dir(absAgentPath)
+	agentFolderName := filepath.Base(absAgentPath)
+
+	loader := utils.NewAgentLoader(agentParentDir)
+	rootAgent, err := loader.LoadAgent(agentFolderName)
+	if err != nil {
+		return fmt.Errorf("failed to load agent: %w", err)
+	}
+
+	// Results are stored next to the agent unless --eval-storage-uri says otherwise
+	evalStore, err := evaluation.NewEvalStoreFromURI(c.String("eval-storage-uri"), agentParentDir)
+	if err != nil {
+		return fmt.Errorf("failed to create eval store: %w", err)
+	}
+
+	ctx := context.Background()
+	evaluator := evaluation.NewEvaluator(agentFolderName, rootAgent, evalConfig)
+
+	var results []*evaluation.EvalSetResult
+	for _, evalSet := range evalSets {
+		fmt.Printf("Running eval set %s (%d cases)\n", evalSet.EvalSetID, len(evalSet.EvalCases))
+
+		result, err := evaluator.EvaluateSet(ctx, evalSet)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate eval set %s: %w", evalSet.EvalSetID, err)
+		}
+		results = append(results, result)
+
+		if err := evalStore.SaveEvalSetResult(ctx, agentFolderName, result); err != nil {
+			return fmt.Errorf("failed to save eval result: %w", err)
+		}
+		fmt.Printf("Saved eval result %s\n", result.EvalSetResultID)
 	}
-	if evalStorageURI != "" {
-		fmt.Printf("Eval storage: %s\n", evalStorageURI)
+
+	printEvalSummary(os.Stdout, results)
+	if printDetailed {
+		printEvalDetails(os.Stdout, results)
 	}
 
-	// Validate eval set files exist
-	for _, evalFile := range evalSetFiles {
-		if _, err := os.Stat(evalFile); os.IsNotExist(err) {
-			return fmt.Errorf("eval set file not found: %s", evalFile)
+	for _, result := range results {
+		if !result.Passed() {
+			return fmt.Errorf("one or more eval cases failed")
 		}
 	}
 
-	// TODO: Implement evaluation logic
-	// This would involve:
-	// 1. Loading the agent
-	// 2. Loading evaluation sets and configurations
-	// 3. Running the agent against each eval case
-	// 4. Collecting metrics and results
-	// 5. Generating reports
+	return nil
+}
+
+// printEvalSummary prints a pass/fail table with one row per eval case.
+func printEvalSummary(out io.Writer, results []*evaluation.EvalSetResult) {
+	passed, failed := 0, 0
 
-	fmt.Printf("Evaluation implementation not yet complete.\n")
-	fmt.Printf("Configuration would be:\n")
-	fmt.Printf("  Agent: %s\n", absAgentPath)
-	fmt.Printf("  Eval sets: %v\n", evalSetFiles)
-	fmt.Printf("  Print detailed: %v\n", printDetailed)
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EVAL SET\tEVAL CASE\tSTATUS\tMETRICS")
+	for _, result := range results {
+		for _, caseResult := range result.EvalCaseResults {
+			if caseResult.FinalEvalStatus == evaluation.EvalStatusFailed {
+				failed++
+			} else {
+				passed++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", caseResult.EvalSetID, caseResult.EvalID,
+				caseResult.FinalEvalStatus, formatMetricResults(caseResult.OverallMetricResults))
+		}
+	}
+	w.Flush()
 
-	return fmt.Errorf("eval command not yet implemented")
+	fmt.Fprintf(out, "\nTests passed: %d\nTests failed: %d\n", passed, failed)
+}
+
+// printEvalDetails prints expected vs actual tool calls and responses for every invocation.
+func printEvalDetails(out io.Writer, results []*evaluation.EvalSetResult) {
+	for _, result := range results {
+		for _, caseResult := range result.EvalCaseResults {
+			fmt.Fprintf(out, "\n=== %s / %s: %s ===\n", caseResult.EvalSetID, caseResult.EvalID, caseResult.FinalEvalStatus)
+			if caseResult.ErrorMessage != "" {
+				fmt.Fprintf(out, "Error: %s\n", caseResult.ErrorMessage)
+			}
+
+			for i, invocation := range caseResult.InvocationResults {
+				expected := invocation.ExpectedInvocation
+				actual := invocation.ActualInvocation
+
+				fmt.Fprintf(out, "\n--- Invocation %d ---\n", i+1)
+				fmt.Fprintf(out, "User: %s\n", evaluation.ContentText(expected.UserContent))
+				fmt.Fprintf(out, "Expected tool calls: %s\n", formatToolUses(expected.ToolUses()))
+				fmt.Fprintf(out, "Actual tool calls:   %s\n", formatToolUses(actual.ToolUses()))
+				fmt.Fprintf(out, "Expected response: %s\n", evaluation.ContentText(expected.FinalResponse))
+				fmt.Fprintf(out, "Actual response:   %s\n", evaluation.ContentText(actual.FinalResponse))
+				fmt.Fprintf(out, "Metrics: %s\n", formatMetricResults(invocation.MetricResults))
+			}
+		}
+	}
+}
+
+func formatMetricResults(metrics []*evaluation.EvalMetricResult) string {
+	parts := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		score := "n/a"
+		if metric.Score != nil {
+			score = fmt.Sprintf("%.2f", *metric.Score)
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s (>= %.2f)", metric.MetricName, score, metric.Threshold))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatToolUses(calls []core.FunctionCall) string {
+	if len(calls) == 0 {
+		return "(none)"
+	}
+	parts := make([]string, 0, len(calls))
+	for _, call := range calls {
+		args, err := json.Marshal(call.Args)
+		if err != nil {
+			args = []byte("{}")
+		}
+		parts = append(parts, fmt.Sprintf("%s(%s)", call.Name, args))
+	}
+	return strings.Join(parts, ", ")
 }
@@ -10,8 +10,11 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/agent-protocol/adk-golang/pkg/artifacts"
+	"github.com/agent-protocol/adk-golang/pkg/auth"
 	"github.com/agent-protocol/adk-golang/pkg/cli/utils"
 	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/memory"
 	"github.com/agent-protocol/adk-golang/pkg/runners"
 	"github.com/agent-protocol/adk-golang/pkg/sessions"
 )
@@ -81,8 +84,26 @@ func runCommandAction(c *cli.Context) error {
 	}
 
 	// Create services
-	sessionService := sessions.NewInMemorySessionService()
-	// TODO: Create artifact and credential services based on URIs
+	sessionService, err := sessions.NewSessionServiceFromURI(c.String("session-service-uri"), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create session service: %w", err)
+	}
+	defer sessionService.Close(context.Background())
+
+	artifactService, err := artifacts.NewArtifactServiceFromURI(c.String("artifact-service-uri"))
+	if err != nil {
+		return fmt.Errorf("failed to create artifact service: %w", err)
+	}
+
+	memoryService, err := memory.NewMemoryServiceFromURI(c.String("memory-service-uri"))
+	if err != nil {
+		return fmt.Errorf("failed to create memory service: %w", err)
+	}
+
+	credentialService, err := auth.NewCredentialServiceFromURI(c.String("credential-service-uri"))
+	if err != nil {
+		return fmt.Errorf("failed to create credential service: %w", err)
+	}
 
 	// Create session
 	ctx := context.Background()
@@ -107,7 +128,7 @@ func runCommandAction(c *cli.Context) error {
 	}
 
 	// Interactive mode
-	return runInteractiveMode(ctx, rootAgent, sessionService, session, c.Bool("save-session"), c.String("session-id"))
+	return runInteractiveMode(ctx, rootAgent, sessionService, artifactService, memoryService, credentialService, session, c.Bool("save-session"), c.String("session-id"))
 }
 
 func runReplayMode(ctx context.Context, replayFile string, agent core.BaseAgent, sessionService core.SessionService, session *core.Session) error {
@@ -132,9 +153,12 @@ func runResumeMode(ctx context.Context, resumeFile string, agent core.BaseAgent,
 	return nil
 }
 
-func runInteractiveMode(ctx context.Context, rootAgent core.BaseAgent, sessionService core.SessionService, session *core.Session, saveSession bool, sessionID string) error {
+func runInteractiveMode(ctx context.Context, rootAgent core.BaseAgent, sessionService core.SessionService, artifactService core.ArtifactService, memoryService core.MemoryService, credentialService core.CredentialService, session *core.Session, saveSession bool, sessionID string) error {
 	// Create runner
 	runner := runners.NewRunner(session.AppName, rootAgent, sessionService)
+	runner.SetArtifactService(artifactService)
+	runner.SetMemoryService(memoryService)
+	runner.SetCredentialService(credentialService)
 
 	fmt.Printf("Running agent %s, type 'exit' to exit.\n", rootAgent.Name())
 	fmt.Print("[user]: ")
@@ -176,6 +200,10 @@ func runInteractiveMode(ctx context.Context, rootAgent core.BaseAgent, sessionSe
 
 		// Process events
 		for event := range eventStream {
+			// Streamed chunks are repeated by the complete event
+			if event.Partial != nil && *event.Partial {
+				continue
+			}
 			if event.Content != nil && len(event.Content.Parts) > 0 {
 				var textParts []string
 				for _, part := range event.Content.Parts {
@@ -57,6 +57,7 @@ func webCommandAction(c *cli.Context) error {
 	sessionServiceURI := c.String("session-service-uri")
 	artifactServiceURI := c.String("artifact-service-uri")
 	memoryServiceURI := c.String("memory-service-uri")
+	credentialServiceURI := c.String("credential-service-uri")
 	evalStorageURI := c.String("eval-storage-uri")
 
 	fmt.Printf("Starting ADK Web Server...\n")
@@ -72,17 +73,18 @@ func webCommandAction(c *cli.Context) error {
 
 	// Create server configuration
 	config := &api.ServerConfig{
-		Host:               host,
-		Port:               port,
-		AgentsDir:          absAgentsDir,
-		SessionServiceURI:  sessionServiceURI,
-		ArtifactServiceURI: artifactServiceURI,
-		MemoryServiceURI:   memoryServiceURI,
-		EvalStorageURI:     evalStorageURI,
-		AllowOrigins:       allowOrigins,
-		TraceToCloud:       traceToCloud,
-		A2AEnabled:         a2a,
-		LogLevel:           logLevel,
+		Host:                 host,
+		Port:                 port,
+		AgentsDir:            absAgentsDir,
+		SessionServiceURI:    sessionServiceURI,
+		ArtifactServiceURI:   artifactServiceURI,
+		MemoryServiceURI:     memoryServiceURI,
+		CredentialServiceURI: credentialServiceURI,
+		EvalStorageURI:       evalStorageURI,
+		AllowOrigins:         allowOrigins,
+		TraceToCloud:         traceToCloud,
+		A2AEnabled:           a2a,
+		LogLevel:             logLevel,
 	}
 
 	// Create and start server
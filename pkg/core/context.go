@@ -480,7 +480,20 @@ func (tc *ToolContext) SaveArtifact(filename string, content []byte, mimeType st
 		MimeType:  mimeType,
 	}
 
-	return tc.InvocationContext.ArtifactService.SaveArtifact(tc.InvocationContext, req)
+	version, err := tc.InvocationContext.ArtifactService.SaveArtifact(tc.InvocationContext, req)
+	if err != nil {
+		return 0, err
+	}
+
+	// Record the new version so the runner can track it in the session
+	if tc.Actions != nil {
+		if tc.Actions.ArtifactDelta == nil {
+			tc.Actions.ArtifactDelta = make(map[string]int)
+		}
+		tc.Actions.ArtifactDelta[filename] = version
+	}
+
+	return version, nil
 }
 
 // LoadArtifact loads an artifact by filename and optional version.
@@ -547,7 +560,13 @@ func (tc *ToolContext) GetCredential(credentialID string) (*Credential, error) {
 		return nil, fmt.Errorf("credential service not available")
 	}
 
-	return tc.InvocationContext.CredentialService.GetCredential(tc.InvocationContext, credentialID)
+	req := &CredentialRequest{
+		AppName:      tc.InvocationContext.Session.AppName,
+		UserID:       tc.InvocationContext.Session.UserID,
+		CredentialID: credentialID,
+	}
+
+	return tc.InvocationContext.CredentialService.GetCredential(tc.InvocationContext, req)
 }
 
 // TransferToAgent transfers control to another agent.
@@ -616,7 +635,26 @@ type RunConfig struct {
 	SaveInputBlobsAsArtifacts bool           `json:"save_input_blobs_as_artifacts"`
 	MaxTurns                  *int           `json:"max_turns,omitempty"`
 	Timeout                   *time.Duration `json:"timeout,omitempty"`
-}
+	// MaxInlineBlobSize is the size in bytes up to which input blobs stay
+	// inline when SaveInputBlobsAsArtifacts is set (0 = offload all blobs).
+	MaxInlineBlobSize int `json:"max_inline_blob_size,omitempty"`
+	// Budget limits the usage of a single invocation. It takes precedence
+	// over the runner's invocation budget.
+	Budget *Budget `json:"budget,omitempty"`
+	// StreamingMode asks agents to stream their responses as partial events.
+	StreamingMode StreamingMode `json:"streaming_mode,omitempty"`
+}
+
+// StreamingMode selects how agents deliver their responses.
+type StreamingMode string
+
+const (
+	// StreamingModeNone delivers complete events only.
+	StreamingModeNone StreamingMode = ""
+	// StreamingModeSSE also delivers partial events as the model generates
+	// them. Only the complete events are persisted.
+	StreamingModeSSE StreamingMode = "sse"
+)
 
 // LLMRequest represents a request to a language model.
 type LLMRequest struct {
@@ -630,6 +668,13 @@ type LLMResponse struct {
 	Content  *Content       `json:"content,omitempty"`
 	Partial  *bool          `json:"partial,omitempty"`
 	Metadata map[string]any `json:"metadata,omitempty"`
+	Usage    *Usage         `json:"usage,omitempty"`
+
+	// ErrorCode, ErrorMessage and CustomMetadata are copied onto the
+	// agent's event, e.g. by callbacks that block or rewrite a response.
+	ErrorCode      *string        `json:"error_code,omitempty"`
+	ErrorMessage   *string        `json:"error_message,omitempty"`
+	CustomMetadata map[string]any `json:"custom_metadata,omitempty"`
 }
 
 // LLMConfig contains configuration for LLM requests.
@@ -641,6 +686,23 @@ type LLMConfig struct {
 	TopK              *int                   `json:"top_k,omitempty"`
 	Tools             []*FunctionDeclaration `json:"tools,omitempty"`
 	SystemInstruction *string                `json:"system_instruction,omitempty"`
+
+	// ResponseMIMEType requests a response format; "application/json" enables JSON mode.
+	ResponseMIMEType string `json:"response_mime_type,omitempty"`
+
+	// ResponseSchema constrains a JSON response to a JSON schema on providers
+	// that support structured output.
+	ResponseSchema map[string]any `json:"response_schema,omitempty"`
+
+	// Thinking enables the reasoning of models that support it; the
+	// reasoning is returned as thought parts.
+	Thinking *ThinkingConfig `json:"thinking,omitempty"`
+}
+
+// ThinkingConfig configures the reasoning of thinking models.
+type ThinkingConfig struct {
+	// BudgetTokens caps the tokens spent on reasoning (0 = provider default).
+	BudgetTokens int `json:"budget_tokens,omitempty"`
 }
 
 // Credential represents authentication credentials.
@@ -739,6 +801,20 @@ type ListVersionsRequest struct {
 	Filename  string `json:"filename"`
 }
 
+// CredentialRequest identifies a stored credential.
+type CredentialRequest struct {
+	AppName      string `json:"app_name"`
+	UserID       string `json:"user_id"`
+	CredentialID string `json:"credential_id"`
+}
+
+// StoreCredentialRequest contains parameters for storing a credential.
+type StoreCredentialRequest struct {
+	AppName    string      `json:"app_name"`
+	UserID     string      `json:"user_id"`
+	Credential *Credential `json:"credential"`
+}
+
 // RetrieveMemoryRequest contains parameters for memory retrieval.
 type RetrieveMemoryRequest struct {
 	AppName string `json:"app_name"`
@@ -141,15 +141,16 @@ type MemoryService interface {
 }
 
 // CredentialService defines the interface for credential management.
+// Credentials are scoped to an app and user.
 type CredentialService interface {
-	// GetCredential retrieves a credential by ID.
-	GetCredential(ctx context.Context, credentialID string) (*Credential, error)
+	// GetCredential retrieves a credential by ID. Returns nil if not found.
+	GetCredential(ctx context.Context, req *CredentialRequest) (*Credential, error)
 
-	// StoreCredential saves a credential.
-	StoreCredential(ctx context.Context, credential *Credential) error
+	// StoreCredential saves a credential under its ID.
+	StoreCredential(ctx context.Context, req *StoreCredentialRequest) error
 
 	// DeleteCredential removes a credential.
-	DeleteCredential(ctx context.Context, credentialID string) error
+	DeleteCredential(ctx context.Context, req *CredentialRequest) error
 }
 
 // Runner orchestrates agent execution and manages the overall workflow.
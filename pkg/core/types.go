@@ -15,15 +15,61 @@ type Content struct {
 }
 
 // Part represents a component of a message.
-// This is a union type that can be text, function call, function response, etc.
+// This is a union type that can be text, function call, function response,
+// inline binary data ("inline_data"), a file reference ("file_data") or the
+// reasoning of a thinking model ("thought").
 type Part struct {
 	Type             string            `json:"type,omitempty"`
 	Text             *string           `json:"text,omitempty"`
 	FunctionCall     *FunctionCall     `json:"function_call,omitempty"`
 	FunctionResponse *FunctionResponse `json:"function_response,omitempty"`
+	InlineData       *Blob             `json:"inline_data,omitempty"`
+	FileData         *FileData         `json:"file_data,omitempty"`
+	Thought          *string           `json:"thought,omitempty"`
 	Metadata         map[string]any    `json:"metadata,omitempty"`
 }
 
+// Blob is binary data, such as an image, carried inline in a part.
+// Data is base64 encoded in JSON.
+type Blob struct {
+	MimeType    string `json:"mime_type"`
+	Data        []byte `json:"data"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// ArtifactURIScheme prefixes the file URIs of parts referencing an artifact
+// of the session, such as an input blob saved to the artifact service. The
+// Metadata "artifact_version" of the part pins the version.
+const ArtifactURIScheme = "artifact://"
+
+// FileData references a file by URI instead of carrying its bytes.
+type FileData struct {
+	MimeType    string `json:"mime_type,omitempty"`
+	FileURI     string `json:"file_uri"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// NewInlineDataPart creates a part carrying binary data.
+func NewInlineDataPart(mimeType string, data []byte) Part {
+	return Part{Type: "inline_data", InlineData: &Blob{MimeType: mimeType, Data: data}}
+}
+
+// NewFileDataPart creates a part referencing a file by URI.
+func NewFileDataPart(fileURI, mimeType string) Part {
+	return Part{Type: "file_data", FileData: &FileData{MimeType: mimeType, FileURI: fileURI}}
+}
+
+// NewThoughtPart creates a part carrying model reasoning. Thoughts are kept
+// apart from Text so that they are never mistaken for the answer.
+func NewThoughtPart(thought string) Part {
+	return Part{Type: "thought", Thought: &thought}
+}
+
+// IsThought reports whether the part carries model reasoning.
+func (p Part) IsThought() bool {
+	return p.Thought != nil
+}
+
 // FunctionCall represents a request to execute a tool.
 type FunctionCall struct {
 	ID   string         `json:"id,omitempty"`
@@ -60,6 +106,21 @@ type EventActions struct {
 	TransferToAgent      *string               `json:"transfer_to_agent,omitempty"`
 	Escalate             *bool                 `json:"escalate,omitempty"`
 	RequestedAuthConfigs map[string]AuthConfig `json:"requested_auth_configs,omitempty"`
+	Compaction           *EventCompaction      `json:"compaction,omitempty"`
+}
+
+// EventCompaction replaces the session events between StartTimestamp and
+// EndTimestamp (inclusive) with CompactedContent, typically a summary, when
+// the history is sent to a model.
+type EventCompaction struct {
+	StartTimestamp   time.Time `json:"start_timestamp"`
+	EndTimestamp     time.Time `json:"end_timestamp"`
+	CompactedContent *Content  `json:"compacted_content"`
+}
+
+// Covers reports whether the compaction replaces the event.
+func (c *EventCompaction) Covers(event *Event) bool {
+	return !event.Timestamp.Before(c.StartTimestamp) && !event.Timestamp.After(c.EndTimestamp)
 }
 
 // Event represents a single event in the conversation between agents and users.
@@ -78,6 +139,7 @@ type Event struct {
 	ErrorMessage       *string        `json:"error_message,omitempty"`
 	Interrupted        *bool          `json:"interrupted,omitempty"`
 	CustomMetadata     map[string]any `json:"custom_metadata,omitempty"`
+	Usage              *Usage         `json:"usage,omitempty"`
 }
 
 // NewEvent creates a new event with a generated ID and current timestamp.
@@ -139,6 +201,40 @@ func (e *Event) GetFunctionResponses() []*FunctionResponse {
 	return responses
 }
 
+// HasThoughts reports whether the event content carries thought parts.
+func (e *Event) HasThoughts() bool {
+	if e.Content == nil {
+		return false
+	}
+	for _, part := range e.Content.Parts {
+		if part.IsThought() {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutThoughts returns the event with its thought parts removed. The event
+// is returned as is when it has none; otherwise a copy is made, whose content
+// is nil if it held only thoughts.
+func (e *Event) WithoutThoughts() *Event {
+	if !e.HasThoughts() {
+		return e
+	}
+	parts := make([]Part, 0, len(e.Content.Parts))
+	for _, part := range e.Content.Parts {
+		if !part.IsThought() {
+			parts = append(parts, part)
+		}
+	}
+	stripped := *e
+	stripped.Content = nil
+	if len(parts) > 0 {
+		stripped.Content = &Content{Role: e.Content.Role, Parts: parts}
+	}
+	return &stripped
+}
+
 // IsFinalResponse determines if this event represents a final response.
 func (e *Event) IsFinalResponse() bool {
 	log.Println("Checking if event is a final response...")
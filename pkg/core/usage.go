@@ -0,0 +1,112 @@
+package core
+
+import "time"
+
+// Usage reports the tokens consumed by LLM calls and the time they took.
+// A single LLM response carries the usage of one call; aggregates sum them.
+type Usage struct {
+	InputTokens  int           `json:"input_tokens"`
+	OutputTokens int           `json:"output_tokens"`
+	CachedTokens int           `json:"cached_tokens,omitempty"`
+	TotalTokens  int           `json:"total_tokens"`
+	Latency      time.Duration `json:"latency,omitempty"`
+	LLMCalls     int           `json:"llm_calls"`
+}
+
+// Add accumulates other into u.
+func (u *Usage) Add(other *Usage) {
+	if other == nil {
+		return
+	}
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CachedTokens += other.CachedTokens
+	u.TotalTokens += other.TotalTokens
+	u.Latency += other.Latency
+	u.LLMCalls += other.LLMCalls
+}
+
+// UsageSummary aggregates the usage recorded on a list of events.
+type UsageSummary struct {
+	Total        Usage             `json:"total"`
+	ByInvocation map[string]*Usage `json:"by_invocation"`
+}
+
+// SummarizeUsage sums the usage of events, in total and per invocation.
+func SummarizeUsage(events []*Event) *UsageSummary {
+	summary := &UsageSummary{ByInvocation: make(map[string]*Usage)}
+	for _, event := range events {
+		if event == nil || event.Usage == nil {
+			continue
+		}
+		summary.Total.Add(event.Usage)
+
+		invocation, ok := summary.ByInvocation[event.InvocationID]
+		if !ok {
+			invocation = &Usage{}
+			summary.ByInvocation[event.InvocationID] = invocation
+		}
+		invocation.Add(event.Usage)
+	}
+	return summary
+}
+
+// Budget limits the LLM usage of a scope such as an invocation, a session or
+// a user's day. Zero fields are unlimited.
+type Budget struct {
+	MaxInputTokens  int `json:"max_input_tokens,omitempty"`
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+	MaxLLMCalls     int `json:"max_llm_calls,omitempty"`
+	MaxToolCalls    int `json:"max_tool_calls,omitempty"`
+}
+
+// BudgetUsage is the usage counted against a Budget.
+type BudgetUsage struct {
+	Usage
+	ToolCalls int `json:"tool_calls"`
+}
+
+// AddEvent accumulates the LLM usage and function calls of a complete event.
+// Partial events are skipped since the complete event repeats them.
+func (u *BudgetUsage) AddEvent(event *Event) {
+	if event == nil || (event.Partial != nil && *event.Partial) {
+		return
+	}
+	u.Usage.Add(event.Usage)
+	if event.Content != nil {
+		for _, part := range event.Content.Parts {
+			if part.FunctionCall != nil {
+				u.ToolCalls++
+			}
+		}
+	}
+}
+
+// Exhausted returns the name of the first limit that usage has reached, or
+// an empty string if the budget allows more work.
+func (b *Budget) Exhausted(usage *BudgetUsage) string {
+	return b.check(usage, 0)
+}
+
+// Exceeded returns the name of the first limit that usage has gone over, or
+// an empty string if usage is within the budget.
+func (b *Budget) Exceeded(usage *BudgetUsage) string {
+	return b.check(usage, 1)
+}
+
+func (b *Budget) check(usage *BudgetUsage, margin int) string {
+	if b == nil || usage == nil {
+		return ""
+	}
+	switch {
+	case b.MaxInputTokens > 0 && usage.InputTokens >= b.MaxInputTokens+margin:
+		return "max_input_tokens"
+	case b.MaxOutputTokens > 0 && usage.OutputTokens >= b.MaxOutputTokens+margin:
+		return "max_output_tokens"
+	case b.MaxLLMCalls > 0 && usage.LLMCalls >= b.MaxLLMCalls+margin:
+		return "max_llm_calls"
+	case b.MaxToolCalls > 0 && usage.ToolCalls >= b.MaxToolCalls+margin:
+		return "max_tool_calls"
+	}
+	return ""
+}
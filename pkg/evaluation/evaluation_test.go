@@ -0,0 +1,232 @@
+package evaluation
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/agents"
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+const testEvalSetJSON = `{
+  "eval_set_id": "weather_set",
+  "eval_cases": [
+    {
+      "eval_id": "weather_paris",
+      "conversation": [
+        {
+          "user_content": {"role": "user", "parts": [{"text": "What is the weather in Paris?"}]},
+          "final_response": {"role": "model", "parts": [{"text": "It is sunny in Paris."}]},
+          "intermediate_data": {"tool_uses": [{"name": "get_weather", "args": {"city": "Paris", "days": 1}}]}
+        }
+      ],
+      "session_input": {"user_id": "alice", "state": {"units": "metric"}}
+    },
+    {
+      "eval_id": "weather_london",
+      "conversation": [
+        {
+          "user_content": {"role": "user", "parts": [{"text": "What is the weather in London?"}]},
+          "final_response": {"role": "model", "parts": [{"text": "It is raining in London."}]},
+          "intermediate_data": {"tool_uses": [{"name": "get_weather", "args": {"city": "London", "days": 1}}]}
+        }
+      ]
+    }
+  ]
+}`
+
+// newWeatherAgent returns an agent that always calls get_weather for Paris and
+// answers that it is sunny in Paris.
+func newWeatherAgent() *agents.CustomAgent {
+	agent := agents.NewCustomAgent("weather_agent", "Answers weather questions")
+	agent.SetExecute(func(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event) error {
+		call := core.NewEvent(invocationCtx.InvocationID, "weather_agent")
+		call.Content = &core.Content{
+			Role: "model",
+			Parts: []core.Part{{
+				Type:         "function_call",
+				FunctionCall: &core.FunctionCall{ID: "call_1", Name: "get_weather", Args: map[string]any{"city": "Paris", "days": 1.0}},
+			}},
+		}
+		eventChan <- call
+
+		answer := core.NewEvent(invocationCtx.InvocationID, "weather_agent")
+		answer.Content = &core.Content{
+			Role:  "model",
+			Parts: []core.Part{{Type: "text", Text: ptr.Ptr("It is sunny in Paris.")}},
+		}
+		eventChan <- answer
+		return nil
+	})
+	return agent
+}
+
+func TestToolTrajectoryScore(t *testing.T) {
+	expected := []core.FunctionCall{{Name: "get_weather", Args: map[string]any{"city": "Paris", "days": 1}}}
+
+	if score := ToolTrajectoryScore([]core.FunctionCall{{Name: "get_weather", Args: map[string]any{"city": "Paris", "days": 1.0}}}, expected); score != 1.0 {
+		t.Errorf("Expected matching trajectory to score 1.0, got %f", score)
+	}
+	if score := ToolTrajectoryScore([]core.FunctionCall{{Name: "get_weather", Args: map[string]any{"city": "London", "days": 1}}}, expected); score != 0.0 {
+		t.Errorf("Expected different args to score 0.0, got %f", score)
+	}
+	if score := ToolTrajectoryScore(nil, expected); score != 0.0 {
+		t.Errorf("Expected missing calls to score 0.0, got %f", score)
+	}
+	if score := ToolTrajectoryScore(nil, nil); score != 1.0 {
+		t.Errorf("Expected no calls on both sides to score 1.0, got %f", score)
+	}
+}
+
+func TestResponseMatchScore(t *testing.T) {
+	if score := ResponseMatchScore("It is sunny in Paris.", "it is SUNNY in paris"); score != 1.0 {
+		t.Errorf("Expected identical tokens to score 1.0, got %f", score)
+	}
+	if score := ResponseMatchScore("hello", "goodbye"); score != 0.0 {
+		t.Errorf("Expected disjoint tokens to score 0.0, got %f", score)
+	}
+
+	// 3 overlapping tokens, precision 3/4, recall 3/5.
+	score := ResponseMatchScore("the cat sat down", "the cat sat on mats")
+	want := 2 * (0.75 * 0.6) / (0.75 + 0.6)
+	if math.Abs(score-want) > 1e-9 {
+		t.Errorf("Expected score %f, got %f", want, score)
+	}
+}
+
+func TestParseEvalSetArg(t *testing.T) {
+	path, selected := ParseEvalSetArg("sets/weather.evalset.json:case1, case2")
+	if path != "sets/weather.evalset.json" {
+		t.Errorf("Expected path without selection, got %s", path)
+	}
+	if len(selected) != 2 || selected[0] != "case1" || selected[1] != "case2" {
+		t.Errorf("Expected [case1 case2], got %v", selected)
+	}
+
+	path, selected = ParseEvalSetArg("sets/weather.evalset.json")
+	if path != "sets/weather.evalset.json" || selected != nil {
+		t.Errorf("Expected plain path, got %s %v", path, selected)
+	}
+}
+
+func TestLoadEvalSet_LegacyFormat(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "simple.test.json")
+	legacy := `[{"query": "hi", "expected_tool_use": [{"tool_name": "greet", "tool_input": {"name": "bob"}}], "reference": "hello bob"}]`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write eval file: %v", err)
+	}
+
+	evalSet, err := LoadEvalSet(path)
+	if err != nil {
+		t.Fatalf("Failed to load eval set: %v", err)
+	}
+	if evalSet.EvalSetID != "simple.test" {
+		t.Errorf("Expected eval set ID derived from file name, got %s", evalSet.EvalSetID)
+	}
+	if len(evalSet.EvalCases) != 1 || len(evalSet.EvalCases[0].Conversation) != 1 {
+		t.Fatalf("Expected one case with one invocation, got %+v", evalSet.EvalCases)
+	}
+	invocation := evalSet.EvalCases[0].Conversation[0]
+	if ContentText(invocation.FinalResponse) != "hello bob" {
+		t.Errorf("Expected reference as final response, got %q", ContentText(invocation.FinalResponse))
+	}
+	if uses := invocation.ToolUses(); len(uses) != 1 || uses[0].Name != "greet" {
+		t.Errorf("Expected greet tool use, got %+v", uses)
+	}
+}
+
+func TestFilterEvalCases(t *testing.T) {
+	evalSet, err := ParseEvalSet([]byte(testEvalSetJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse eval set: %v", err)
+	}
+
+	filtered, err := FilterEvalCases(evalSet, []string{"weather_london"})
+	if err != nil {
+		t.Fatalf("Failed to filter eval cases: %v", err)
+	}
+	if len(filtered.EvalCases) != 1 || filtered.EvalCases[0].EvalID != "weather_london" {
+		t.Errorf("Expected only weather_london, got %+v", filtered.EvalCases)
+	}
+
+	if _, err := FilterEvalCases(evalSet, []string{"missing"}); err == nil {
+		t.Error("Expected error for unknown eval case")
+	}
+}
+
+func TestEvaluator_EvaluateSet(t *testing.T) {
+	evalSet, err := ParseEvalSet([]byte(testEvalSetJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse eval set: %v", err)
+	}
+
+	evaluator := NewEvaluator("weather_app", newWeatherAgent(), nil)
+	result, err := evaluator.EvaluateSet(context.Background(), evalSet)
+	if err != nil {
+		t.Fatalf("EvaluateSet failed: %v", err)
+	}
+
+	if len(result.EvalCaseResults) != 2 {
+		t.Fatalf("Expected 2 case results, got %d", len(result.EvalCaseResults))
+	}
+	if !strings.HasPrefix(result.EvalSetResultID, "weather_app_weather_set_") {
+		t.Errorf("Unexpected eval set result ID: %s", result.EvalSetResultID)
+	}
+
+	paris := result.EvalCaseResults[0]
+	if paris.FinalEvalStatus != EvalStatusPassed {
+		t.Errorf("Expected weather_paris to pass, got %s (%+v)", paris.FinalEvalStatus, paris.OverallMetricResults)
+	}
+	if paris.UserID != "alice" {
+		t.Errorf("Expected session input user to be used, got %s", paris.UserID)
+	}
+	actual := paris.InvocationResults[0].ActualInvocation
+	if ContentText(actual.FinalResponse) != "It is sunny in Paris." {
+		t.Errorf("Unexpected actual final response: %q", ContentText(actual.FinalResponse))
+	}
+
+	london := result.EvalCaseResults[1]
+	if london.FinalEvalStatus != EvalStatusFailed {
+		t.Errorf("Expected weather_london to fail, got %s", london.FinalEvalStatus)
+	}
+	for _, metric := range london.OverallMetricResults {
+		if metric.EvalStatus != EvalStatusFailed {
+			t.Errorf("Expected metric %s to fail, got %s", metric.MetricName, metric.EvalStatus)
+		}
+	}
+
+	if result.Passed() {
+		t.Error("Expected eval set result not to pass")
+	}
+}
+
+func TestEvaluator_AgentError(t *testing.T) {
+	agent := agents.NewCustomAgent("broken_agent", "Always fails")
+	agent.SetExecute(func(invocationCtx *core.InvocationContext, eventChan chan<- *core.Event) error {
+		return context.DeadlineExceeded
+	})
+
+	evalCase := &EvalCase{
+		EvalID: "broken",
+		Conversation: []*Invocation{{
+			UserContent: &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}},
+		}},
+	}
+
+	result, err := NewEvaluator("app", agent, nil).EvaluateCase(context.Background(), evalCase)
+	if err != nil {
+		t.Fatalf("EvaluateCase failed: %v", err)
+	}
+	if result.FinalEvalStatus != EvalStatusFailed {
+		t.Errorf("Expected failed status, got %s", result.FinalEvalStatus)
+	}
+	if result.ErrorMessage == "" {
+		t.Error("Expected error message to be recorded")
+	}
+}
@@ -0,0 +1,277 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/runners"
+	"github.com/agent-protocol/adk-golang/pkg/sessions"
+)
+
+// DefaultEvalUserID is used when an eval case does not specify a user.
+const DefaultEvalUserID = "eval_user"
+
+// Evaluator replays eval cases against an agent and scores the results.
+type Evaluator struct {
+	appName string
+	agent   core.BaseAgent
+	config  *EvalConfig
+}
+
+// NewEvaluator creates a new evaluator for the given agent.
+// If config is nil, DefaultEvalConfig is used.
+func NewEvaluator(appName string, agent core.BaseAgent, config *EvalConfig) *Evaluator {
+	if config == nil {
+		config = DefaultEvalConfig()
+	}
+	return &Evaluator{
+		appName: appName,
+		agent:   agent,
+		config:  config,
+	}
+}
+
+// EvaluateSet runs every case in the eval set and returns the aggregated result.
+func (e *Evaluator) EvaluateSet(ctx context.Context, evalSet *EvalSet) (*EvalSetResult, error) {
+	result := &EvalSetResult{
+		EvalSetResultID:   fmt.Sprintf("%s_%s_%d", e.appName, evalSet.EvalSetID, time.Now().UnixNano()),
+		EvalSetID:         evalSet.EvalSetID,
+		EvalCaseResults:   make([]*EvalCaseResult, 0, len(evalSet.EvalCases)),
+		CreationTimestamp: nowTimestamp(),
+	}
+
+	for _, evalCase := range evalSet.EvalCases {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		caseResult, err := e.EvaluateCase(ctx, evalCase)
+		if err != nil {
+			return result, fmt.Errorf("failed to evaluate case %s: %w", evalCase.EvalID, err)
+		}
+		caseResult.EvalSetID = evalSet.EvalSetID
+		result.EvalCaseResults = append(result.EvalCaseResults, caseResult)
+	}
+
+	return result, nil
+}
+
+// EvaluateCase replays a single eval case in a fresh in-memory session and scores it.
+// Agent failures are reported as a failed case rather than as an error.
+func (e *Evaluator) EvaluateCase(ctx context.Context, evalCase *EvalCase) (*EvalCaseResult, error) {
+	userID := DefaultEvalUserID
+	var initialState map[string]any
+	if evalCase.SessionInput != nil {
+		if evalCase.SessionInput.UserID != "" {
+			userID = evalCase.SessionInput.UserID
+		}
+		initialState = evalCase.SessionInput.State
+	}
+
+	sessionService := sessions.NewInMemorySessionService()
+	session, err := sessionService.CreateSession(ctx, &core.CreateSessionRequest{
+		AppName: e.appName,
+		UserID:  userID,
+		State:   copyState(initialState),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	runner := runners.NewRunner(e.appName, e.agent, sessionService)
+
+	result := &EvalCaseResult{
+		EvalID:            evalCase.EvalID,
+		SessionID:         session.ID,
+		UserID:            userID,
+		InvocationResults: make([]*InvocationResult, 0, len(evalCase.Conversation)),
+	}
+
+	for _, expected := range evalCase.Conversation {
+		actual, errMsg, err := e.runInvocation(ctx, runner, session, expected)
+		if err != nil {
+			return nil, err
+		}
+		if errMsg != "" && result.ErrorMessage == "" {
+			result.ErrorMessage = errMsg
+		}
+
+		result.InvocationResults = append(result.InvocationResults, &InvocationResult{
+			ExpectedInvocation: expected,
+			ActualInvocation:   actual,
+			MetricResults:      e.scoreInvocation(actual, expected),
+		})
+	}
+
+	result.OverallMetricResults = e.aggregate(result.InvocationResults)
+	result.FinalEvalStatus = finalStatus(result.OverallMetricResults)
+	if result.ErrorMessage != "" {
+		result.FinalEvalStatus = EvalStatusFailed
+	}
+
+	return result, nil
+}
+
+// runInvocation sends the user content of an expected invocation to the agent and
+// records the tool calls and responses it produces.
+func (e *Evaluator) runInvocation(ctx context.Context, runner *runners.RunnerImpl, session *core.Session, expected *Invocation) (*Invocation, string, error) {
+	eventStream, err := runner.RunAsync(ctx, &core.RunRequest{
+		UserID:     session.UserID,
+		SessionID:  session.ID,
+		NewMessage: expected.UserContent,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to run agent: %w", err)
+	}
+
+	actual := &Invocation{
+		UserContent:      expected.UserContent,
+		IntermediateData: &IntermediateData{},
+	}
+	var errMsg string
+	var textEvents []*core.Event
+
+	for event := range eventStream {
+		if actual.InvocationID == "" {
+			actual.InvocationID = event.InvocationID
+		}
+		if event.ErrorMessage != nil && errMsg == "" {
+			errMsg = *event.ErrorMessage
+		}
+		if event.Partial != nil && *event.Partial {
+			continue
+		}
+		if event.Content == nil || event.Author == "user" {
+			continue
+		}
+
+		if recordToolUses(actual, event) {
+			textEvents = append(textEvents, event)
+		}
+	}
+	setResponses(actual, textEvents)
+
+	return actual, errMsg, ctx.Err()
+}
+
+// scoreInvocation computes every configured metric for a single invocation.
+func (e *Evaluator) scoreInvocation(actual, expected *Invocation) []*EvalMetricResult {
+	results := make([]*EvalMetricResult, 0, len(e.config.Criteria))
+	for _, name := range sortedMetricNames(e.config.Criteria) {
+		threshold := e.config.Criteria[name]
+		metric := &EvalMetricResult{
+			MetricName: name,
+			Threshold:  threshold,
+			EvalStatus: EvalStatusNotEvaluated,
+		}
+
+		var score float64
+		switch name {
+		case MetricToolTrajectoryAvgScore:
+			score = ToolTrajectoryScore(actual.ToolUses(), expected.ToolUses())
+		case MetricResponseMatchScore:
+			if expected.FinalResponse == nil {
+				results = append(results, metric)
+				continue
+			}
+			score = ResponseMatchScore(ContentText(actual.FinalResponse), ContentText(expected.FinalResponse))
+		default:
+			results = append(results, metric)
+			continue
+		}
+
+		metric.Score = &score
+		metric.EvalStatus = statusFor(score, threshold)
+		results = append(results, metric)
+	}
+	return results
+}
+
+// aggregate averages each metric over the invocations where it was evaluated.
+func (e *Evaluator) aggregate(invocations []*InvocationResult) []*EvalMetricResult {
+	results := make([]*EvalMetricResult, 0, len(e.config.Criteria))
+	for _, name := range sortedMetricNames(e.config.Criteria) {
+		threshold := e.config.Criteria[name]
+		metric := &EvalMetricResult{
+			MetricName: name,
+			Threshold:  threshold,
+			EvalStatus: EvalStatusNotEvaluated,
+		}
+
+		total, count := 0.0, 0
+		for _, invocation := range invocations {
+			for _, m := range invocation.MetricResults {
+				if m.MetricName == name && m.Score != nil {
+					total += *m.Score
+					count++
+				}
+			}
+		}
+		if count > 0 {
+			avg := total / float64(count)
+			metric.Score = &avg
+			metric.EvalStatus = statusFor(avg, threshold)
+		}
+		results = append(results, metric)
+	}
+	return results
+}
+
+func statusFor(score, threshold float64) EvalStatus {
+	if score >= threshold {
+		return EvalStatusPassed
+	}
+	return EvalStatusFailed
+}
+
+// finalStatus fails if any metric failed, passes if at least one metric passed.
+func finalStatus(metrics []*EvalMetricResult) EvalStatus {
+	status := EvalStatusNotEvaluated
+	for _, metric := range metrics {
+		switch metric.EvalStatus {
+		case EvalStatusFailed:
+			return EvalStatusFailed
+		case EvalStatusPassed:
+			status = EvalStatusPassed
+		}
+	}
+	return status
+}
+
+// sortedMetricNames returns the metric names in a stable order for reporting,
+// built-in metrics first.
+func sortedMetricNames(criteria map[string]float64) []string {
+	rank := func(name string) int {
+		switch name {
+		case MetricToolTrajectoryAvgScore:
+			return 0
+		case MetricResponseMatchScore:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	names := make([]string, 0, len(criteria))
+	for name := range criteria {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if rank(names[i]) != rank(names[j]) {
+			return rank(names[i]) < rank(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func copyState(state map[string]any) map[string]any {
+	copied := make(map[string]any, len(state))
+	for k, v := range state {
+		copied[k] = v
+	}
+	return copied
+}
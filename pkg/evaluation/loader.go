@@ -0,0 +1,165 @@
+package evaluation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// legacyEvalEntry is a single turn in the older list-based eval file format:
+//
+//	[{"query": "...", "expected_tool_use": [{"tool_name": "...", "tool_input": {...}}], "reference": "..."}]
+//
+// All entries in such a file form one conversation.
+type legacyEvalEntry struct {
+	Query           string `json:"query"`
+	ExpectedToolUse []struct {
+		ToolName  string         `json:"tool_name"`
+		ToolInput map[string]any `json:"tool_input"`
+	} `json:"expected_tool_use"`
+	Reference string `json:"reference"`
+}
+
+// ParseEvalSetArg splits an eval set argument of the form "path/to/set.json:case1,case2"
+// into the file path and the optional list of selected eval case IDs.
+func ParseEvalSetArg(arg string) (string, []string) {
+	path := arg
+	var selected []string
+	if idx := strings.LastIndex(arg, ":"); idx > 0 && !strings.ContainsAny(arg[idx+1:], `/\`) {
+		// Guard against Windows drive letters such as "C:\..." by requiring a file
+		// with a suffix before the colon.
+		if filepath.Ext(arg[:idx]) != "" {
+			path = arg[:idx]
+			for _, id := range strings.Split(arg[idx+1:], ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					selected = append(selected, id)
+				}
+			}
+		}
+	}
+	return path, selected
+}
+
+// LoadEvalSet reads an eval set from a JSON file. Both the eval set format and the
+// older list-based test file format are accepted.
+func LoadEvalSet(path string) (*EvalSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read eval set file: %w", err)
+	}
+
+	evalSet, err := ParseEvalSet(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse eval set file %s: %w", path, err)
+	}
+
+	if evalSet.EvalSetID == "" {
+		evalSet.EvalSetID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for i, evalCase := range evalSet.EvalCases {
+		if evalCase.EvalID == "" {
+			evalCase.EvalID = fmt.Sprintf("%s_%d", evalSet.EvalSetID, i)
+		}
+	}
+
+	return evalSet, nil
+}
+
+// ParseEvalSet decodes an eval set from JSON.
+func ParseEvalSet(data []byte) (*EvalSet, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var entries []legacyEvalEntry
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, err
+		}
+		return convertLegacyEntries(entries), nil
+	}
+
+	var evalSet EvalSet
+	if err := json.Unmarshal(trimmed, &evalSet); err != nil {
+		return nil, err
+	}
+	return &evalSet, nil
+}
+
+// FilterEvalCases returns a copy of the eval set containing only the selected cases.
+// An empty selection keeps every case.
+func FilterEvalCases(evalSet *EvalSet, selected []string) (*EvalSet, error) {
+	if len(selected) == 0 {
+		return evalSet, nil
+	}
+
+	byID := make(map[string]*EvalCase, len(evalSet.EvalCases))
+	for _, evalCase := range evalSet.EvalCases {
+		byID[evalCase.EvalID] = evalCase
+	}
+
+	filtered := *evalSet
+	filtered.EvalCases = make([]*EvalCase, 0, len(selected))
+	for _, id := range selected {
+		evalCase, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("eval case %s not found in eval set %s", id, evalSet.EvalSetID)
+		}
+		filtered.EvalCases = append(filtered.EvalCases, evalCase)
+	}
+	return &filtered, nil
+}
+
+// LoadEvalConfig reads evaluation criteria from a JSON file.
+// An empty path returns the default configuration.
+func LoadEvalConfig(path string) (*EvalConfig, error) {
+	if path == "" {
+		return DefaultEvalConfig(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read eval config file: %w", err)
+	}
+
+	var config EvalConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse eval config file %s: %w", path, err)
+	}
+	if len(config.Criteria) == 0 {
+		return DefaultEvalConfig(), nil
+	}
+
+	return &config, nil
+}
+
+func convertLegacyEntries(entries []legacyEvalEntry) *EvalSet {
+	evalCase := &EvalCase{}
+	for _, entry := range entries {
+		query := entry.Query
+		invocation := &Invocation{
+			UserContent: &core.Content{
+				Role:  "user",
+				Parts: []core.Part{{Type: "text", Text: &query}},
+			},
+			IntermediateData: &IntermediateData{},
+		}
+		if entry.Reference != "" {
+			reference := entry.Reference
+			invocation.FinalResponse = &core.Content{
+				Role:  "model",
+				Parts: []core.Part{{Type: "text", Text: &reference}},
+			}
+		}
+		for _, toolUse := range entry.ExpectedToolUse {
+			invocation.IntermediateData.ToolUses = append(invocation.IntermediateData.ToolUses, core.FunctionCall{
+				Name: toolUse.ToolName,
+				Args: toolUse.ToolInput,
+			})
+		}
+		evalCase.Conversation = append(evalCase.Conversation, invocation)
+	}
+	return &EvalSet{EvalCases: []*EvalCase{evalCase}}
+}
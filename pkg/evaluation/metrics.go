@@ -0,0 +1,103 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// ToolTrajectoryScore returns 1.0 when the actual tool calls match the expected
+// ones exactly (same tools, same order, same arguments) and 0.0 otherwise.
+func ToolTrajectoryScore(actual, expected []core.FunctionCall) float64 {
+	if len(actual) != len(expected) {
+		return 0.0
+	}
+	for i := range expected {
+		if actual[i].Name != expected[i].Name {
+			return 0.0
+		}
+		if !argsEqual(actual[i].Args, expected[i].Args) {
+			return 0.0
+		}
+	}
+	return 1.0
+}
+
+// ResponseMatchScore returns the ROUGE-1 F-measure between the actual and the
+// expected response text. Two empty responses are considered a perfect match.
+func ResponseMatchScore(actual, expected string) float64 {
+	actualTokens := tokenize(actual)
+	expectedTokens := tokenize(expected)
+
+	if len(actualTokens) == 0 && len(expectedTokens) == 0 {
+		return 1.0
+	}
+	if len(actualTokens) == 0 || len(expectedTokens) == 0 {
+		return 0.0
+	}
+
+	expectedCounts := make(map[string]int, len(expectedTokens))
+	for _, token := range expectedTokens {
+		expectedCounts[token]++
+	}
+
+	overlap := 0
+	for _, token := range actualTokens {
+		if expectedCounts[token] > 0 {
+			expectedCounts[token]--
+			overlap++
+		}
+	}
+	if overlap == 0 {
+		return 0.0
+	}
+
+	precision := float64(overlap) / float64(len(actualTokens))
+	recall := float64(overlap) / float64(len(expectedTokens))
+	return 2 * precision * recall / (precision + recall)
+}
+
+// ContentText concatenates the text parts of a content.
+func ContentText(content *core.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != nil {
+			texts = append(texts, *part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// tokenize lowercases the text and splits it on anything that is not a letter or digit.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// argsEqual compares tool arguments after normalizing them through JSON, so that
+// for example an int in an eval file and a float64 from a model compare equal.
+func argsEqual(a, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(normalizeJSON(a), normalizeJSON(b))
+}
+
+func normalizeJSON(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
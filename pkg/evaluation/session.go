@@ -0,0 +1,103 @@
+package evaluation
+
+import (
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// NewEvalCaseFromSession builds an eval case from a recorded session. Each user
+// message starts a new invocation; the tool calls and text responses that follow
+// it become the expected tool uses and final response.
+func NewEvalCaseFromSession(evalID string, session *core.Session) *EvalCase {
+	evalCase := &EvalCase{
+		EvalID:       evalID,
+		Conversation: ConvertEventsToInvocations(session.Events),
+		SessionInput: &SessionInput{
+			AppName: session.AppName,
+			UserID:  session.UserID,
+		},
+		CreationTimestamp: nowTimestamp(),
+	}
+	return evalCase
+}
+
+// ConvertEventsToInvocations groups session events into invocations.
+// Events that precede the first user message are ignored.
+func ConvertEventsToInvocations(events []*core.Event) []*Invocation {
+	invocations := make([]*Invocation, 0)
+
+	var current *Invocation
+	var textEvents []*core.Event
+	flush := func() {
+		if current == nil {
+			return
+		}
+		setResponses(current, textEvents)
+		invocations = append(invocations, current)
+		current, textEvents = nil, nil
+	}
+
+	for _, event := range events {
+		if event.Content == nil || (event.Partial != nil && *event.Partial) {
+			continue
+		}
+
+		if event.Author == "user" {
+			// Function responses are sent with the user role but belong to the current turn
+			if hasFunctionResponse(event.Content) {
+				continue
+			}
+			flush()
+			current = &Invocation{
+				InvocationID:     event.InvocationID,
+				UserContent:      event.Content,
+				IntermediateData: &IntermediateData{},
+			}
+			continue
+		}
+
+		if current != nil && recordToolUses(current, event) {
+			textEvents = append(textEvents, event)
+		}
+	}
+	flush()
+
+	return invocations
+}
+
+func hasFunctionResponse(content *core.Content) bool {
+	for _, part := range content.Parts {
+		if part.FunctionResponse != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// recordToolUses appends the function calls of an agent event to the invocation
+// and reports whether the event also carries text.
+func recordToolUses(invocation *Invocation, event *core.Event) bool {
+	hasText := false
+	for _, part := range event.Content.Parts {
+		if part.FunctionCall != nil {
+			invocation.IntermediateData.ToolUses = append(invocation.IntermediateData.ToolUses, *part.FunctionCall)
+		}
+		if part.Text != nil && *part.Text != "" {
+			hasText = true
+		}
+	}
+	return hasText
+}
+
+// setResponses uses the last text event as the final response and records the
+// ones before it as intermediate responses.
+func setResponses(invocation *Invocation, textEvents []*core.Event) {
+	n := len(textEvents)
+	if n == 0 {
+		return
+	}
+	invocation.FinalResponse = textEvents[n-1].Content
+	for _, event := range textEvents[:n-1] {
+		invocation.IntermediateData.IntermediateResponses = append(invocation.IntermediateData.IntermediateResponses,
+			IntermediateReply{Author: event.Author, Parts: event.Content.Parts})
+	}
+}
@@ -0,0 +1,409 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	evalSetFileSuffix       = ".evalset.json"
+	evalSetResultFileSuffix = ".evalset_result.json"
+	evalHistoryDir          = ".adk/eval_history"
+)
+
+var validIDPattern = regexp.MustCompile(`^[A-Za-z0-9_\-.]+$`)
+
+// EvalStore persists eval sets, eval cases and eval run results.
+// Get methods return (nil, nil) when the requested item does not exist.
+type EvalStore interface {
+	// CreateEvalSet creates a new, empty eval set.
+	CreateEvalSet(ctx context.Context, appName, evalSetID string) (*EvalSet, error)
+
+	// GetEvalSet returns an eval set with all its cases.
+	GetEvalSet(ctx context.Context, appName, evalSetID string) (*EvalSet, error)
+
+	// ListEvalSets returns the IDs of all eval sets for an app.
+	ListEvalSets(ctx context.Context, appName string) ([]string, error)
+
+	// DeleteEvalSet removes an eval set.
+	DeleteEvalSet(ctx context.Context, appName, evalSetID string) error
+
+	// AddEvalCase adds a case to an existing eval set. Case IDs must be unique within the set.
+	AddEvalCase(ctx context.Context, appName, evalSetID string, evalCase *EvalCase) error
+
+	// GetEvalCase returns a single case from an eval set.
+	GetEvalCase(ctx context.Context, appName, evalSetID, evalID string) (*EvalCase, error)
+
+	// UpdateEvalCase replaces an existing case in an eval set.
+	UpdateEvalCase(ctx context.Context, appName, evalSetID string, evalCase *EvalCase) error
+
+	// DeleteEvalCase removes a case from an eval set.
+	DeleteEvalCase(ctx context.Context, appName, evalSetID, evalID string) error
+
+	// SaveEvalSetResult stores the result of an eval run.
+	SaveEvalSetResult(ctx context.Context, appName string, result *EvalSetResult) error
+
+	// GetEvalSetResult returns a stored eval run result.
+	GetEvalSetResult(ctx context.Context, appName, resultID string) (*EvalSetResult, error)
+
+	// ListEvalSetResults returns the IDs of all stored eval run results for an app, oldest first.
+	ListEvalSetResults(ctx context.Context, appName string) ([]string, error)
+}
+
+var _ EvalStore = (*LocalEvalStore)(nil)
+
+// LocalEvalStore implements EvalStore on the local filesystem using the same layout
+// as agent directories:
+//
+//	<base>/<app>/<eval_set_id>.evalset.json
+//	<base>/<app>/.adk/eval_history/<result_id>.evalset_result.json
+type LocalEvalStore struct {
+	baseDir string
+	mutex   sync.RWMutex
+}
+
+// NewLocalEvalStore creates a new filesystem eval store rooted at baseDir.
+func NewLocalEvalStore(baseDir string) (*LocalEvalStore, error) {
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create eval storage directory: %w", err)
+	}
+	return &LocalEvalStore{baseDir: baseDir}, nil
+}
+
+// NewEvalStoreFromURI creates an eval store from an --eval-storage-uri value.
+// An empty URI stores eval data in defaultDir; "file://<path>" or a plain path
+// stores it under the given directory.
+func NewEvalStoreFromURI(uri, defaultDir string) (EvalStore, error) {
+	switch {
+	case uri == "":
+		return NewLocalEvalStore(defaultDir)
+	case strings.HasPrefix(uri, "file://"):
+		return NewLocalEvalStore(strings.TrimPrefix(uri, "file://"))
+	case strings.Contains(uri, "://"):
+		return nil, fmt.Errorf("unsupported eval storage URI: %s", uri)
+	default:
+		return NewLocalEvalStore(uri)
+	}
+}
+
+// CreateEvalSet creates a new, empty eval set.
+func (s *LocalEvalStore) CreateEvalSet(ctx context.Context, appName, evalSetID string) (*EvalSet, error) {
+	if err := validateIDs(appName, evalSetID); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	path := s.evalSetPath(appName, evalSetID)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("eval set already exists: %s", evalSetID)
+	}
+
+	evalSet := &EvalSet{
+		EvalSetID:         evalSetID,
+		Name:              evalSetID,
+		EvalCases:         make([]*EvalCase, 0),
+		CreationTimestamp: nowTimestamp(),
+	}
+	if err := writeJSONFile(path, evalSet); err != nil {
+		return nil, fmt.Errorf("failed to save eval set: %w", err)
+	}
+
+	return evalSet, nil
+}
+
+// GetEvalSet returns an eval set with all its cases.
+func (s *LocalEvalStore) GetEvalSet(ctx context.Context, appName, evalSetID string) (*EvalSet, error) {
+	if err := validateIDs(appName, evalSetID); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.loadEvalSet(appName, evalSetID)
+}
+
+// ListEvalSets returns the IDs of all eval sets for an app.
+func (s *LocalEvalStore) ListEvalSets(ctx context.Context, appName string) ([]string, error) {
+	if err := validateIDs(appName); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return listIDs(filepath.Join(s.baseDir, appName), evalSetFileSuffix)
+}
+
+// DeleteEvalSet removes an eval set.
+func (s *LocalEvalStore) DeleteEvalSet(ctx context.Context, appName, evalSetID string) error {
+	if err := validateIDs(appName, evalSetID); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := os.Remove(s.evalSetPath(appName, evalSetID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete eval set: %w", err)
+	}
+	return nil
+}
+
+// AddEvalCase adds a case to an existing eval set.
+func (s *LocalEvalStore) AddEvalCase(ctx context.Context, appName, evalSetID string, evalCase *EvalCase) error {
+	if err := validateIDs(appName, evalSetID, evalCase.EvalID); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	evalSet, err := s.requireEvalSet(appName, evalSetID)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range evalSet.EvalCases {
+		if existing.EvalID == evalCase.EvalID {
+			return fmt.Errorf("eval case %s already exists in eval set %s", evalCase.EvalID, evalSetID)
+		}
+	}
+
+	if evalCase.CreationTimestamp == 0 {
+		evalCase.CreationTimestamp = nowTimestamp()
+	}
+	evalSet.EvalCases = append(evalSet.EvalCases, evalCase)
+
+	return writeJSONFile(s.evalSetPath(appName, evalSetID), evalSet)
+}
+
+// GetEvalCase returns a single case from an eval set.
+func (s *LocalEvalStore) GetEvalCase(ctx context.Context, appName, evalSetID, evalID string) (*EvalCase, error) {
+	if err := validateIDs(appName, evalSetID); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	evalSet, err := s.loadEvalSet(appName, evalSetID)
+	if err != nil || evalSet == nil {
+		return nil, err
+	}
+
+	for _, evalCase := range evalSet.EvalCases {
+		if evalCase.EvalID == evalID {
+			return evalCase, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpdateEvalCase replaces an existing case in an eval set.
+func (s *LocalEvalStore) UpdateEvalCase(ctx context.Context, appName, evalSetID string, evalCase *EvalCase) error {
+	if err := validateIDs(appName, evalSetID); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	evalSet, err := s.requireEvalSet(appName, evalSetID)
+	if err != nil {
+		return err
+	}
+
+	for i, existing := range evalSet.EvalCases {
+		if existing.EvalID == evalCase.EvalID {
+			evalSet.EvalCases[i] = evalCase
+			return writeJSONFile(s.evalSetPath(appName, evalSetID), evalSet)
+		}
+	}
+	return fmt.Errorf("eval case %s not found in eval set %s", evalCase.EvalID, evalSetID)
+}
+
+// DeleteEvalCase removes a case from an eval set.
+func (s *LocalEvalStore) DeleteEvalCase(ctx context.Context, appName, evalSetID, evalID string) error {
+	if err := validateIDs(appName, evalSetID); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	evalSet, err := s.requireEvalSet(appName, evalSetID)
+	if err != nil {
+		return err
+	}
+
+	for i, existing := range evalSet.EvalCases {
+		if existing.EvalID == evalID {
+			evalSet.EvalCases = append(evalSet.EvalCases[:i], evalSet.EvalCases[i+1:]...)
+			return writeJSONFile(s.evalSetPath(appName, evalSetID), evalSet)
+		}
+	}
+	return fmt.Errorf("eval case %s not found in eval set %s", evalID, evalSetID)
+}
+
+// SaveEvalSetResult stores the result of an eval run.
+func (s *LocalEvalStore) SaveEvalSetResult(ctx context.Context, appName string, result *EvalSetResult) error {
+	if err := validateIDs(appName, result.EvalSetResultID); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := writeJSONFile(s.evalResultPath(appName, result.EvalSetResultID), result); err != nil {
+		return fmt.Errorf("failed to save eval result: %w", err)
+	}
+	return nil
+}
+
+// GetEvalSetResult returns a stored eval run result.
+func (s *LocalEvalStore) GetEvalSetResult(ctx context.Context, appName, resultID string) (*EvalSetResult, error) {
+	if err := validateIDs(appName, resultID); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var result EvalSetResult
+	found, err := readJSONFile(s.evalResultPath(appName, resultID), &result)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListEvalSetResults returns the IDs of all stored eval run results for an app, oldest first.
+func (s *LocalEvalStore) ListEvalSetResults(ctx context.Context, appName string) ([]string, error) {
+	if err := validateIDs(appName); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	dir := filepath.Join(s.baseDir, appName, evalHistoryDir)
+	ids, err := listIDs(dir, evalSetResultFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	// Order by file modification time so the history reads chronologically
+	modTimes := make(map[string]time.Time, len(ids))
+	for _, id := range ids {
+		if info, err := os.Stat(filepath.Join(dir, id+evalSetResultFileSuffix)); err == nil {
+			modTimes[id] = info.ModTime()
+		}
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return modTimes[ids[i]].Before(modTimes[ids[j]])
+	})
+
+	return ids, nil
+}
+
+func (s *LocalEvalStore) evalSetPath(appName, evalSetID string) string {
+	return filepath.Join(s.baseDir, appName, evalSetID+evalSetFileSuffix)
+}
+
+func (s *LocalEvalStore) evalResultPath(appName, resultID string) string {
+	return filepath.Join(s.baseDir, appName, evalHistoryDir, resultID+evalSetResultFileSuffix)
+}
+
+func (s *LocalEvalStore) loadEvalSet(appName, evalSetID string) (*EvalSet, error) {
+	var evalSet EvalSet
+	found, err := readJSONFile(s.evalSetPath(appName, evalSetID), &evalSet)
+	if err != nil || !found {
+		return nil, err
+	}
+	if evalSet.EvalCases == nil {
+		evalSet.EvalCases = make([]*EvalCase, 0)
+	}
+	return &evalSet, nil
+}
+
+func (s *LocalEvalStore) requireEvalSet(appName, evalSetID string) (*EvalSet, error) {
+	evalSet, err := s.loadEvalSet(appName, evalSetID)
+	if err != nil {
+		return nil, err
+	}
+	if evalSet == nil {
+		return nil, fmt.Errorf("eval set not found: %s", evalSetID)
+	}
+	return evalSet, nil
+}
+
+// validateIDs rejects IDs that could escape the storage directory.
+func validateIDs(ids ...string) error {
+	for _, id := range ids {
+		if !validIDPattern.MatchString(id) || id == "." || id == ".." {
+			return fmt.Errorf("invalid identifier: %q", id)
+		}
+	}
+	return nil
+}
+
+func listIDs(dir, suffix string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), suffix) {
+			ids = append(ids, strings.TrimSuffix(entry.Name(), suffix))
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func writeJSONFile(path string, value any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+func readJSONFile(path string, value any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return true, nil
+}
+
+func nowTimestamp() float64 {
+	return float64(time.Now().UnixNano()) / float64(time.Second)
+}
@@ -0,0 +1,110 @@
+package evaluation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+func TestLocalEvalStore_EvalSets(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalEvalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if _, err := store.CreateEvalSet(ctx, "app", "set1"); err != nil {
+		t.Fatalf("Failed to create eval set: %v", err)
+	}
+	if _, err := store.CreateEvalSet(ctx, "app", "set1"); err == nil {
+		t.Error("Expected error when creating duplicate eval set")
+	}
+	if _, err := store.CreateEvalSet(ctx, "app", "../escape"); err == nil {
+		t.Error("Expected error for invalid eval set ID")
+	}
+
+	evalCase := &EvalCase{
+		EvalID: "case1",
+		Conversation: []*Invocation{{
+			UserContent: &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}},
+		}},
+	}
+	if err := store.AddEvalCase(ctx, "app", "set1", evalCase); err != nil {
+		t.Fatalf("Failed to add eval case: %v", err)
+	}
+	if err := store.AddEvalCase(ctx, "app", "set1", evalCase); err == nil {
+		t.Error("Expected error when adding duplicate eval case")
+	}
+	if err := store.AddEvalCase(ctx, "app", "missing", &EvalCase{EvalID: "x"}); err == nil {
+		t.Error("Expected error when adding to missing eval set")
+	}
+
+	got, err := store.GetEvalCase(ctx, "app", "set1", "case1")
+	if err != nil || got == nil {
+		t.Fatalf("Failed to get eval case: %v", err)
+	}
+	if ContentText(got.Conversation[0].UserContent) != "hi" {
+		t.Errorf("Unexpected user content: %q", ContentText(got.Conversation[0].UserContent))
+	}
+
+	ids, err := store.ListEvalSets(ctx, "app")
+	if err != nil || len(ids) != 1 || ids[0] != "set1" {
+		t.Errorf("Expected [set1], got %v (%v)", ids, err)
+	}
+
+	if err := store.DeleteEvalCase(ctx, "app", "set1", "case1"); err != nil {
+		t.Fatalf("Failed to delete eval case: %v", err)
+	}
+	if got, _ := store.GetEvalCase(ctx, "app", "set1", "case1"); got != nil {
+		t.Error("Expected eval case to be deleted")
+	}
+
+	if missing, err := store.GetEvalSet(ctx, "app", "nope"); err != nil || missing != nil {
+		t.Errorf("Expected (nil, nil) for missing eval set, got %v, %v", missing, err)
+	}
+}
+
+func TestLocalEvalStore_Results(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalEvalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	result := &EvalSetResult{
+		EvalSetResultID: "app_set1_1",
+		EvalSetID:       "set1",
+		EvalCaseResults: []*EvalCaseResult{{EvalID: "case1", FinalEvalStatus: EvalStatusPassed}},
+	}
+	if err := store.SaveEvalSetResult(ctx, "app", result); err != nil {
+		t.Fatalf("Failed to save result: %v", err)
+	}
+
+	ids, err := store.ListEvalSetResults(ctx, "app")
+	if err != nil || len(ids) != 1 || ids[0] != "app_set1_1" {
+		t.Errorf("Expected [app_set1_1], got %v (%v)", ids, err)
+	}
+
+	loaded, err := store.GetEvalSetResult(ctx, "app", "app_set1_1")
+	if err != nil || loaded == nil {
+		t.Fatalf("Failed to load result: %v", err)
+	}
+	if len(loaded.EvalCaseResults) != 1 || loaded.EvalCaseResults[0].FinalEvalStatus != EvalStatusPassed {
+		t.Errorf("Unexpected loaded result: %+v", loaded)
+	}
+}
+
+func TestNewEvalStoreFromURI(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewEvalStoreFromURI("file://"+dir, ""); err != nil {
+		t.Errorf("Expected file URI to be supported: %v", err)
+	}
+	if _, err := NewEvalStoreFromURI("", dir); err != nil {
+		t.Errorf("Expected default directory to be used: %v", err)
+	}
+	if _, err := NewEvalStoreFromURI("gs://bucket", dir); err == nil {
+		t.Error("Expected unsupported scheme to fail")
+	}
+}
@@ -0,0 +1,137 @@
+// Package evaluation provides tooling for evaluating agents against eval sets.
+// An eval set is a collection of eval cases, each describing a conversation with
+// the user turns, the tool calls the agent is expected to make and the final
+// response it is expected to produce.
+package evaluation
+
+import (
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// Metric names understood by the evaluator.
+const (
+	MetricToolTrajectoryAvgScore = "tool_trajectory_avg_score"
+	MetricResponseMatchScore     = "response_match_score"
+)
+
+// EvalStatus is the outcome of evaluating a metric or a case.
+type EvalStatus string
+
+const (
+	EvalStatusPassed       EvalStatus = "PASSED"
+	EvalStatusFailed       EvalStatus = "FAILED"
+	EvalStatusNotEvaluated EvalStatus = "NOT_EVALUATED"
+)
+
+// EvalSet is a named collection of eval cases.
+type EvalSet struct {
+	EvalSetID         string      `json:"eval_set_id"`
+	Name              string      `json:"name,omitempty"`
+	Description       string      `json:"description,omitempty"`
+	EvalCases         []*EvalCase `json:"eval_cases"`
+	CreationTimestamp float64     `json:"creation_timestamp,omitempty"`
+}
+
+// EvalCase is a single conversation to replay against the agent.
+type EvalCase struct {
+	EvalID            string        `json:"eval_id"`
+	Conversation      []*Invocation `json:"conversation"`
+	SessionInput      *SessionInput `json:"session_input,omitempty"`
+	CreationTimestamp float64       `json:"creation_timestamp,omitempty"`
+}
+
+// SessionInput describes the session an eval case starts from.
+type SessionInput struct {
+	AppName string         `json:"app_name,omitempty"`
+	UserID  string         `json:"user_id,omitempty"`
+	State   map[string]any `json:"state,omitempty"`
+}
+
+// Invocation is one user turn together with the expected (or actual) agent behavior.
+type Invocation struct {
+	InvocationID     string            `json:"invocation_id,omitempty"`
+	UserContent      *core.Content     `json:"user_content"`
+	FinalResponse    *core.Content     `json:"final_response,omitempty"`
+	IntermediateData *IntermediateData `json:"intermediate_data,omitempty"`
+}
+
+// IntermediateData holds what happened between the user turn and the final response.
+type IntermediateData struct {
+	ToolUses              []core.FunctionCall `json:"tool_uses,omitempty"`
+	IntermediateResponses []IntermediateReply `json:"intermediate_responses,omitempty"`
+}
+
+// IntermediateReply is a text response produced by an agent before the final response.
+type IntermediateReply struct {
+	Author string      `json:"author"`
+	Parts  []core.Part `json:"parts"`
+}
+
+// ToolUses returns the tool calls recorded for the invocation.
+func (i *Invocation) ToolUses() []core.FunctionCall {
+	if i == nil || i.IntermediateData == nil {
+		return nil
+	}
+	return i.IntermediateData.ToolUses
+}
+
+// EvalConfig contains the pass thresholds for each metric.
+// Metrics that are not present in Criteria are not evaluated.
+type EvalConfig struct {
+	Criteria map[string]float64 `json:"criteria"`
+}
+
+// DefaultEvalConfig returns the default evaluation criteria.
+func DefaultEvalConfig() *EvalConfig {
+	return &EvalConfig{
+		Criteria: map[string]float64{
+			MetricToolTrajectoryAvgScore: 1.0,
+			MetricResponseMatchScore:     0.8,
+		},
+	}
+}
+
+// EvalMetricResult is the score of a single metric.
+type EvalMetricResult struct {
+	MetricName string     `json:"metric_name"`
+	Threshold  float64    `json:"threshold"`
+	Score      *float64   `json:"score,omitempty"`
+	EvalStatus EvalStatus `json:"eval_status"`
+}
+
+// InvocationResult compares an expected invocation against what the agent actually did.
+type InvocationResult struct {
+	ExpectedInvocation *Invocation         `json:"expected_invocation"`
+	ActualInvocation   *Invocation         `json:"actual_invocation"`
+	MetricResults      []*EvalMetricResult `json:"eval_metric_results"`
+}
+
+// EvalCaseResult is the outcome of a single eval case.
+type EvalCaseResult struct {
+	EvalSetID            string              `json:"eval_set_id"`
+	EvalID               string              `json:"eval_id"`
+	FinalEvalStatus      EvalStatus          `json:"final_eval_status"`
+	OverallMetricResults []*EvalMetricResult `json:"overall_eval_metric_results"`
+	InvocationResults    []*InvocationResult `json:"eval_metric_result_per_invocation"`
+	SessionID            string              `json:"session_id"`
+	UserID               string              `json:"user_id"`
+	ErrorMessage         string              `json:"error_message,omitempty"`
+}
+
+// EvalSetResult is the outcome of a run over an eval set.
+type EvalSetResult struct {
+	EvalSetResultID   string            `json:"eval_set_result_id"`
+	EvalSetID         string            `json:"eval_set_id"`
+	EvalCaseResults   []*EvalCaseResult `json:"eval_case_results"`
+	CreationTimestamp float64           `json:"creation_timestamp"`
+}
+
+// Passed reports whether every case in the run passed.
+func (r *EvalSetResult) Passed() bool {
+	for _, result := range r.EvalCaseResults {
+		if result.FinalEvalStatus == EvalStatusFailed {
+			return false
+		}
+	}
+	return true
+}
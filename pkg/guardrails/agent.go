@@ -0,0 +1,155 @@
+package guardrails
+
+import (
+	"github.com/agent-protocol/adk-golang/pkg/agents"
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// AttachToAgent checks the model calls of an agent, wrapping its existing
+// model callbacks. The user messages of each request are checked against
+// the input policies, and each response against the output policies. A
+// blocked message is answered with the blocked response without calling the
+// model, and earlier blocked messages are left out of later requests.
+//
+// The violations are listed on the agent's event for the response. Output
+// policies see complete final responses, so a streaming agent publishes no
+// partial events while output policies are set, and responses calling tools
+// are not checked.
+func (g *Guardrails) AttachToAgent(agent *agents.LLMAgent) {
+	var next agents.LlmAgentCallbacks
+	if callbacks := agent.Callbacks(); callbacks != nil {
+		next = *callbacks
+	}
+	agent.SetCallbacks(&agents.LlmAgentCallbacks{
+		BeforeModelCallback:     g.beforeModel(next.BeforeModelCallback),
+		AfterModelCallback:      g.afterModel(next.AfterModelCallback),
+		PartialResponseCallback: g.partialResponse(next.PartialResponseCallback),
+		BeforeToolCallback:      next.BeforeToolCallback,
+		AfterToolCallback:       next.AfterToolCallback,
+	})
+}
+
+// partialResponse drops partial responses while output policies are set, as
+// they are published before the complete response can be checked.
+func (g *Guardrails) partialResponse(next agents.PartialResponseCallback) agents.PartialResponseCallback {
+	return func(invocationCtx *core.InvocationContext, response *core.LLMResponse) (bool, error) {
+		if len(g.Output) > 0 {
+			return false, nil
+		}
+		if next == nil {
+			return true, nil
+		}
+		return next(invocationCtx, response)
+	}
+}
+
+// beforeModel checks the user messages of a request before next runs.
+func (g *Guardrails) beforeModel(next agents.BeforeModelCallback) agents.BeforeModelCallback {
+	return func(invocationCtx *core.InvocationContext, request *core.LLMRequest) (*core.LLMResponse, error) {
+		newest := -1
+		for i, content := range request.Contents {
+			if content.Role == "user" && hasText(&content) {
+				newest = i
+			}
+		}
+
+		contents := make([]core.Content, 0, len(request.Contents))
+		for i, content := range request.Contents {
+			if content.Role != "user" {
+				contents = append(contents, content)
+				continue
+			}
+			checked, found, blocked := checkContent(g.Input, &content)
+			if i == newest {
+				if blocked {
+					return &core.LLMResponse{
+						Content:        g.blockedResponse(),
+						ErrorCode:      ptr.Ptr(ErrorCodeViolation),
+						ErrorMessage:   ptr.Ptr(blockedMessage(found)),
+						CustomMetadata: recordViolations(nil, found),
+					}, nil
+				}
+			}
+			if !blocked {
+				contents = append(contents, *checked)
+			}
+		}
+		request.Contents = contents
+
+		if next == nil {
+			return nil, nil
+		}
+		return next(invocationCtx, request)
+	}
+}
+
+// afterModel checks a final response after next ran, and lists the
+// violations of the response and of the message it answers.
+func (g *Guardrails) afterModel(next agents.AfterModelCallback) agents.AfterModelCallback {
+	return func(invocationCtx *core.InvocationContext, response *core.LLMResponse) (*core.LLMResponse, error) {
+		if next != nil {
+			replacement, err := next(invocationCtx, response)
+			if err != nil {
+				return nil, err
+			}
+			if replacement != nil {
+				response = replacement
+			}
+		}
+
+		if response.ErrorCode != nil && *response.ErrorCode == ErrorCodeViolation {
+			return response, nil
+		}
+
+		// The message answered is checked again rather than its violations
+		// kept from the request, as a failed model call has no response
+		var violations []Violation
+		if invocationCtx.UserContent != nil {
+			_, violations, _ = checkContent(g.Input, invocationCtx.UserContent)
+		}
+
+		// The text next to tool calls is not the answer yet
+		checked, found, blocked := response.Content, []Violation(nil), false
+		if !hasFunctionCalls(response.Content) {
+			checked, found, blocked = checkContent(g.Output, response.Content)
+		}
+		violations = append(violations, found...)
+		if len(violations) == 0 {
+			return response, nil
+		}
+
+		substitute := *response
+		substitute.Content = checked
+		if blocked {
+			substitute.Content = g.blockedResponse()
+			substitute.ErrorCode = ptr.Ptr(ErrorCodeViolation)
+			substitute.ErrorMessage = ptr.Ptr(blockedMessage(found))
+		}
+		substitute.CustomMetadata = recordViolations(response.CustomMetadata, violations)
+		return &substitute, nil
+	}
+}
+
+// hasText reports whether a content has text parts.
+func hasText(content *core.Content) bool {
+	for _, part := range content.Parts {
+		if part.Text != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFunctionCalls reports whether a content calls tools.
+func hasFunctionCalls(content *core.Content) bool {
+	if content == nil {
+		return false
+	}
+	for _, part := range content.Parts {
+		if part.FunctionCall != nil {
+			return true
+		}
+	}
+	return false
+}
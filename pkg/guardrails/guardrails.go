@@ -0,0 +1,190 @@
+// Package guardrails provides composable content policies for agents and
+// runners: deny lists, PII detection, length limits and output schema
+// validation. Policies block, redact or rewrite content, and every violation
+// is reported on an event so that it can be audited.
+package guardrails
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// Action is what a policy does with violating content.
+type Action string
+
+const (
+	// ActionBlock rejects the content. The user gets the guardrails'
+	// blocked response instead.
+	ActionBlock Action = "block"
+
+	// ActionRedact masks the violating parts of the content.
+	ActionRedact Action = "redact"
+
+	// ActionRewrite replaces the content, e.g. truncates it.
+	ActionRewrite Action = "rewrite"
+)
+
+// ErrorCodeViolation is the error code of an event whose content was blocked.
+const ErrorCodeViolation = "GUARDRAIL_VIOLATION"
+
+// ViolationsMetadataKey is the Event.CustomMetadata key listing the
+// violations found in the event's content, or in the message it answers.
+const ViolationsMetadataKey = "guardrail_violations"
+
+// DefaultBlockedResponse is the response given in place of blocked content.
+const DefaultBlockedResponse = "Sorry, I can't help with that request."
+
+// Violation describes content that broke a policy. It never includes the
+// violating content itself, so that it can be logged and stored safely.
+type Violation struct {
+	// Policy names the policy that was violated.
+	Policy string `json:"policy"`
+
+	// Action is what the policy did about it.
+	Action Action `json:"action"`
+
+	// Category refines the violation, e.g. the kind of PII found.
+	Category string `json:"category,omitempty"`
+
+	// Message explains the violation.
+	Message string `json:"message"`
+}
+
+// Policy checks text against a rule.
+type Policy interface {
+	// Apply returns the text, redacted or rewritten as the policy requires,
+	// with the violations found. A violation with ActionBlock rejects the
+	// text.
+	Apply(text string) (string, []Violation)
+}
+
+// Result is the outcome of checking text against policies.
+type Result struct {
+	// Text is the text after redactions and rewrites.
+	Text string
+
+	// Violations lists the violations found, in policy order.
+	Violations []Violation
+
+	// Blocked reports whether a policy blocked the text.
+	Blocked bool
+}
+
+// Guardrails checks the messages going into an agent and the responses
+// coming out of it. Attach it to an LLMAgent with AttachToAgent, or to a
+// runner with AttachToRunner. Only text is checked, not thoughts, so hide
+// thoughts from users where that matters. Output policies only check final
+// responses, not the text of responses calling tools.
+type Guardrails struct {
+	// Input are the policies checked on user messages.
+	Input []Policy
+
+	// Output are the policies checked on agent responses.
+	Output []Policy
+
+	// BlockedResponse replaces blocked content (default
+	// DefaultBlockedResponse).
+	BlockedResponse string
+}
+
+// CheckInput checks a user message against the input policies.
+func (g *Guardrails) CheckInput(text string) *Result {
+	return check(g.Input, text)
+}
+
+// CheckOutput checks an agent response against the output policies.
+func (g *Guardrails) CheckOutput(text string) *Result {
+	return check(g.Output, text)
+}
+
+// check runs text through policies in order. Each policy sees the text as
+// redacted or rewritten by the previous ones; a block stops the checks.
+func check(policies []Policy, text string) *Result {
+	result := &Result{Text: text}
+	for _, policy := range policies {
+		var violations []Violation
+		result.Text, violations = policy.Apply(result.Text)
+		result.Violations = append(result.Violations, violations...)
+		for _, violation := range violations {
+			if violation.Action == ActionBlock {
+				result.Blocked = true
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// checkContent checks the text parts of a content. Thoughts are not
+// checked: they are not JSON, for one. It returns a copy with the
+// redactions and rewrites applied, or content itself when there was nothing
+// to change.
+func checkContent(policies []Policy, content *core.Content) (*core.Content, []Violation, bool) {
+	if content == nil || len(policies) == 0 {
+		return content, nil, false
+	}
+
+	var parts []core.Part
+	var violations []Violation
+	for i, part := range content.Parts {
+		if part.Text == nil {
+			continue
+		}
+
+		result := check(policies, *part.Text)
+		violations = append(violations, result.Violations...)
+		if result.Blocked {
+			return nil, violations, true
+		}
+		if result.Text == *part.Text {
+			continue
+		}
+
+		if parts == nil {
+			parts = append([]core.Part(nil), content.Parts...)
+		}
+		parts[i].Text = ptr.Ptr(result.Text)
+	}
+	if parts == nil {
+		return content, violations, false
+	}
+	return &core.Content{Role: content.Role, Parts: parts}, violations, false
+}
+
+// blockedResponse returns the response given in place of blocked content.
+func (g *Guardrails) blockedResponse() *core.Content {
+	text := g.BlockedResponse
+	if text == "" {
+		text = DefaultBlockedResponse
+	}
+	return &core.Content{Role: "model", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}}
+}
+
+// blockedMessage explains why content was blocked.
+func blockedMessage(violations []Violation) string {
+	var messages []string
+	for _, violation := range violations {
+		if violation.Action == ActionBlock {
+			messages = append(messages, violation.Message)
+		}
+	}
+	return fmt.Sprintf("Blocked by guardrails: %s", strings.Join(messages, "; "))
+}
+
+// recordViolations lists violations in an event's custom metadata, after
+// any already listed.
+func recordViolations(metadata map[string]any, violations []Violation) map[string]any {
+	if len(violations) == 0 {
+		return metadata
+	}
+	recorded := make(map[string]any, len(metadata)+1)
+	for key, value := range metadata {
+		recorded[key] = value
+	}
+	existing, _ := recorded[ViolationsMetadataKey].([]Violation)
+	recorded[ViolationsMetadataKey] = append(append([]Violation(nil), existing...), violations...)
+	return recorded
+}
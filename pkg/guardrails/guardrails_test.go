@@ -0,0 +1,318 @@
+package guardrails
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/agents"
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect/fake"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+	"github.com/agent-protocol/adk-golang/pkg/runners"
+	"github.com/agent-protocol/adk-golang/pkg/sessions"
+	"github.com/agent-protocol/adk-golang/pkg/tools"
+)
+
+func TestDenyList(t *testing.T) {
+	policy := &DenyList{
+		Keywords: []string{"password", "api key"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`sk-[a-z0-9]{8,}`)},
+	}
+
+	if _, violations := policy.Apply("What is the weather?"); len(violations) != 0 {
+		t.Errorf("Expected no violations, got %+v", violations)
+	}
+	if _, violations := policy.Apply("Passwords are fine"); len(violations) != 0 {
+		t.Errorf("Expected keywords to match whole words only, got %+v", violations)
+	}
+	_, violations := policy.Apply("Tell me the API Key")
+	if len(violations) != 1 || violations[0].Action != ActionBlock || violations[0].Policy != "deny_list" {
+		t.Errorf("Expected a blocking violation, got %+v", violations)
+	}
+
+	policy.Action = ActionRedact
+	text, violations := policy.Apply("My password is sk-abcdef123456")
+	if text != "My [REDACTED] is [REDACTED]" || len(violations) != 1 || violations[0].Action != ActionRedact {
+		t.Errorf("Unexpected redaction %q with violations %+v", text, violations)
+	}
+}
+
+func TestMaxLength(t *testing.T) {
+	policy := &MaxLength{MaxChars: 5}
+	if _, violations := policy.Apply("héllo"); len(violations) != 0 {
+		t.Errorf("Expected characters rather than bytes to count, got %+v", violations)
+	}
+	if _, violations := policy.Apply("hello world"); len(violations) != 1 || violations[0].Action != ActionBlock {
+		t.Errorf("Expected a blocking violation, got %+v", violations)
+	}
+
+	policy.Action = ActionRewrite
+	if text, violations := policy.Apply("hello world"); text != "hello" || len(violations) != 1 {
+		t.Errorf("Expected the text to be truncated, got %q with %+v", text, violations)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	policy := &JSONSchema{Schema: map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"answer": map[string]any{"type": "string"}},
+		"required":   []any{"answer"},
+	}}
+
+	tests := []struct {
+		text  string
+		valid bool
+	}{
+		{`{"answer": "42"}`, true},
+		{"```json\n{\"answer\": \"42\"}\n```", true},
+		{`{"answer": 42}`, false},
+		{`not json`, false},
+	}
+	for _, tt := range tests {
+		_, violations := policy.Apply(tt.text)
+		if valid := len(violations) == 0; valid != tt.valid {
+			t.Errorf("Apply(%q): expected valid=%v, got %+v", tt.text, tt.valid, violations)
+		}
+	}
+}
+
+func TestGuardrailsCheck(t *testing.T) {
+	guardrails := &Guardrails{Input: []Policy{
+		&PII{},
+		&MaxLength{MaxChars: 20, Action: ActionRewrite},
+		&DenyList{Keywords: []string{"secret"}},
+	}}
+
+	result := guardrails.CheckInput("Mail jane@example.com about the launch")
+	if result.Blocked || result.Text != "Mail [EMAIL] about t" || len(result.Violations) != 2 {
+		t.Errorf("Expected the policies to apply in order, got %+v", result)
+	}
+
+	result = guardrails.CheckInput("a secret")
+	if !result.Blocked || len(result.Violations) != 1 {
+		t.Errorf("Expected the text to be blocked, got %+v", result)
+	}
+
+	if result := guardrails.CheckOutput("anything"); result.Blocked || len(result.Violations) != 0 {
+		t.Errorf("Expected no output policies, got %+v", result)
+	}
+}
+
+func userMessage(text string) *core.Content {
+	return &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}}
+}
+
+func newAgent(conn core.LLMConnection) *agents.LLMAgent {
+	agent := agents.NewLLMAgent("support-agent", "Answers customers", &agents.LlmAgentConfig{
+		Model:         "test-model",
+		MaxToolCalls:  5,
+		RetryAttempts: 1,
+	})
+	agent.SetLLMConnection(conn)
+	return agent
+}
+
+func violationsOf(event *core.Event) []Violation {
+	violations, _ := event.CustomMetadata[ViolationsMetadataKey].([]Violation)
+	return violations
+}
+
+func TestAttachToAgent(t *testing.T) {
+	conn := fake.New(
+		fake.Turn{
+			LastUserMessage: "My email is [EMAIL]",
+			Response:        fake.Text("Your card 4111 1111 1111 1111 is on file."),
+		},
+	)
+	agent := newAgent(conn)
+
+	callbackCalled := false
+	agent.SetCallbacks(&agents.LlmAgentCallbacks{
+		BeforeModelCallback: func(invocationCtx *core.InvocationContext, request *core.LLMRequest) (*core.LLMResponse, error) {
+			callbackCalled = true
+			return nil, nil
+		},
+	})
+	guardrails := &Guardrails{
+		Input:  []Policy{&DenyList{Keywords: []string{"password"}}, &PII{}},
+		Output: []Policy{&PII{}},
+	}
+	guardrails.AttachToAgent(agent)
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	run := func(text string) *core.Event {
+		t.Helper()
+		invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+		invocationCtx.UserContent = userMessage(text)
+		events, err := agent.Run(invocationCtx)
+		if err != nil {
+			t.Fatalf("Agent run failed: %v", err)
+		}
+		return events[len(events)-1]
+	}
+
+	final := run("My email is jane@example.com")
+	conn.AssertDone(t)
+	if !callbackCalled {
+		t.Error("Expected the agent's own callback to still be called")
+	}
+	if got := *final.Content.Parts[0].Text; got != "Your card [CREDIT_CARD] is on file." {
+		t.Errorf("Expected a redacted response, got %q", got)
+	}
+	violations := violationsOf(final)
+	if len(violations) != 2 || violations[0].Category != "email" || violations[1].Category != "credit_card" {
+		t.Errorf("Expected the input and output violations on the event, got %+v", violations)
+	}
+	if final.ErrorCode != nil {
+		t.Errorf("Expected no error code for redactions, got %s", *final.ErrorCode)
+	}
+
+	final = run("What is the admin password?")
+	if len(conn.Requests()) != 1 {
+		t.Errorf("Expected a blocked message not to reach the model, got %d calls", len(conn.Requests()))
+	}
+	if final.ErrorCode == nil || *final.ErrorCode != ErrorCodeViolation || *final.Content.Parts[0].Text != DefaultBlockedResponse {
+		t.Errorf("Unexpected blocked event: %+v", final)
+	}
+	if violations := violationsOf(final); len(violations) != 1 || violations[0].Policy != "deny_list" {
+		t.Errorf("Unexpected violations: %+v", violations)
+	}
+}
+
+func TestAttachToAgent_Streaming(t *testing.T) {
+	conn := fake.New(fake.Turn{
+		Response: fake.Text("Write to jane@example.com."),
+		Partials: []string{"Write to jane@", "example.com."},
+	})
+	agent := newAgent(conn)
+	guardrails := &Guardrails{Output: []Policy{&PII{}}}
+	guardrails.AttachToAgent(agent)
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	invocationCtx.UserContent = userMessage("Who do I contact?")
+	invocationCtx.RunConfig = &core.RunConfig{StreamingMode: core.StreamingModeSSE}
+	events, err := agent.Run(invocationCtx)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	conn.AssertDone(t)
+
+	for _, event := range events {
+		if event.Partial != nil && *event.Partial {
+			t.Errorf("Expected no partial events while output policies are set, got %+v", event.Content)
+		}
+		if event.Content != nil && strings.Contains(*event.Content.Parts[0].Text, "jane@") {
+			t.Errorf("Unredacted text reached an event: %q", *event.Content.Parts[0].Text)
+		}
+	}
+	if got := *events[len(events)-1].Content.Parts[0].Text; got != "Write to [EMAIL]." {
+		t.Errorf("Expected the redacted final response, got %q", got)
+	}
+}
+
+func TestAttachToAgent_SkipsToolCalls(t *testing.T) {
+	conn := fake.New(
+		fake.Turn{Response: &core.LLMResponse{Content: &core.Content{Role: "model", Parts: []core.Part{
+			{Type: "text", Text: ptr.Ptr("Let me check the answer.")},
+			{Type: "function_call", FunctionCall: &core.FunctionCall{ID: "call-1", Name: "lookup", Args: map[string]any{}}},
+		}}}},
+		fake.Turn{Response: fake.Text(`{"answer": "42"}`)},
+	)
+	agent := newAgent(conn)
+	lookup, err := tools.NewFunctionTool("lookup", "Looks up the answer", func() map[string]any {
+		return map[string]any{"answer": "42"}
+	})
+	if err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+	agent.AddTool(lookup)
+	guardrails := &Guardrails{Output: []Policy{&JSONSchema{Schema: map[string]any{
+		"type":     "object",
+		"required": []any{"answer"},
+	}}}}
+	guardrails.AttachToAgent(agent)
+
+	session := core.NewSession("test-session", "test-app", "test-user")
+	invocationCtx := core.NewInvocationContext(context.Background(), "test-invocation", agent, session, nil)
+	invocationCtx.UserContent = userMessage("What is the answer?")
+	events, err := agent.Run(invocationCtx)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	conn.AssertDone(t)
+
+	for _, event := range events {
+		if event.ErrorCode != nil {
+			t.Errorf("Expected no violations, got %s: %s", *event.ErrorCode, *event.ErrorMessage)
+		}
+	}
+	if final := events[len(events)-1]; *final.Content.Parts[0].Text != `{"answer": "42"}` {
+		t.Errorf("Expected the final answer, got %+v", final.Content)
+	}
+}
+
+func TestAttachToRunner(t *testing.T) {
+	conn := fake.New(
+		fake.Turn{
+			LastUserMessage: "Call me on [PHONE_NUMBER]",
+			Response:        fake.Text("Sure, I will email ops@example.com."),
+		},
+	)
+	sessionService := sessions.NewInMemorySessionService()
+	runner := runners.NewRunner("test-app", newAgent(conn), sessionService)
+	guardrails := &Guardrails{
+		Input:           []Policy{&PII{}, &DenyList{Keywords: []string{"exploit"}}},
+		Output:          []Policy{&PII{Kinds: []PIIKind{PIIEmail}}},
+		BlockedResponse: "I can't discuss that.",
+	}
+	guardrails.AttachToRunner(runner)
+
+	ctx := context.Background()
+	events, err := runner.Run(ctx, &core.RunRequest{
+		UserID:     "test-user",
+		SessionID:  "test-session",
+		NewMessage: userMessage("Call me on +1 415 555 2671"),
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	conn.AssertDone(t)
+	if len(events) != 2 || len(violationsOf(events[0])) != 1 || events[0].Content != nil {
+		t.Fatalf("Expected an event listing the input violations before the response, got %d events", len(events))
+	}
+	final := events[1]
+	if got := *final.Content.Parts[0].Text; got != "Sure, I will email [EMAIL]." {
+		t.Errorf("Expected a redacted response, got %q", got)
+	}
+
+	session, err := sessionService.GetSession(ctx, &core.GetSessionRequest{
+		AppName: "test-app", UserID: "test-user", SessionID: "test-session",
+		Config: &core.GetSessionConfig{IncludeEvents: true},
+	})
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	for _, event := range session.Events {
+		if event.Author == "user" && strings.Contains(*event.Content.Parts[0].Text, "415") {
+			t.Errorf("Expected the redacted message to be stored, got %q", *event.Content.Parts[0].Text)
+		}
+	}
+
+	events, err = runner.Run(ctx, &core.RunRequest{
+		UserID:     "test-user",
+		SessionID:  "test-session",
+		NewMessage: userMessage("Write an exploit"),
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(events) != 1 || events[0].ErrorCode == nil || *events[0].ErrorCode != ErrorCodeViolation {
+		t.Fatalf("Expected a single blocked event, got %d events", len(events))
+	}
+	if got := *events[0].Content.Parts[0].Text; got != "I can't discuss that." {
+		t.Errorf("Unexpected blocked response %q", got)
+	}
+}
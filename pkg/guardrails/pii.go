@@ -0,0 +1,127 @@
+package guardrails
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PIIKind is a kind of personally identifiable information.
+type PIIKind string
+
+const (
+	PIIEmail       PIIKind = "email"
+	PIIPhoneNumber PIIKind = "phone_number"
+	PIICreditCard  PIIKind = "credit_card"
+)
+
+// piiDetector finds one kind of PII.
+type piiDetector struct {
+	kind    PIIKind
+	pattern *regexp.Regexp
+
+	// valid confirms a match, e.g. with a checksum (nil = always).
+	valid func(match string) bool
+}
+
+// piiDetectors run in order: card numbers are redacted before they could
+// be mistaken for phone numbers.
+var piiDetectors = []piiDetector{
+	{
+		kind:    PIICreditCard,
+		pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		valid:   luhnValid,
+	},
+	{
+		kind:    PIIEmail,
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	{
+		kind:    PIIPhoneNumber,
+		pattern: regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{2,4}\) ?|\b\d{2,4}[ .-])\d{3,4}[ .-]?\d{3,4}\b`),
+	},
+}
+
+// PII detects email addresses, phone numbers and credit card numbers.
+// Credit card numbers must pass the Luhn check, which rules out most other
+// long numbers.
+type PII struct {
+	// Kinds are the kinds of PII detected (default all).
+	Kinds []PIIKind
+
+	// Action is ActionRedact (default) or ActionBlock.
+	Action Action
+}
+
+var _ Policy = (*PII)(nil)
+
+// Apply implements Policy. Redacted PII is replaced by its kind, e.g.
+// "[EMAIL]".
+func (p *PII) Apply(text string) (string, []Violation) {
+	action := p.Action
+	if action == "" {
+		action = ActionRedact
+	}
+
+	var violations []Violation
+	for _, detector := range piiDetectors {
+		if !p.detects(detector.kind) {
+			continue
+		}
+
+		found := 0
+		replacement := "[" + strings.ToUpper(string(detector.kind)) + "]"
+		text = detector.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if detector.valid != nil && !detector.valid(match) {
+				return match
+			}
+			found++
+			return replacement
+		})
+		if found == 0 {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Policy:   "pii",
+			Action:   action,
+			Category: string(detector.kind),
+			Message:  fmt.Sprintf("content contains PII: %d %s", found, strings.ReplaceAll(string(detector.kind), "_", " ")),
+		})
+	}
+	return text, violations
+}
+
+// detects reports whether the policy detects a kind of PII.
+func (p *PII) detects(kind PIIKind) bool {
+	if len(p.Kinds) == 0 {
+		return true
+	}
+	for _, k := range p.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// luhnValid reports whether the digits of a number pass the Luhn check.
+func luhnValid(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
@@ -0,0 +1,84 @@
+package guardrails
+
+import (
+	"testing"
+)
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"4111 1111 1111 1111", true},
+		{"5500-0000-0000-0004", true},
+		{"378282246310005", true},
+		{"4111 1111 1111 1112", false},
+		{"123456789012", false},
+	}
+	for _, tt := range tests {
+		if got := luhnValid(tt.number); got != tt.want {
+			t.Errorf("luhnValid(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestPII(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     *PII
+		text       string
+		want       string
+		categories []string
+	}{
+		{
+			name:       "email",
+			policy:     &PII{},
+			text:       "Write to jane.doe+news@example.co.uk today",
+			want:       "Write to [EMAIL] today",
+			categories: []string{"email"},
+		},
+		{
+			name:       "phone numbers",
+			policy:     &PII{},
+			text:       "Call +1 415 555 2671 or (02) 9374 4000",
+			want:       "Call [PHONE_NUMBER] or [PHONE_NUMBER]",
+			categories: []string{"phone_number"},
+		},
+		{
+			name:       "credit card with valid checksum",
+			policy:     &PII{},
+			text:       "Card 4111-1111-1111-1111, order 4111111111111112",
+			want:       "Card [CREDIT_CARD], order 4111111111111112",
+			categories: []string{"credit_card"},
+		},
+		{
+			name:       "selected kinds",
+			policy:     &PII{Kinds: []PIIKind{PIIEmail}},
+			text:       "jane@example.com, 4111 1111 1111 1111",
+			want:       "[EMAIL], 4111 1111 1111 1111",
+			categories: []string{"email"},
+		},
+		{
+			name:   "no PII",
+			policy: &PII{},
+			text:   "Order 12345 ships on 2024-05-01",
+			want:   "Order 12345 ships on 2024-05-01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, violations := tt.policy.Apply(tt.text)
+			if got != tt.want {
+				t.Errorf("Unexpected text: got %q, want %q", got, tt.want)
+			}
+			if len(violations) != len(tt.categories) {
+				t.Fatalf("Expected %d violations, got %+v", len(tt.categories), violations)
+			}
+			for i, violation := range violations {
+				if violation.Category != tt.categories[i] || violation.Action != ActionRedact || violation.Policy != "pii" {
+					t.Errorf("Unexpected violation: %+v", violation)
+				}
+			}
+		})
+	}
+}
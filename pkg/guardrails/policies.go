@@ -0,0 +1,130 @@
+package guardrails
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agent-protocol/adk-golang/pkg/agents"
+)
+
+// DefaultRedaction replaces content redacted by a DenyList.
+const DefaultRedaction = "[REDACTED]"
+
+// DenyList rejects or redacts keywords and regular expressions.
+type DenyList struct {
+	// Name names the policy in violations (default "deny_list").
+	Name string
+
+	// Keywords are matched as whole words, ignoring case.
+	Keywords []string
+
+	// Patterns are matched as is.
+	Patterns []*regexp.Regexp
+
+	// Action is ActionBlock (default) or ActionRedact.
+	Action Action
+
+	// Replacement replaces redacted matches (default DefaultRedaction).
+	Replacement string
+}
+
+var _ Policy = (*DenyList)(nil)
+
+// Apply implements Policy.
+func (d *DenyList) Apply(text string) (string, []Violation) {
+	name := d.Name
+	if name == "" {
+		name = "deny_list"
+	}
+	action := d.Action
+	if action == "" {
+		action = ActionBlock
+	}
+	replacement := d.Replacement
+	if replacement == "" {
+		replacement = DefaultRedaction
+	}
+
+	patterns := d.Patterns
+	if len(d.Keywords) > 0 {
+		quoted := make([]string, len(d.Keywords))
+		for i, keyword := range d.Keywords {
+			quoted[i] = regexp.QuoteMeta(keyword)
+		}
+		patterns = append([]*regexp.Regexp{regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)}, patterns...)
+	}
+
+	matches := 0
+	for _, pattern := range patterns {
+		found := len(pattern.FindAllStringIndex(text, -1))
+		if found == 0 {
+			continue
+		}
+		matches += found
+		if action == ActionBlock {
+			break
+		}
+		text = pattern.ReplaceAllLiteralString(text, replacement)
+	}
+	if matches == 0 {
+		return text, nil
+	}
+	return text, []Violation{{
+		Policy:  name,
+		Action:  action,
+		Message: fmt.Sprintf("content matches the %s", strings.ReplaceAll(name, "_", " ")),
+	}}
+}
+
+// MaxLength limits the length of content in characters.
+type MaxLength struct {
+	// MaxChars is the maximum number of characters.
+	MaxChars int
+
+	// Action is ActionBlock (default) or ActionRewrite, which truncates.
+	Action Action
+}
+
+var _ Policy = (*MaxLength)(nil)
+
+// Apply implements Policy.
+func (m *MaxLength) Apply(text string) (string, []Violation) {
+	length := utf8.RuneCountInString(text)
+	if m.MaxChars <= 0 || length <= m.MaxChars {
+		return text, nil
+	}
+	action := m.Action
+	if action == "" {
+		action = ActionBlock
+	}
+	if action == ActionRewrite {
+		text = string([]rune(text)[:m.MaxChars])
+	}
+	return text, []Violation{{
+		Policy:  "max_length",
+		Action:  action,
+		Message: fmt.Sprintf("content has %d characters, more than the maximum of %d", length, m.MaxChars),
+	}}
+}
+
+// JSONSchema blocks responses that are not JSON matching Schema. Markdown
+// code fences around the JSON are tolerated.
+type JSONSchema struct {
+	Schema map[string]any
+}
+
+var _ Policy = (*JSONSchema)(nil)
+
+// Apply implements Policy.
+func (j *JSONSchema) Apply(text string) (string, []Violation) {
+	if _, err := agents.ParseStructuredOutput(text, j.Schema); err != nil {
+		return text, []Violation{{
+			Policy:  "json_schema",
+			Action:  ActionBlock,
+			Message: err.Error(),
+		}}
+	}
+	return text, nil
+}
@@ -0,0 +1,64 @@
+package guardrails
+
+import (
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+	"github.com/agent-protocol/adk-golang/pkg/runners"
+)
+
+var _ runners.Filter = (*Guardrails)(nil)
+
+// AttachToRunner checks the messages and events of a runner: new messages
+// against the input policies, and the agents' events against the output
+// policies. This covers every agent of the runner, including remote ones.
+// Agents keep their own events in the invocation's session as they were,
+// so attach the guardrails to an LLMAgent as well to keep them out of its
+// later requests.
+func (g *Guardrails) AttachToRunner(runner *runners.RunnerImpl) {
+	runner.AddFilter(g)
+}
+
+// FilterMessage implements runners.Filter. A message with violations is
+// answered by an event listing them; a blocked message is answered with the
+// blocked response and not stored.
+func (g *Guardrails) FilterMessage(invocationCtx *core.InvocationContext, message *core.Content) (*core.Content, *core.Event, error) {
+	checked, violations, blocked := checkContent(g.Input, message)
+	if len(violations) == 0 {
+		return message, nil, nil
+	}
+
+	event := core.NewEvent(invocationCtx.InvocationID, invocationCtx.Agent.Name())
+	event.CustomMetadata = recordViolations(nil, violations)
+	if !blocked {
+		return checked, event, nil
+	}
+	event.Content = g.blockedResponse()
+	event.ErrorCode = ptr.Ptr(ErrorCodeViolation)
+	event.ErrorMessage = ptr.Ptr(blockedMessage(violations))
+	event.TurnComplete = ptr.Ptr(true)
+	return nil, event, nil
+}
+
+// FilterEvent implements runners.Filter. An event with violations is
+// replaced by a copy with the redactions and rewrites applied, or with the
+// blocked response, listing the violations. Events calling tools are not
+// checked, as they carry no answer yet.
+func (g *Guardrails) FilterEvent(invocationCtx *core.InvocationContext, event *core.Event) (*core.Event, error) {
+	if event.Author == "user" || hasFunctionCalls(event.Content) {
+		return event, nil
+	}
+	checked, violations, blocked := checkContent(g.Output, event.Content)
+	if len(violations) == 0 {
+		return event, nil
+	}
+
+	filtered := *event
+	filtered.Content = checked
+	if blocked {
+		filtered.Content = g.blockedResponse()
+		filtered.ErrorCode = ptr.Ptr(ErrorCodeViolation)
+		filtered.ErrorMessage = ptr.Ptr(blockedMessage(violations))
+	}
+	filtered.CustomMetadata = recordViolations(event.CustomMetadata, violations)
+	return &filtered, nil
+}
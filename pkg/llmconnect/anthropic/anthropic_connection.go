@@ -0,0 +1,581 @@
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// APIVersion is the Messages API version sent in the anthropic-version header.
+const APIVersion = "2023-06-01"
+
+var _ core.LLMConnection = (*AnthropicConnection)(nil)
+
+// AnthropicConnection implements the LLMConnection interface for the
+// Anthropic Messages API.
+type AnthropicConnection struct {
+	baseURL    string
+	httpClient *http.Client
+	model      string
+	config     *AnthropicConfig
+}
+
+// AnthropicConfig contains configuration options for Anthropic connections.
+type AnthropicConfig struct {
+	// BaseURL is the API root without the version, e.g. https://api.anthropic.com.
+	BaseURL     string        `json:"base_url"`
+	APIKey      string        `json:"-"`
+	Model       string        `json:"model"`
+	Temperature *float32      `json:"temperature,omitempty"`
+	TopP        *float32      `json:"top_p,omitempty"`
+	TopK        *int          `json:"top_k,omitempty"`
+	Timeout     time.Duration `json:"timeout"`
+
+	// MaxTokens is required by the API; it is used when the request sets none.
+	MaxTokens int `json:"max_tokens"`
+}
+
+// SignatureMetadataKey is the part metadata key holding the signature of a
+// thought, which the API requires to accept the thought back.
+const SignatureMetadataKey = "signature"
+
+// minThinkingBudget is the smallest thinking budget accepted by the API.
+const minThinkingBudget = 1024
+
+// DefaultAnthropicConfig returns a default configuration for Anthropic.
+// The API key and base URL are read from ANTHROPIC_API_KEY and ANTHROPIC_BASE_URL.
+func DefaultAnthropicConfig() *AnthropicConfig {
+	baseURL := os.Getenv("ANTHROPIC_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com"
+	}
+	return &AnthropicConfig{
+		BaseURL:   baseURL,
+		APIKey:    os.Getenv("ANTHROPIC_API_KEY"),
+		Model:     "claude-3-5-sonnet-latest",
+		MaxTokens: 4096,
+		Timeout:   120 * time.Second,
+	}
+}
+
+// NewAnthropicConnection creates a new Anthropic connection with the given configuration.
+func NewAnthropicConnection(config *AnthropicConfig) *AnthropicConnection {
+	if config == nil {
+		config = DefaultAnthropicConfig()
+	}
+
+	return &AnthropicConnection{
+		baseURL: strings.TrimSuffix(config.BaseURL, "/"),
+		model:   config.Model,
+		config:  config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// GenerateContent sends a Messages API request and returns the response.
+func (c *AnthropicConnection) GenerateContent(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
+	msgReq, err := c.convertToAnthropicRequest(request, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := c.makeHTTPRequest(ctx, "/v1/messages", msgReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var msgResp MessagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&msgResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return c.convertFromAnthropicResponse(&msgResp)
+}
+
+// GenerateContentStream sends a streaming Messages API request. Partial
+// responses carry the text delta of each event; the last response is complete
+// and holds the full text, the assembled tool calls and the usage. A stream
+// failure, including an error event, is reported as a final response with
+// Metadata["error"] set.
+func (c *AnthropicConnection) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
+	msgReq, err := c.convertToAnthropicRequest(request, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := c.makeHTTPRequest(ctx, "/v1/messages", msgReq)
+	if err != nil {
+		return nil, err
+	}
+
+	responseChan := make(chan *core.LLMResponse, 10)
+
+	go func() {
+		defer close(responseChan)
+		defer resp.Body.Close()
+
+		send := func(response *core.LLMResponse) bool {
+			select {
+			case responseChan <- response:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		acc := &streamAccumulator{}
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	events:
+		for scanner.Scan() {
+			// The event name is repeated in the payload, so only data lines matter
+			data, found := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+			if !found {
+				continue
+			}
+
+			var event StreamEvent
+			if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+				send(streamError(fmt.Errorf("failed to decode stream event: %w", err)))
+				return
+			}
+
+			switch event.Type {
+			case "error":
+				if event.Error != nil {
+					send(streamError(event.Error))
+				} else {
+					send(streamError(fmt.Errorf("stream error event without details")))
+				}
+				return
+			case "message_stop":
+				break events
+			}
+
+			if delta, thought := acc.add(&event); delta != "" {
+				part := core.Part{Type: "text", Text: ptr.Ptr(delta)}
+				if thought {
+					part = core.NewThoughtPart(delta)
+				}
+				partial := &core.LLMResponse{
+					Content: &core.Content{
+						Role:  "assistant",
+						Parts: []core.Part{part},
+					},
+					Partial: ptr.Ptr(true),
+				}
+				if !send(partial) {
+					return
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			send(streamError(fmt.Errorf("failed to read stream: %w", err)))
+			return
+		}
+
+		final, err := c.convertFromAnthropicResponse(acc.response())
+		if err != nil {
+			send(streamError(err))
+			return
+		}
+		send(final)
+	}()
+
+	return responseChan, nil
+}
+
+// Close closes the connection (no-op for HTTP-based connections).
+func (c *AnthropicConnection) Close(ctx context.Context) error {
+	return nil
+}
+
+// convertToAnthropicRequest converts an ADK LLMRequest to the Messages API format.
+func (c *AnthropicConnection) convertToAnthropicRequest(request *core.LLMRequest, stream bool) (*MessagesRequest, error) {
+	if request == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	msgReq := &MessagesRequest{
+		Model:     c.model,
+		MaxTokens: c.config.MaxTokens,
+		Stream:    stream,
+		Messages:  make([]Message, 0, len(request.Contents)),
+	}
+
+	// System contents are hoisted into the top-level system field; the config
+	// instruction is used when the contents carry none.
+	var systemTexts []string
+	for _, content := range request.Contents {
+		if content.Role == "system" {
+			for _, part := range content.Parts {
+				if part.Text != nil && *part.Text != "" {
+					systemTexts = append(systemTexts, *part.Text)
+				}
+			}
+			continue
+		}
+
+		message, err := c.convertContent(content)
+		if err != nil {
+			return nil, err
+		}
+		if len(message.Content) == 0 {
+			continue
+		}
+
+		// The API requires alternating roles, so consecutive turns of the
+		// same role are merged.
+		if n := len(msgReq.Messages); n > 0 && msgReq.Messages[n-1].Role == message.Role {
+			msgReq.Messages[n-1].Content = append(msgReq.Messages[n-1].Content, message.Content...)
+			continue
+		}
+		msgReq.Messages = append(msgReq.Messages, message)
+	}
+	if len(systemTexts) == 0 && request.Config != nil && request.Config.SystemInstruction != nil {
+		systemTexts = append(systemTexts, *request.Config.SystemInstruction)
+	}
+	msgReq.System = strings.Join(systemTexts, "\n\n")
+
+	for _, tool := range request.Tools {
+		schema := tool.Parameters
+		if schema == nil {
+			schema = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		msgReq.Tools = append(msgReq.Tools, Tool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: schema,
+		})
+	}
+
+	// Apply connection-level configuration first
+	msgReq.Temperature = c.config.Temperature
+	msgReq.TopP = c.config.TopP
+	msgReq.TopK = c.config.TopK
+
+	// Apply request-level configuration (overrides connection config)
+	if request.Config != nil {
+		if request.Config.Temperature != nil {
+			msgReq.Temperature = request.Config.Temperature
+		}
+		if request.Config.MaxTokens != nil {
+			msgReq.MaxTokens = *request.Config.MaxTokens
+		}
+		if request.Config.TopP != nil {
+			msgReq.TopP = request.Config.TopP
+		}
+		if request.Config.TopK != nil {
+			msgReq.TopK = request.Config.TopK
+		}
+		if request.Config.Thinking != nil {
+			budget := max(request.Config.Thinking.BudgetTokens, minThinkingBudget)
+			if msgReq.MaxTokens <= budget {
+				msgReq.MaxTokens = budget + c.config.MaxTokens
+			}
+			msgReq.Thinking = &Thinking{Type: "enabled", BudgetTokens: budget}
+			// Sampling parameters are not supported together with thinking
+			msgReq.Temperature = nil
+			msgReq.TopP = nil
+			msgReq.TopK = nil
+		}
+	}
+
+	return msgReq, nil
+}
+
+// convertContent converts one ADK content into a Messages API turn. Tool
+// results are sent by the "user" role as required by the API.
+func (c *AnthropicConnection) convertContent(content core.Content) (Message, error) {
+	message := Message{Role: c.mapRole(content.Role)}
+
+	for _, part := range content.Parts {
+		switch {
+		case part.Thought != nil:
+			// Thinking is only accepted back with the signature it came with
+			signature, _ := part.Metadata[SignatureMetadataKey].(string)
+			if signature != "" && *part.Thought != "" {
+				message.Content = append(message.Content, ContentBlock{
+					Type:      "thinking",
+					Thinking:  *part.Thought,
+					Signature: signature,
+				})
+			}
+		case part.FunctionCall != nil:
+			args := part.FunctionCall.Args
+			if args == nil {
+				args = map[string]any{}
+			}
+			input, err := json.Marshal(args)
+			if err != nil {
+				return Message{}, fmt.Errorf("failed to marshal arguments of %s: %w", part.FunctionCall.Name, err)
+			}
+			message.Content = append(message.Content, ContentBlock{
+				Type:  "tool_use",
+				ID:    toolUseID(part.FunctionCall.ID, part.FunctionCall.Name),
+				Name:  part.FunctionCall.Name,
+				Input: input,
+			})
+		case part.FunctionResponse != nil:
+			result, err := json.Marshal(part.FunctionResponse.Response)
+			if err != nil {
+				return Message{}, fmt.Errorf("failed to marshal response of %s: %w", part.FunctionResponse.Name, err)
+			}
+			_, isError := part.FunctionResponse.Response["error"]
+			message.Role = "user"
+			message.Content = append(message.Content, ContentBlock{
+				Type:      "tool_result",
+				ToolUseID: toolUseID(part.FunctionResponse.ID, part.FunctionResponse.Name),
+				Content:   string(result),
+				IsError:   isError,
+			})
+		case part.Text != nil && *part.Text != "":
+			message.Content = append(message.Content, ContentBlock{
+				Type: "text",
+				Text: *part.Text,
+			})
+		}
+	}
+
+	return message, nil
+}
+
+// convertFromAnthropicResponse converts a Messages API response to ADK format.
+func (c *AnthropicConnection) convertFromAnthropicResponse(resp *MessagesResponse) (*core.LLMResponse, error) {
+	response := &core.LLMResponse{
+		Partial:  ptr.Ptr(false),
+		Metadata: make(map[string]any),
+	}
+
+	content := &core.Content{
+		Role:  "assistant",
+		Parts: make([]core.Part, 0, len(resp.Content)),
+	}
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "thinking":
+			if block.Thinking != "" {
+				part := core.NewThoughtPart(block.Thinking)
+				if block.Signature != "" {
+					part.Metadata = map[string]any{SignatureMetadataKey: block.Signature}
+				}
+				content.Parts = append(content.Parts, part)
+			}
+		case "text":
+			if block.Text != "" {
+				content.Parts = append(content.Parts, core.Part{
+					Type: "text",
+					Text: ptr.Ptr(block.Text),
+				})
+			}
+		case "tool_use":
+			args := make(map[string]any)
+			if len(block.Input) > 0 {
+				if err := json.Unmarshal(block.Input, &args); err != nil {
+					return nil, fmt.Errorf("invalid input for tool use %s: %w", block.Name, err)
+				}
+			}
+			content.Parts = append(content.Parts, core.Part{
+				Type: "function_call",
+				FunctionCall: &core.FunctionCall{
+					ID:   block.ID,
+					Name: block.Name,
+					Args: args,
+				},
+			})
+		}
+	}
+	if len(content.Parts) > 0 {
+		response.Content = content
+	}
+
+	if resp.StopReason != "" {
+		response.Metadata["finish_reason"] = resp.StopReason
+	}
+	if resp.ID != "" {
+		response.Metadata["id"] = resp.ID
+	}
+	if resp.Model != "" {
+		response.Metadata["model"] = resp.Model
+	}
+	if resp.Usage != nil {
+		response.Metadata["prompt_tokens"] = resp.Usage.InputTokens
+		response.Metadata["completion_tokens"] = resp.Usage.OutputTokens
+		response.Metadata["total_tokens"] = resp.Usage.InputTokens + resp.Usage.OutputTokens
+		if resp.Usage.CacheReadInputTokens > 0 {
+			response.Metadata["cached_tokens"] = resp.Usage.CacheReadInputTokens
+		}
+		response.Usage = &core.Usage{
+			InputTokens:  resp.Usage.InputTokens,
+			OutputTokens: resp.Usage.OutputTokens,
+			CachedTokens: resp.Usage.CacheReadInputTokens,
+			TotalTokens:  resp.Usage.InputTokens + resp.Usage.OutputTokens,
+			LLMCalls:     1,
+		}
+	}
+
+	return response, nil
+}
+
+// makeHTTPRequest posts a JSON payload to the API.
+func (c *AnthropicConnection) makeHTTPRequest(ctx context.Context, endpoint string, payload any) (*http.Response, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("anthropic-version", APIVersion)
+	if c.config.APIKey != "" {
+		req.Header.Set("x-api-key", c.config.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		var errResp errorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != nil {
+			errResp.Error.StatusCode = resp.StatusCode
+			return nil, errResp.Error
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	return resp, nil
+}
+
+// mapRole maps ADK roles to Messages API roles.
+func (c *AnthropicConnection) mapRole(role string) string {
+	switch role {
+	case "agent", "model", "assistant":
+		return "assistant"
+	default:
+		return "user"
+	}
+}
+
+// toolUseID returns the ID linking a tool use to its result. The API
+// requires one, so the function name is used when the ADK part has none.
+func toolUseID(id, name string) string {
+	if id != "" {
+		return id
+	}
+	return "toolu_" + name
+}
+
+// streamError wraps a stream failure in a final response.
+func streamError(err error) *core.LLMResponse {
+	return &core.LLMResponse{
+		Partial:  ptr.Ptr(false),
+		Metadata: map[string]any{"error": err.Error()},
+	}
+}
+
+// streamAccumulator assembles the events of a stream into a complete response.
+type streamAccumulator struct {
+	resp   MessagesResponse
+	blocks []*ContentBlock
+	inputs map[int]*strings.Builder
+}
+
+// add merges an event and returns its text delta and whether the delta is
+// thinking.
+func (a *streamAccumulator) add(event *StreamEvent) (string, bool) {
+	switch event.Type {
+	case "message_start":
+		if event.Message != nil {
+			a.resp = *event.Message
+			a.resp.Content = nil
+		}
+	case "content_block_start":
+		if event.ContentBlock == nil {
+			return "", false
+		}
+		for len(a.blocks) <= event.Index {
+			a.blocks = append(a.blocks, nil)
+		}
+		block := *event.ContentBlock
+		// The start event carries an empty input; the JSON arrives in deltas
+		block.Input = nil
+		a.blocks[event.Index] = &block
+		if block.Type == "thinking" {
+			return block.Thinking, true
+		}
+		return block.Text, false
+	case "content_block_delta":
+		if event.Delta == nil || event.Index >= len(a.blocks) || a.blocks[event.Index] == nil {
+			return "", false
+		}
+		switch event.Delta.Type {
+		case "text_delta":
+			a.blocks[event.Index].Text += event.Delta.Text
+			return event.Delta.Text, false
+		case "thinking_delta":
+			a.blocks[event.Index].Thinking += event.Delta.Thinking
+			return event.Delta.Thinking, true
+		case "signature_delta":
+			a.blocks[event.Index].Signature += event.Delta.Signature
+		case "input_json_delta":
+			if a.inputs == nil {
+				a.inputs = make(map[int]*strings.Builder)
+			}
+			input, ok := a.inputs[event.Index]
+			if !ok {
+				input = &strings.Builder{}
+				a.inputs[event.Index] = input
+			}
+			input.WriteString(event.Delta.PartialJSON)
+		}
+	case "message_delta":
+		if event.Delta != nil && event.Delta.StopReason != "" {
+			a.resp.StopReason = event.Delta.StopReason
+		}
+		if event.Usage != nil {
+			if a.resp.Usage == nil {
+				a.resp.Usage = &Usage{}
+			}
+			a.resp.Usage.OutputTokens = event.Usage.OutputTokens
+		}
+	}
+	return "", false
+}
+
+// response returns the accumulated response.
+func (a *streamAccumulator) response() *MessagesResponse {
+	resp := a.resp
+	resp.Content = make([]ContentBlock, 0, len(a.blocks))
+	for i, block := range a.blocks {
+		if block == nil {
+			continue
+		}
+		if input, ok := a.inputs[i]; ok && block.Type == "tool_use" {
+			block.Input = json.RawMessage(input.String())
+		}
+		resp.Content = append(resp.Content, *block)
+	}
+	return &resp
+}
@@ -0,0 +1,296 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+func newTestConnection(t *testing.T, handler http.HandlerFunc) *AnthropicConnection {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := DefaultAnthropicConfig()
+	config.BaseURL = server.URL + "/"
+	config.APIKey = "test-key"
+	config.Model = "claude-test"
+	return NewAnthropicConnection(config)
+}
+
+func TestGenerateContent_ToolUseAndUsage(t *testing.T) {
+	var received MessagesRequest
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") != APIVersion {
+			t.Errorf("Unexpected headers: %v", r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		fmt.Fprint(w, `{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"model": "claude-test",
+			"content": [
+				{"type": "text", "text": "Checking Paris."},
+				{"type": "tool_use", "id": "toolu_abc", "name": "get_weather", "input": {"location": "Paris"}}
+			],
+			"stop_reason": "tool_use",
+			"usage": {"input_tokens": 20, "output_tokens": 9, "cache_read_input_tokens": 4}
+		}`)
+	})
+
+	request := &core.LLMRequest{
+		Contents: []core.Content{
+			{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Weather in Berlin and Paris?")}}},
+			{Role: "agent", Parts: []core.Part{{
+				Type:         "function_call",
+				FunctionCall: &core.FunctionCall{ID: "toolu_1", Name: "get_weather", Args: map[string]any{"location": "Berlin"}},
+			}}},
+			{Role: "agent", Parts: []core.Part{{
+				Type:             "function_response",
+				FunctionResponse: &core.FunctionResponse{ID: "toolu_1", Name: "get_weather", Response: map[string]any{"temp": 21}},
+			}}},
+			{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("And Paris?")}}},
+		},
+		Config: &core.LLMConfig{
+			SystemInstruction: ptr.Ptr("Be brief."),
+			MaxTokens:         ptr.Ptr(256),
+			Temperature:       ptr.Float32(0.3),
+		},
+		Tools: []*core.FunctionDeclaration{{Name: "get_weather", Description: "Get weather"}},
+	}
+
+	response, err := conn.GenerateContent(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+
+	// Request conversion
+	if received.Model != "claude-test" || received.Stream || received.MaxTokens != 256 {
+		t.Errorf("Unexpected model/stream/max_tokens: %s/%v/%d", received.Model, received.Stream, received.MaxTokens)
+	}
+	if received.System != "Be brief." {
+		t.Errorf("Expected system instruction at top level, got %q", received.System)
+	}
+	if len(received.Messages) != 3 {
+		t.Fatalf("Expected 3 alternating messages, got %d: %+v", len(received.Messages), received.Messages)
+	}
+	assistant := received.Messages[1]
+	if assistant.Role != "assistant" || len(assistant.Content) != 1 || assistant.Content[0].Type != "tool_use" ||
+		assistant.Content[0].ID != "toolu_1" || string(assistant.Content[0].Input) != `{"location":"Berlin"}` {
+		t.Errorf("Unexpected assistant message: %+v", assistant)
+	}
+	results := received.Messages[2]
+	if results.Role != "user" || len(results.Content) != 2 {
+		t.Fatalf("Expected tool result merged with the next user turn, got %+v", results)
+	}
+	if results.Content[0].Type != "tool_result" || results.Content[0].ToolUseID != "toolu_1" || results.Content[0].Content != `{"temp":21}` {
+		t.Errorf("Unexpected tool result: %+v", results.Content[0])
+	}
+	if results.Content[1].Type != "text" || results.Content[1].Text != "And Paris?" {
+		t.Errorf("Unexpected user text: %+v", results.Content[1])
+	}
+	if len(received.Tools) != 1 || received.Tools[0].InputSchema["type"] != "object" {
+		t.Errorf("Expected tool with default schema, got %+v", received.Tools)
+	}
+	if received.Temperature == nil || *received.Temperature != 0.3 {
+		t.Errorf("Expected request temperature override, got %v", received.Temperature)
+	}
+
+	// Response conversion
+	if response.Partial == nil || *response.Partial {
+		t.Error("Expected a complete response")
+	}
+	if len(response.Content.Parts) != 2 || *response.Content.Parts[0].Text != "Checking Paris." {
+		t.Fatalf("Unexpected content: %+v", response.Content)
+	}
+	call := response.Content.Parts[1].FunctionCall
+	if call == nil || call.ID != "toolu_abc" || call.Args["location"] != "Paris" {
+		t.Errorf("Unexpected function call: %+v", call)
+	}
+	if response.Metadata["prompt_tokens"] != 20 || response.Metadata["completion_tokens"] != 9 ||
+		response.Metadata["total_tokens"] != 29 || response.Metadata["cached_tokens"] != 4 ||
+		response.Metadata["finish_reason"] != "tool_use" {
+		t.Errorf("Unexpected metadata: %v", response.Metadata)
+	}
+	if response.Usage == nil || response.Usage.InputTokens != 20 || response.Usage.CachedTokens != 4 || response.Usage.TotalTokens != 29 {
+		t.Errorf("Unexpected usage: %+v", response.Usage)
+	}
+}
+
+func TestGenerateContentStream(t *testing.T) {
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		var req MessagesRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Errorf("Expected streaming request, got %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"message_start","message":{"id":"msg_s","type":"message","role":"assistant","model":"claude-test","content":[],"usage":{"input_tokens":15,"output_tokens":1}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"ping"}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_x","name":"lookup","input":{}}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"q\":"}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"go\"}"}}`,
+			`{"type":"content_block_stop","index":1}`,
+			`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":12}}`,
+			`{"type":"message_stop"}`,
+		}
+		for _, event := range events {
+			var typed struct{ Type string }
+			json.Unmarshal([]byte(event), &typed)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typed.Type, event)
+		}
+	})
+
+	request := &core.LLMRequest{Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}}}}
+	stream, err := conn.GenerateContentStream(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+
+	var responses []*core.LLMResponse
+	for response := range stream {
+		responses = append(responses, response)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("Expected 2 partials and a final response, got %d", len(responses))
+	}
+	if !*responses[0].Partial || *responses[0].Content.Parts[0].Text != "Hel" || *responses[1].Content.Parts[0].Text != "lo" {
+		t.Errorf("Unexpected partial responses")
+	}
+
+	final := responses[2]
+	if *final.Partial {
+		t.Error("Expected the last response to be complete")
+	}
+	if len(final.Content.Parts) != 2 || *final.Content.Parts[0].Text != "Hello" {
+		t.Fatalf("Unexpected final content: %+v", final.Content)
+	}
+	call := final.Content.Parts[1].FunctionCall
+	if call == nil || call.ID != "toolu_x" || call.Name != "lookup" || call.Args["q"] != "go" {
+		t.Errorf("Unexpected assembled tool call: %+v", call)
+	}
+	if final.Metadata["total_tokens"] != 27 || final.Metadata["finish_reason"] != "tool_use" || final.Metadata["id"] != "msg_s" {
+		t.Errorf("Unexpected final metadata: %v", final.Metadata)
+	}
+}
+
+func TestGenerateContentStream_ErrorEvent(t *testing.T) {
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
+	})
+
+	stream, err := conn.GenerateContentStream(context.Background(), &core.LLMRequest{})
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	var last *core.LLMResponse
+	for response := range stream {
+		last = response
+	}
+	if last == nil || last.Metadata["error"] == nil {
+		t.Fatalf("Expected a final error response, got %+v", last)
+	}
+}
+
+func TestGenerateContent_APIError(t *testing.T) {
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(529)
+		fmt.Fprint(w, `{"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}`)
+	})
+
+	_, err := conn.GenerateContent(context.Background(), &core.LLMRequest{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != 529 || apiErr.Type != "overloaded_error" || !apiErr.Retryable() {
+		t.Errorf("Unexpected API error: %+v", apiErr)
+	}
+}
+
+func TestGenerateContentStream_Thinking(t *testing.T) {
+	var received MessagesRequest
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"message_start","message":{"id":"msg_t","type":"message","role":"assistant","model":"claude-test","content":[],"usage":{"input_tokens":10,"output_tokens":1}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"The user greets me."}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig-1"}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hi!"}}`,
+			`{"type":"content_block_stop","index":1}`,
+			`{"type":"message_stop"}`,
+		}
+		for _, event := range events {
+			fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+	})
+
+	request := &core.LLMRequest{
+		Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}}},
+		Config:   &core.LLMConfig{Temperature: ptr.Float32(0.5), Thinking: &core.ThinkingConfig{}},
+	}
+	stream, err := conn.GenerateContentStream(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	var responses []*core.LLMResponse
+	for response := range stream {
+		responses = append(responses, response)
+	}
+
+	if received.Thinking == nil || received.Thinking.BudgetTokens != minThinkingBudget || received.Temperature != nil {
+		t.Errorf("Expected thinking enabled without sampling parameters, got %+v", received)
+	}
+	if received.MaxTokens <= minThinkingBudget {
+		t.Errorf("Expected max tokens above the thinking budget, got %d", received.MaxTokens)
+	}
+	if len(responses) != 3 || !responses[0].Content.Parts[0].IsThought() || responses[1].Content.Parts[0].IsThought() {
+		t.Fatalf("Expected a thought and a text partial before the final response, got %d responses", len(responses))
+	}
+
+	final := responses[2].Content
+	if len(final.Parts) != 2 || *final.Parts[0].Thought != "The user greets me." || *final.Parts[1].Text != "Hi!" {
+		t.Fatalf("Unexpected final content: %+v", final)
+	}
+	if final.Parts[0].Metadata[SignatureMetadataKey] != "sig-1" {
+		t.Errorf("Expected the signature to be kept, got %v", final.Parts[0].Metadata)
+	}
+
+	// Signed thoughts are sent back; unsigned ones are dropped
+	followUp := &core.LLMRequest{Contents: []core.Content{
+		{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}},
+		{Role: "model", Parts: append([]core.Part{core.NewThoughtPart("unsigned")}, final.Parts...)},
+	}}
+	msgReq, err := conn.convertToAnthropicRequest(followUp, false)
+	if err != nil {
+		t.Fatalf("Failed to convert request: %v", err)
+	}
+	blocks := msgReq.Messages[1].Content
+	if len(blocks) != 2 || blocks[0].Type != "thinking" || blocks[0].Signature != "sig-1" || blocks[1].Type != "text" {
+		t.Errorf("Unexpected assistant blocks: %+v", blocks)
+	}
+}
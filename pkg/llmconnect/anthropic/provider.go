@@ -0,0 +1,28 @@
+package anthropic
+
+import (
+	"fmt"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect"
+)
+
+func init() {
+	llmconnect.Register("anthropic", []string{"anthropic/*", "claude-*"}, newFromSettings)
+}
+
+// newFromSettings creates a connection for the model registry.
+func newFromSettings(model string, settings llmconnect.ProviderSettings) (core.LLMConnection, error) {
+	config := DefaultAnthropicConfig()
+	config.Model = model
+	if settings.BaseURL != "" {
+		config.BaseURL = settings.BaseURL
+	}
+	if apiKey := settings.ResolvedAPIKey(); apiKey != "" {
+		config.APIKey = apiKey
+	}
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("no API key: set ANTHROPIC_API_KEY")
+	}
+	return NewAnthropicConnection(config), nil
+}
@@ -0,0 +1,146 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Wire types for the Anthropic Messages API, see
+// https://docs.anthropic.com/en/api/messages. Only the fields used by the
+// connection are modelled.
+
+// MessagesRequest is the body of POST /v1/messages.
+type MessagesRequest struct {
+	Model         string    `json:"model"`
+	MaxTokens     int       `json:"max_tokens"`
+	System        string    `json:"system,omitempty"`
+	Messages      []Message `json:"messages"`
+	Tools         []Tool    `json:"tools,omitempty"`
+	Temperature   *float32  `json:"temperature,omitempty"`
+	TopP          *float32  `json:"top_p,omitempty"`
+	TopK          *int      `json:"top_k,omitempty"`
+	StopSequences []string  `json:"stop_sequences,omitempty"`
+	Stream        bool      `json:"stream,omitempty"`
+	Thinking      *Thinking `json:"thinking,omitempty"`
+}
+
+// Thinking enables extended thinking. Type is "enabled" and the budget must
+// be at least 1024 tokens and below max_tokens.
+type Thinking struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+// Message is a single conversation turn. Role is "user" or "assistant".
+type Message struct {
+	Role    string         `json:"role"`
+	Content []ContentBlock `json:"content"`
+}
+
+// ContentBlock is one element of a message: text, thinking, tool_use or
+// tool_result.
+type ContentBlock struct {
+	Type string `json:"type"`
+
+	// text
+	Text string `json:"text,omitempty"`
+
+	// thinking; the signature must be sent back with the thinking
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+
+	// tool_use
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// tool_result
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+// Tool declares a function the model may call.
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// MessagesResponse is the body of a non-streamed response.
+type MessagesResponse struct {
+	ID           string         `json:"id"`
+	Type         string         `json:"type"`
+	Role         string         `json:"role"`
+	Model        string         `json:"model"`
+	Content      []ContentBlock `json:"content"`
+	StopReason   string         `json:"stop_reason"`
+	StopSequence *string        `json:"stop_sequence,omitempty"`
+	Usage        *Usage         `json:"usage,omitempty"`
+}
+
+// Usage reports the tokens consumed by a request.
+type Usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+}
+
+// StreamEvent is a single server-sent event of a streamed response. The
+// populated fields depend on Type.
+type StreamEvent struct {
+	Type string `json:"type"`
+
+	// message_start
+	Message *MessagesResponse `json:"message,omitempty"`
+
+	// content_block_start, content_block_delta, content_block_stop
+	Index        int           `json:"index"`
+	ContentBlock *ContentBlock `json:"content_block,omitempty"`
+
+	// content_block_delta and message_delta
+	Delta *StreamDelta `json:"delta,omitempty"`
+
+	// message_delta
+	Usage *Usage `json:"usage,omitempty"`
+
+	// error
+	Error *APIError `json:"error,omitempty"`
+}
+
+// StreamDelta is the incremental update carried by a delta event.
+type StreamDelta struct {
+	Type        string `json:"type,omitempty"`
+	Text        string `json:"text,omitempty"`
+	Thinking    string `json:"thinking,omitempty"`
+	Signature   string `json:"signature,omitempty"`
+	PartialJSON string `json:"partial_json,omitempty"`
+	StopReason  string `json:"stop_reason,omitempty"`
+}
+
+// APIError is returned when the server responds with a non-200 status.
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("anthropic API error (status %d, %s): %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("anthropic API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed when retried. Status 529
+// is returned when the API is overloaded.
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500 || e.Type == "overloaded_error"
+}
+
+// errorResponse is the error envelope of the Messages API.
+type errorResponse struct {
+	Type  string    `json:"type"`
+	Error *APIError `json:"error"`
+}
@@ -0,0 +1,398 @@
+package llmconnect
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// CacheHitMetadataKey is the LLMResponse.Metadata key set on responses served
+// from a cache.
+const CacheHitMetadataKey = "cache_hit"
+
+// ErrCacheMiss is returned in replay mode for requests that are not cached.
+var ErrCacheMiss = errors.New("LLM response not cached")
+
+// CacheMode controls how a CachingConnection uses its cache.
+type CacheMode string
+
+const (
+	// CacheModeReadWrite serves cached responses and caches new ones.
+	CacheModeReadWrite CacheMode = "read_write"
+	// CacheModeRecord always calls the connection and caches its responses,
+	// replacing earlier recordings.
+	CacheModeRecord CacheMode = "record"
+	// CacheModeReplay only serves cached responses and fails with
+	// ErrCacheMiss otherwise, so it needs no connection at all.
+	CacheModeReplay CacheMode = "replay"
+)
+
+// Cache stores LLM responses by request key.
+type Cache interface {
+	// Get returns the response stored under key, or nil if there is none
+	// or it has expired.
+	Get(ctx context.Context, key string) (*core.LLMResponse, error)
+
+	// Set stores a response under key. A zero ttl never expires.
+	Set(ctx context.Context, key string, response *core.LLMResponse, ttl time.Duration) error
+}
+
+// CacheConfig contains configuration options for caching connections.
+type CacheConfig struct {
+	Mode CacheMode `json:"mode"`
+
+	// TTL is how long cached responses are served (0 = forever).
+	TTL time.Duration `json:"ttl"`
+}
+
+// DefaultCacheConfig returns a default configuration for caching connections.
+func DefaultCacheConfig() *CacheConfig {
+	return &CacheConfig{
+		Mode: CacheModeReadWrite,
+	}
+}
+
+// RequestKey returns the cache key of a request: a SHA-256 hash of its
+// canonical JSON encoding, covering the contents, tools and config.
+func RequestKey(request *core.LLMRequest) (string, error) {
+	// encoding/json sorts map keys, which makes the encoding canonical
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+var _ core.LLMConnection = (*CachingConnection)(nil)
+
+// CachingConnection implements the LLMConnection interface by serving
+// responses for identical requests from a cache. Responses served from the
+// cache carry Metadata["cache_hit"] and report zero usage.
+type CachingConnection struct {
+	conn   core.LLMConnection
+	cache  Cache
+	config *CacheConfig
+}
+
+// NewCachingConnection creates a caching connection in front of conn. The
+// connection may be nil in replay mode.
+func NewCachingConnection(conn core.LLMConnection, cache Cache, config *CacheConfig) (*CachingConnection, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("a cache is required")
+	}
+	if config == nil {
+		config = DefaultCacheConfig()
+	}
+	switch config.Mode {
+	case "":
+		config.Mode = CacheModeReadWrite
+	case CacheModeReadWrite, CacheModeRecord, CacheModeReplay:
+	default:
+		return nil, fmt.Errorf("unknown cache mode %q", config.Mode)
+	}
+	if conn == nil && config.Mode != CacheModeReplay {
+		return nil, fmt.Errorf("a connection is required in %s mode", config.Mode)
+	}
+
+	return &CachingConnection{conn: conn, cache: cache, config: config}, nil
+}
+
+// GenerateContent returns the cached response for the request, calling the
+// connection on a miss.
+func (c *CachingConnection) GenerateContent(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
+	key, cached, err := c.lookup(ctx, request)
+	if err != nil || cached != nil {
+		return cached, err
+	}
+
+	response, err := c.conn.GenerateContent(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	c.store(ctx, key, response)
+	return response, nil
+}
+
+// GenerateContentStream replays a cached response as a single complete
+// response, or streams from the connection on a miss and caches the final
+// response.
+func (c *CachingConnection) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
+	key, cached, err := c.lookup(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		responseChan := make(chan *core.LLMResponse, 1)
+		responseChan <- cached
+		close(responseChan)
+		return responseChan, nil
+	}
+
+	stream, err := c.conn.GenerateContentStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	responseChan := make(chan *core.LLMResponse, 10)
+	go func() {
+		defer close(responseChan)
+		var final *core.LLMResponse
+		for response := range stream {
+			if response != nil && (response.Partial == nil || !*response.Partial) {
+				final = response
+			}
+			select {
+			case responseChan <- response:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if final != nil {
+			c.store(ctx, key, final)
+		}
+	}()
+	return responseChan, nil
+}
+
+// Close closes the underlying connection.
+func (c *CachingConnection) Close(ctx context.Context) error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close(ctx)
+}
+
+// lookup returns the key of a request and its cached response, if the mode
+// allows serving one. In replay mode a miss is an error.
+func (c *CachingConnection) lookup(ctx context.Context, request *core.LLMRequest) (string, *core.LLMResponse, error) {
+	key, err := RequestKey(request)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.config.Mode == CacheModeRecord {
+		return key, nil, nil
+	}
+
+	cached, err := c.cache.Get(ctx, key)
+	if err != nil {
+		if c.config.Mode == CacheModeReplay {
+			return "", nil, fmt.Errorf("failed to read cached response %s: %w", key, err)
+		}
+		log.Printf("Failed to read cached LLM response %s: %v", key, err)
+	}
+	if cached != nil {
+		return key, cacheHit(cached), nil
+	}
+	if c.config.Mode == CacheModeReplay {
+		return "", nil, fmt.Errorf("%w: request %s", ErrCacheMiss, key)
+	}
+	return key, nil, nil
+}
+
+// store caches a complete, successful response. Failures are logged since
+// the response itself is still valid.
+func (c *CachingConnection) store(ctx context.Context, key string, response *core.LLMResponse) {
+	if response == nil || response.Metadata["error"] != nil {
+		return
+	}
+	if err := c.cache.Set(ctx, key, response, c.config.TTL); err != nil {
+		log.Printf("Failed to cache LLM response %s: %v", key, err)
+	}
+}
+
+// cacheHit marks a copy of a cached response as served from the cache.
+func cacheHit(cached *core.LLMResponse) *core.LLMResponse {
+	response := cloneResponse(cached)
+	response.Metadata[CacheHitMetadataKey] = true
+	response.Usage = &core.Usage{}
+	return response
+}
+
+// cloneResponse copies a response so callers can annotate it without
+// changing the cached original.
+func cloneResponse(response *core.LLMResponse) *core.LLMResponse {
+	clone := *response
+	clone.Metadata = make(map[string]any, len(response.Metadata)+1)
+	for k, v := range response.Metadata {
+		clone.Metadata[k] = v
+	}
+	if response.Usage != nil {
+		usage := *response.Usage
+		clone.Usage = &usage
+	}
+	return &clone
+}
+
+// memoryEntry is one response held by a MemoryCache.
+type memoryEntry struct {
+	key       string
+	response  *core.LLMResponse
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-memory Cache that evicts the least recently used
+// responses beyond its capacity.
+type MemoryCache struct {
+	capacity int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// NewMemoryCache creates an in-memory cache holding up to capacity responses
+// (0 = unlimited).
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the response stored under key.
+func (m *MemoryCache) Get(ctx context.Context, key string) (*core.LLMResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && m.now().After(entry.expiresAt) {
+		m.order.Remove(element)
+		delete(m.entries, key)
+		return nil, nil
+	}
+	m.order.MoveToFront(element)
+	return cloneResponse(entry.response), nil
+}
+
+// Set stores a response under key, evicting the least recently used
+// response when the cache is full.
+func (m *MemoryCache) Set(ctx context.Context, key string, response *core.LLMResponse, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryEntry{key: key, response: cloneResponse(response)}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	if element, ok := m.entries[key]; ok {
+		element.Value = entry
+		m.order.MoveToFront(element)
+		return nil
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+	if m.capacity > 0 && m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of cached responses, including expired ones not yet evicted.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// diskEntry is the file format of a DiskCache entry.
+type diskEntry struct {
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+	Response  *core.LLMResponse `json:"response"`
+}
+
+// DiskCache is a Cache storing one JSON file per response in a directory.
+// Its files can be committed as test fixtures and replayed with
+// CacheModeReplay.
+type DiskCache struct {
+	dir string
+	now func() time.Time
+}
+
+// NewDiskCache creates a disk cache in dir, creating the directory if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir, now: time.Now}, nil
+}
+
+// Get returns the response stored under key.
+func (d *DiskCache) Get(ctx context.Context, key string) (*core.LLMResponse, error) {
+	data, err := os.ReadFile(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode cache entry: %w", err)
+	}
+	if entry.Response == nil {
+		return nil, fmt.Errorf("failed to decode cache entry: no response")
+	}
+	if entry.ExpiresAt != nil && d.now().After(*entry.ExpiresAt) {
+		os.Remove(d.path(key))
+		return nil, nil
+	}
+	if entry.Response.Metadata == nil {
+		entry.Response.Metadata = make(map[string]any)
+	}
+	return entry.Response, nil
+}
+
+// Set stores a response under key.
+func (d *DiskCache) Set(ctx context.Context, key string, response *core.LLMResponse, ttl time.Duration) error {
+	entry := diskEntry{Response: response}
+	if ttl > 0 {
+		expiresAt := d.now().Add(ttl)
+		entry.ExpiresAt = &expiresAt
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see partial entries
+	tmp, err := os.CreateTemp(d.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+func (d *DiskCache) path(key string) string {
+	return filepath.Join(d.dir, key+".json")
+}
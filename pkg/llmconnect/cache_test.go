@@ -0,0 +1,187 @@
+package llmconnect
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+func newCacheRequest(text string) *core.LLMRequest {
+	return &core.LLMRequest{
+		Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}}},
+		Config:   &core.LLMConfig{Model: "test-model", Temperature: ptr.Float32(0)},
+		Tools: []*core.FunctionDeclaration{{
+			Name:       "search",
+			Parameters: map[string]any{"type": "object", "properties": map[string]any{"q": map[string]any{"type": "string"}}},
+		}},
+	}
+}
+
+func TestRequestKey(t *testing.T) {
+	key, err := RequestKey(newCacheRequest("hi"))
+	if err != nil {
+		t.Fatalf("RequestKey failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		// Map iteration order must not change the key
+		if again, _ := RequestKey(newCacheRequest("hi")); again != key {
+			t.Fatalf("Expected a stable key, got %s and %s", key, again)
+		}
+	}
+
+	changed := newCacheRequest("hi")
+	changed.Config.Temperature = ptr.Float32(0.7)
+	if other, _ := RequestKey(changed); other == key {
+		t.Error("Expected the config to change the key")
+	}
+	changed = newCacheRequest("hi")
+	changed.Tools = nil
+	if other, _ := RequestKey(changed); other == key {
+		t.Error("Expected the tools to change the key")
+	}
+}
+
+func TestCachingConnection_ReadWrite(t *testing.T) {
+	backend := &scriptedConnection{}
+	conn, err := NewCachingConnection(backend, NewMemoryCache(10), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := conn.GenerateContent(context.Background(), newCacheRequest("hi"))
+	if err != nil || first.Metadata[CacheHitMetadataKey] != nil {
+		t.Fatalf("Expected an uncached response, got %+v (err=%v)", first, err)
+	}
+	second, err := conn.GenerateContent(context.Background(), newCacheRequest("hi"))
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+	if backend.calls != 1 || second.Metadata[CacheHitMetadataKey] != true {
+		t.Errorf("Expected a cache hit, got %d calls and %v", backend.calls, second.Metadata)
+	}
+	if second.Usage == nil || second.Usage.LLMCalls != 0 || second.Usage.TotalTokens != 0 {
+		t.Errorf("Expected zero usage for a cache hit, got %+v", second.Usage)
+	}
+
+	conn.GenerateContent(context.Background(), newCacheRequest("something else"))
+	if backend.calls != 2 {
+		t.Errorf("Expected a different request to miss, got %d calls", backend.calls)
+	}
+
+	// Errors are not cached
+	backend.err = errors.New("boom")
+	if _, err := conn.GenerateContent(context.Background(), newCacheRequest("third")); err == nil {
+		t.Error("Expected the connection error")
+	}
+	backend.err = nil
+	conn.GenerateContent(context.Background(), newCacheRequest("third"))
+	if backend.calls != 4 {
+		t.Errorf("Expected the failed request to be retried, got %d calls", backend.calls)
+	}
+}
+
+func TestMemoryCache_LRUAndTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(2)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.Set(ctx, "a", &core.LLMResponse{}, 0)
+	cache.Set(ctx, "b", &core.LLMResponse{}, 0)
+	cache.Get(ctx, "a")
+	cache.Set(ctx, "c", &core.LLMResponse{}, 0)
+	if response, _ := cache.Get(ctx, "b"); response != nil {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if response, _ := cache.Get(ctx, "a"); response == nil {
+		t.Error("Expected the recently used entry to be kept")
+	}
+
+	cache.Set(ctx, "d", &core.LLMResponse{}, time.Minute)
+	now = now.Add(2 * time.Minute)
+	if response, _ := cache.Get(ctx, "d"); response != nil {
+		t.Error("Expected the entry to expire")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Expected expired entries to be dropped, got %d", cache.Len())
+	}
+}
+
+func TestDiskCache_EntryWithoutResponse(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, entry := range map[string]string{"null": `{"response": null}`, "missing": `{}`} {
+		if err := os.WriteFile(filepath.Join(dir, key+".json"), []byte(entry), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if response, err := cache.Get(context.Background(), key); err == nil {
+			t.Errorf("Expected a decode error for entry %s, got %+v", entry, response)
+		}
+	}
+}
+
+func TestCachingConnection_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	recordCache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &scriptedConnection{}
+	recorder, _ := NewCachingConnection(backend, recordCache, &CacheConfig{Mode: CacheModeRecord})
+	recorder.GenerateContent(ctx, newCacheRequest("hi"))
+	recorder.GenerateContent(ctx, newCacheRequest("hi"))
+	if backend.calls != 2 {
+		t.Errorf("Expected record mode to always call the connection, got %d calls", backend.calls)
+	}
+
+	stream, err := recorder.GenerateContentStream(ctx, newCacheRequest("streamed"))
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	for range stream {
+	}
+
+	// A fresh cache over the same directory replays without a connection
+	replayCache, _ := NewDiskCache(dir)
+	replayer, err := NewCachingConnection(nil, replayCache, &CacheConfig{Mode: CacheModeReplay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := replayer.GenerateContent(ctx, newCacheRequest("hi"))
+	if err != nil || response.Metadata[CacheHitMetadataKey] != true {
+		t.Fatalf("Expected a replayed response, got %+v (err=%v)", response, err)
+	}
+
+	stream, err = replayer.GenerateContentStream(ctx, newCacheRequest("streamed"))
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	count := 0
+	for response := range stream {
+		count++
+		if response.Metadata[CacheHitMetadataKey] != true {
+			t.Errorf("Expected a replayed stream response, got %v", response.Metadata)
+		}
+	}
+	if count != 1 {
+		t.Errorf("Expected the final stream response to be replayed once, got %d", count)
+	}
+
+	if _, err := replayer.GenerateContent(ctx, newCacheRequest("not recorded")); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("Expected ErrCacheMiss, got %v", err)
+	}
+	if _, err := NewCachingConnection(nil, replayCache, nil); err == nil {
+		t.Error("Expected a connection to be required outside replay mode")
+	}
+}
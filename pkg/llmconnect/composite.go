@@ -0,0 +1,347 @@
+package llmconnect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// BackendMetadataKey is the LLMResponse.Metadata key naming the backend that
+// served a response.
+const BackendMetadataKey = "backend"
+
+// Strategy selects the backend that is tried first for a request.
+type Strategy string
+
+const (
+	// StrategyFailover always starts with the first available backend.
+	StrategyFailover Strategy = "failover"
+	// StrategyRoundRobin rotates the first backend across requests.
+	StrategyRoundRobin Strategy = "round_robin"
+	// StrategyWeighted spreads requests in proportion to Backend.Weight.
+	StrategyWeighted Strategy = "weighted"
+)
+
+// Backend is one connection behind a CompositeConnection.
+type Backend struct {
+	// Name identifies the backend in response metadata and errors.
+	Name       string
+	Connection core.LLMConnection
+	// Weight is the relative share of requests under StrategyWeighted.
+	// Values below 1 count as 1.
+	Weight int
+}
+
+// CompositeConfig contains configuration options for composite connections.
+type CompositeConfig struct {
+	Strategy Strategy `json:"strategy"`
+
+	// FailureThreshold is the number of consecutive retryable failures that
+	// opens a backend's circuit breaker.
+	FailureThreshold int `json:"failure_threshold"`
+
+	// Cooldown is how long an open breaker skips its backend.
+	Cooldown time.Duration `json:"cooldown"`
+
+	// IsRetryable decides whether an error moves the request on to the next
+	// backend. Defaults to DefaultIsRetryable.
+	IsRetryable func(error) bool `json:"-"`
+}
+
+// DefaultCompositeConfig returns a default configuration for composite connections.
+func DefaultCompositeConfig() *CompositeConfig {
+	return &CompositeConfig{
+		Strategy:         StrategyFailover,
+		FailureThreshold: 3,
+		Cooldown:         30 * time.Second,
+		IsRetryable:      DefaultIsRetryable,
+	}
+}
+
+// DefaultIsRetryable reports whether another backend may succeed where err
+// failed. Errors exposing a Retryable method, such as the API errors of the
+// provider packages, decide for themselves; cancellation is never retried;
+// anything else (network failures, timeouts) is.
+func DefaultIsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+	return true
+}
+
+// BackendsError is returned when no backend could serve a request.
+type BackendsError struct {
+	// Errors maps backend names to the error each one returned.
+	Errors map[string]error
+	order  []string
+}
+
+func (e *BackendsError) Error() string {
+	if len(e.order) == 0 {
+		return "all LLM backends are unavailable (circuit breakers open)"
+	}
+	messages := make([]string, 0, len(e.order))
+	for _, name := range e.order {
+		messages = append(messages, fmt.Sprintf("%s: %v", name, e.Errors[name]))
+	}
+	return "all LLM backends failed: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the backend errors for errors.Is and errors.As.
+func (e *BackendsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.order))
+	for _, name := range e.order {
+		errs = append(errs, e.Errors[name])
+	}
+	return errs
+}
+
+func (e *BackendsError) add(name string, err error) {
+	if e.Errors == nil {
+		e.Errors = make(map[string]error)
+	}
+	e.Errors[name] = err
+	e.order = append(e.order, name)
+}
+
+// circuitBreaker tracks the health of one backend.
+type circuitBreaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+// backend is a Backend together with its breaker and weighting state.
+type backend struct {
+	Backend
+	breaker       circuitBreaker
+	currentWeight int
+}
+
+var _ core.LLMConnection = (*CompositeConnection)(nil)
+
+// CompositeConnection implements the LLMConnection interface over an ordered
+// list of backends. A request goes to the backend chosen by the strategy and
+// fails over to the remaining backends, in order, on retryable errors.
+// Backends failing repeatedly are skipped until their cooldown expires.
+type CompositeConnection struct {
+	backends []*backend
+	config   *CompositeConfig
+	now      func() time.Time
+
+	mu   sync.Mutex
+	next int
+}
+
+// NewCompositeConnection creates a composite connection over the given backends.
+func NewCompositeConnection(backends []Backend, config *CompositeConfig) (*CompositeConnection, error) {
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("at least one backend is required")
+	}
+	if config == nil {
+		config = DefaultCompositeConfig()
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 1
+	}
+	if config.IsRetryable == nil {
+		config.IsRetryable = DefaultIsRetryable
+	}
+	switch config.Strategy {
+	case "":
+		config.Strategy = StrategyFailover
+	case StrategyFailover, StrategyRoundRobin, StrategyWeighted:
+	default:
+		return nil, fmt.Errorf("unknown strategy %q", config.Strategy)
+	}
+
+	c := &CompositeConnection{config: config, now: time.Now}
+	names := make(map[string]bool, len(backends))
+	for i, b := range backends {
+		if b.Connection == nil {
+			return nil, fmt.Errorf("backend %d has no connection", i)
+		}
+		if b.Name == "" {
+			b.Name = fmt.Sprintf("backend-%d", i)
+		}
+		if names[b.Name] {
+			return nil, fmt.Errorf("duplicate backend name %q", b.Name)
+		}
+		names[b.Name] = true
+		if b.Weight < 1 {
+			b.Weight = 1
+		}
+		c.backends = append(c.backends, &backend{Backend: b})
+	}
+	return c, nil
+}
+
+// GenerateContent sends the request to the backends in turn until one succeeds.
+func (c *CompositeConnection) GenerateContent(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
+	failures := &BackendsError{}
+	for _, b := range c.candidates() {
+		response, err := b.Connection.GenerateContent(ctx, request)
+		if err == nil {
+			c.recordSuccess(b)
+			return annotate(response, b.Name), nil
+		}
+		if !c.handleFailure(ctx, b, err) {
+			return nil, err
+		}
+		failures.add(b.Name, err)
+	}
+	return nil, failures
+}
+
+// GenerateContentStream opens a stream on the backends in turn until one
+// accepts the request. Failover only happens before the stream starts; a
+// stream ending with Metadata["error"] counts as a failure of its backend.
+func (c *CompositeConnection) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
+	failures := &BackendsError{}
+	for _, b := range c.candidates() {
+		stream, err := b.Connection.GenerateContentStream(ctx, request)
+		if err != nil {
+			if !c.handleFailure(ctx, b, err) {
+				return nil, err
+			}
+			failures.add(b.Name, err)
+			continue
+		}
+
+		responseChan := make(chan *core.LLMResponse, 10)
+		go func(b *backend) {
+			defer close(responseChan)
+			var streamErr error
+			for response := range stream {
+				if response != nil && response.Metadata != nil {
+					if msg, ok := response.Metadata["error"].(string); ok {
+						streamErr = errors.New(msg)
+					}
+				}
+				select {
+				case responseChan <- annotate(response, b.Name):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if streamErr != nil {
+				c.handleFailure(ctx, b, streamErr)
+			} else {
+				c.recordSuccess(b)
+			}
+		}(b)
+		return responseChan, nil
+	}
+	return nil, failures
+}
+
+// Close closes all backends.
+func (c *CompositeConnection) Close(ctx context.Context) error {
+	var errs []error
+	for _, b := range c.backends {
+		if err := b.Connection.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close backend %s: %w", b.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// candidates returns the backends to try for one request: the one picked by
+// the strategy first, then the others in list order. Backends with an open
+// breaker are left out until their cooldown expires; a single failure then
+// opens the breaker again.
+func (c *CompositeConnection) candidates() []*backend {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	available := make([]*backend, 0, len(c.backends))
+	for _, b := range c.backends {
+		if b.breaker.failures >= c.config.FailureThreshold && now.Before(b.breaker.openUntil) {
+			continue
+		}
+		available = append(available, b)
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	first := 0
+	switch c.config.Strategy {
+	case StrategyRoundRobin:
+		first = c.next % len(available)
+		c.next++
+	case StrategyWeighted:
+		first = pickWeighted(available)
+	}
+	if first == 0 {
+		return available
+	}
+
+	ordered := make([]*backend, 0, len(available))
+	ordered = append(ordered, available[first])
+	ordered = append(ordered, available[:first]...)
+	return append(ordered, available[first+1:]...)
+}
+
+// pickWeighted selects a backend with smooth weighted round-robin, which
+// spreads requests evenly in proportion to the weights.
+func pickWeighted(available []*backend) int {
+	total, best := 0, 0
+	for i, b := range available {
+		b.currentWeight += b.Weight
+		total += b.Weight
+		if b.currentWeight > available[best].currentWeight {
+			best = i
+		}
+	}
+	available[best].currentWeight -= total
+	return best
+}
+
+// handleFailure records a failed attempt and reports whether the request
+// should move on to the next backend.
+func (c *CompositeConnection) handleFailure(ctx context.Context, b *backend, err error) bool {
+	if ctx.Err() != nil || !c.config.IsRetryable(err) {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b.breaker.failures++
+	if b.breaker.failures >= c.config.FailureThreshold {
+		b.breaker.openUntil = c.now().Add(c.config.Cooldown)
+		log.Printf("LLM backend %s failed %d times, skipping it for %s: %v", b.Name, b.breaker.failures, c.config.Cooldown, err)
+	} else {
+		log.Printf("LLM backend %s failed, trying next backend: %v", b.Name, err)
+	}
+	return true
+}
+
+// recordSuccess closes the breaker of a backend.
+func (c *CompositeConnection) recordSuccess(b *backend) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b.breaker = circuitBreaker{}
+}
+
+// annotate records the serving backend in the response metadata.
+func annotate(response *core.LLMResponse, name string) *core.LLMResponse {
+	if response == nil {
+		return nil
+	}
+	if response.Metadata == nil {
+		response.Metadata = make(map[string]any)
+	}
+	response.Metadata[BackendMetadataKey] = name
+	return response
+}
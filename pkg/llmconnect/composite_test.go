@@ -0,0 +1,183 @@
+package llmconnect
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// apiError mimics the provider API errors.
+type apiError struct {
+	status int
+}
+
+func (e *apiError) Error() string   { return "api error" }
+func (e *apiError) Retryable() bool { return e.status == 429 || e.status >= 500 }
+
+// scriptedConnection fails with err while it is set and counts its calls.
+type scriptedConnection struct {
+	err   error
+	calls int
+}
+
+func (s *scriptedConnection) GenerateContent(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &core.LLMResponse{}, nil
+}
+
+func (s *scriptedConnection) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	stream := make(chan *core.LLMResponse, 2)
+	stream <- &core.LLMResponse{}
+	stream <- &core.LLMResponse{}
+	close(stream)
+	return stream, nil
+}
+
+func (s *scriptedConnection) Close(ctx context.Context) error {
+	return nil
+}
+
+func servedBy(t *testing.T, conn *CompositeConnection) string {
+	t.Helper()
+	response, err := conn.GenerateContent(context.Background(), &core.LLMRequest{})
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+	return response.Metadata[BackendMetadataKey].(string)
+}
+
+func TestCompositeConnection_Failover(t *testing.T) {
+	hosted := &scriptedConnection{err: &apiError{status: 429}}
+	local := &scriptedConnection{}
+	conn, err := NewCompositeConnection([]Backend{
+		{Name: "hosted", Connection: hosted},
+		{Name: "ollama", Connection: local},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name := servedBy(t, conn); name != "ollama" {
+		t.Errorf("Expected failover to ollama, served by %s", name)
+	}
+
+	// Non-retryable errors are returned without trying the next backend
+	hosted.err = &apiError{status: 400}
+	local.calls = 0
+	if _, err := conn.GenerateContent(context.Background(), &core.LLMRequest{}); err == nil || local.calls != 0 {
+		t.Errorf("Expected the client error to be returned directly, got %v after %d fallback calls", err, local.calls)
+	}
+
+	// All backends failing yields a BackendsError wrapping each error
+	hosted.err = &apiError{status: 503}
+	local.err = errors.New("connection refused")
+	_, err = conn.GenerateContent(context.Background(), &core.LLMRequest{})
+	var backendsErr *BackendsError
+	if !errors.As(err, &backendsErr) || len(backendsErr.Errors) != 2 {
+		t.Fatalf("Expected BackendsError for both backends, got %v", err)
+	}
+	var hostedErr *apiError
+	if !errors.As(err, &hostedErr) || hostedErr.status != 503 {
+		t.Errorf("Expected the hosted error to be unwrappable, got %v", hostedErr)
+	}
+}
+
+func TestCompositeConnection_CircuitBreaker(t *testing.T) {
+	hosted := &scriptedConnection{err: &apiError{status: 500}}
+	local := &scriptedConnection{}
+	config := DefaultCompositeConfig()
+	config.FailureThreshold = 2
+	config.Cooldown = time.Minute
+	conn, err := NewCompositeConnection([]Backend{
+		{Name: "hosted", Connection: hosted},
+		{Name: "ollama", Connection: local},
+	}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	conn.now = func() time.Time { return now }
+
+	servedBy(t, conn)
+	servedBy(t, conn)
+	if hosted.calls != 2 {
+		t.Fatalf("Expected 2 calls to open the breaker, got %d", hosted.calls)
+	}
+
+	// The breaker is open: hosted is skipped during the cooldown
+	servedBy(t, conn)
+	if hosted.calls != 2 {
+		t.Errorf("Expected hosted to be skipped, got %d calls", hosted.calls)
+	}
+
+	// After the cooldown a trial request goes through and closes the breaker
+	now = now.Add(2 * time.Minute)
+	hosted.err = nil
+	if name := servedBy(t, conn); name != "hosted" {
+		t.Errorf("Expected hosted to recover, served by %s", name)
+	}
+}
+
+func TestCompositeConnection_Strategies(t *testing.T) {
+	a, b := &scriptedConnection{}, &scriptedConnection{}
+
+	roundRobin, _ := NewCompositeConnection([]Backend{
+		{Name: "a", Connection: a},
+		{Name: "b", Connection: b},
+	}, &CompositeConfig{Strategy: StrategyRoundRobin})
+	var served []string
+	for i := 0; i < 4; i++ {
+		served = append(served, servedBy(t, roundRobin))
+	}
+	if served[0] != "a" || served[1] != "b" || served[2] != "a" || served[3] != "b" {
+		t.Errorf("Unexpected round-robin order: %v", served)
+	}
+
+	weighted, _ := NewCompositeConnection([]Backend{
+		{Name: "a", Connection: a, Weight: 3},
+		{Name: "b", Connection: b, Weight: 1},
+	}, &CompositeConfig{Strategy: StrategyWeighted})
+	counts := map[string]int{}
+	for i := 0; i < 8; i++ {
+		counts[servedBy(t, weighted)]++
+	}
+	if counts["a"] != 6 || counts["b"] != 2 {
+		t.Errorf("Expected a 3:1 split, got %v", counts)
+	}
+
+	if _, err := NewCompositeConnection([]Backend{{Name: "a", Connection: a}}, &CompositeConfig{Strategy: "random"}); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+}
+
+func TestCompositeConnection_Stream(t *testing.T) {
+	conn, _ := NewCompositeConnection([]Backend{
+		{Name: "hosted", Connection: &scriptedConnection{err: &apiError{status: 429}}},
+		{Name: "ollama", Connection: &scriptedConnection{}},
+	}, nil)
+
+	stream, err := conn.GenerateContentStream(context.Background(), &core.LLMRequest{})
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	count := 0
+	for response := range stream {
+		count++
+		if response.Metadata[BackendMetadataKey] != "ollama" {
+			t.Errorf("Expected stream served by ollama, got %v", response.Metadata)
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 streamed responses, got %d", count)
+	}
+}
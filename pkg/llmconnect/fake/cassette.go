@@ -0,0 +1,169 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// Interaction is one recorded request together with its answer.
+type Interaction struct {
+	Request  *core.LLMRequest  `json:"request"`
+	Partials []string          `json:"partials,omitempty"`
+	Response *core.LLMResponse `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// Cassette is a recording of the interactions with a connection.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette reads a cassette from a JSON file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to a JSON file.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Turns returns the interactions as scripted turns. Each turn expects the
+// last user message that was recorded with it.
+func (c *Cassette) Turns() []Turn {
+	turns := make([]Turn, 0, len(c.Interactions))
+	for _, interaction := range c.Interactions {
+		turn := Turn{
+			LastUserMessage: LastUserMessage(interaction.Request),
+			Response:        interaction.Response,
+			Partials:        interaction.Partials,
+		}
+		if interaction.Error != "" {
+			turn.Err = errors.New(interaction.Error)
+		}
+		turns = append(turns, turn)
+	}
+	return turns
+}
+
+// NewFromCassette creates a connection replaying the cassette at path.
+func NewFromCassette(path string) (*Connection, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(cassette.Turns()...), nil
+}
+
+var _ core.LLMConnection = (*Recorder)(nil)
+
+// Recorder implements the LLMConnection interface by passing requests to a
+// real connection and recording the interactions into a cassette.
+type Recorder struct {
+	conn core.LLMConnection
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a recorder in front of conn.
+func NewRecorder(conn core.LLMConnection) *Recorder {
+	return &Recorder{conn: conn}
+}
+
+// GenerateContent forwards the request and records the answer.
+func (r *Recorder) GenerateContent(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
+	response, err := r.conn.GenerateContent(ctx, request)
+	interaction := Interaction{Request: request, Response: response}
+	if err != nil {
+		interaction.Error = err.Error()
+	}
+	r.record(interaction)
+	return response, err
+}
+
+// GenerateContentStream forwards the request and records the streamed text
+// chunks together with the final response.
+func (r *Recorder) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
+	stream, err := r.conn.GenerateContentStream(ctx, request)
+	if err != nil {
+		r.record(Interaction{Request: request, Error: err.Error()})
+		return nil, err
+	}
+
+	responseChan := make(chan *core.LLMResponse, 10)
+	go func() {
+		defer close(responseChan)
+		interaction := Interaction{Request: request}
+		for response := range stream {
+			if response != nil && response.Partial != nil && *response.Partial {
+				interaction.Partials = append(interaction.Partials, responseText(response))
+			} else {
+				interaction.Response = response
+			}
+			select {
+			case responseChan <- response:
+			case <-ctx.Done():
+				return
+			}
+		}
+		r.record(interaction)
+	}()
+	return responseChan, nil
+}
+
+// Close closes the underlying connection.
+func (r *Recorder) Close(ctx context.Context) error {
+	return r.conn.Close(ctx)
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save writes the recorded interactions to a cassette file.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+func (r *Recorder) record(interaction Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+}
+
+// responseText joins the text parts of a response.
+func responseText(response *core.LLMResponse) string {
+	if response.Content == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, part := range response.Content.Parts {
+		if part.Text != nil {
+			b.WriteString(*part.Text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,275 @@
+// Package fake provides a scripted core.LLMConnection for testing agents
+// without a model server.
+//
+// A Connection answers requests from a script. Turns are consumed in order,
+// while rules registered with OnUserMessage answer any request whose last
+// user message contains their text:
+//
+//	conn := fake.New(
+//		fake.Turn{Response: fake.Call("search", map[string]any{"query": "weather"})},
+//		fake.Turn{Response: fake.Text("It is sunny."), Expect: fake.ExpectFunctionResponse("search")},
+//	)
+//	agent.SetLLMConnection(conn)
+//	...
+//	conn.AssertDone(t)
+//
+// Cassettes recorded from a real connection with a Recorder are replayed
+// with NewFromCassette.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// ErrNoResponse is returned for requests the script has no response for.
+var ErrNoResponse = errors.New("no scripted LLM response")
+
+// Turn is one scripted answer of a Connection.
+type Turn struct {
+	// LastUserMessage, when set, requires the text of the last user message
+	// of the request to contain it.
+	LastUserMessage string
+
+	// Expect checks the received request. A non-nil error fails the call
+	// and is reported by AssertDone.
+	Expect func(request *core.LLMRequest) error
+
+	// Response is the complete response. Streams end with it.
+	Response *core.LLMResponse
+
+	// Partials are text chunks streamed as partial responses before
+	// Response. GenerateContent ignores them.
+	Partials []string
+
+	// Err is returned instead of a response.
+	Err error
+}
+
+var _ core.LLMConnection = (*Connection)(nil)
+
+// Connection implements the LLMConnection interface by answering requests
+// from a script of turns and rules.
+type Connection struct {
+	mu       sync.Mutex
+	turns    []Turn
+	rules    []Turn
+	requests []*core.LLMRequest
+	failures []error
+	closed   bool
+}
+
+// New creates a connection answering requests with the given turns, in order.
+func New(turns ...Turn) *Connection {
+	return &Connection{turns: turns}
+}
+
+// AddTurns appends turns to the script.
+func (c *Connection) AddTurns(turns ...Turn) *Connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.turns = append(c.turns, turns...)
+	return c
+}
+
+// OnUserMessage registers a rule answering every request whose last user
+// message contains text, once the scripted turns are used up. Rules are
+// tried in registration order.
+func (c *Connection) OnUserMessage(text string, turn Turn) *Connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	turn.LastUserMessage = text
+	c.rules = append(c.rules, turn)
+	return c
+}
+
+// GenerateContent answers the request with the next matching turn.
+func (c *Connection) GenerateContent(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
+	turn, err := c.next(request)
+	if err != nil {
+		return nil, err
+	}
+	if turn.Err != nil {
+		return nil, turn.Err
+	}
+	return complete(turn.Response), nil
+}
+
+// GenerateContentStream streams the partials of the next matching turn,
+// followed by its complete response.
+func (c *Connection) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
+	turn, err := c.next(request)
+	if err != nil {
+		return nil, err
+	}
+	if turn.Err != nil {
+		return nil, turn.Err
+	}
+
+	stream := make(chan *core.LLMResponse, len(turn.Partials)+1)
+	go func() {
+		defer close(stream)
+		for _, chunk := range turn.Partials {
+			partial := &core.LLMResponse{
+				Content: &core.Content{Role: "model", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(chunk)}}},
+				Partial: ptr.Ptr(true),
+			}
+			select {
+			case stream <- partial:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case stream <- complete(turn.Response):
+		case <-ctx.Done():
+		}
+	}()
+	return stream, nil
+}
+
+// Close marks the connection as closed.
+func (c *Connection) Close(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+// Requests returns the requests received so far.
+func (c *Connection) Requests() []*core.LLMRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*core.LLMRequest(nil), c.requests...)
+}
+
+// LastRequest returns the most recent request, or nil if there was none.
+func (c *Connection) LastRequest() *core.LLMRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.requests) == 0 {
+		return nil
+	}
+	return c.requests[len(c.requests)-1]
+}
+
+// Remaining returns the number of scripted turns not yet used.
+func (c *Connection) Remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.turns)
+}
+
+// Closed reports whether Close was called.
+func (c *Connection) Closed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// AssertDone fails the test if a request did not match the script or
+// scripted turns were left unused.
+func (c *Connection) AssertDone(t testing.TB) {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, failure := range c.failures {
+		t.Errorf("fake LLM: %v", failure)
+	}
+	if len(c.turns) > 0 {
+		t.Errorf("fake LLM: %d scripted turns were not used", len(c.turns))
+	}
+}
+
+// next records the request and returns the turn answering it.
+func (c *Connection) next(request *core.LLMRequest) (Turn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests = append(c.requests, request)
+	index := len(c.requests)
+	message := LastUserMessage(request)
+
+	var turn Turn
+	switch {
+	case len(c.turns) > 0:
+		turn = c.turns[0]
+		c.turns = c.turns[1:]
+		if turn.LastUserMessage != "" && !strings.Contains(message, turn.LastUserMessage) {
+			return Turn{}, c.fail(fmt.Errorf("request %d: expected last user message containing %q, got %q",
+				index, turn.LastUserMessage, message))
+		}
+	default:
+		found := false
+		for _, rule := range c.rules {
+			if strings.Contains(message, rule.LastUserMessage) {
+				turn, found = rule, true
+				break
+			}
+		}
+		if !found {
+			return Turn{}, c.fail(fmt.Errorf("request %d with last user message %q: %w", index, message, ErrNoResponse))
+		}
+	}
+
+	if turn.Expect != nil {
+		if err := turn.Expect(request); err != nil {
+			return Turn{}, c.fail(fmt.Errorf("request %d: %w", index, err))
+		}
+	}
+	if turn.Response == nil && turn.Err == nil {
+		return Turn{}, c.fail(fmt.Errorf("request %d: turn has no response", index))
+	}
+	return turn, nil
+}
+
+// fail records a script mismatch for AssertDone and returns it.
+func (c *Connection) fail(err error) error {
+	c.failures = append(c.failures, err)
+	return err
+}
+
+// complete returns a copy of a scripted response marked as complete, so
+// callers can annotate it without changing the script.
+func complete(response *core.LLMResponse) *core.LLMResponse {
+	clone := *response
+	clone.Partial = ptr.Ptr(false)
+	if response.Metadata != nil {
+		clone.Metadata = make(map[string]any, len(response.Metadata))
+		for k, v := range response.Metadata {
+			clone.Metadata[k] = v
+		}
+	}
+	return &clone
+}
+
+// LastUserMessage returns the text of the last user content in the request
+// that carries text.
+func LastUserMessage(request *core.LLMRequest) string {
+	if request == nil {
+		return ""
+	}
+	for i := len(request.Contents) - 1; i >= 0; i-- {
+		content := request.Contents[i]
+		if content.Role != "user" {
+			continue
+		}
+		var texts []string
+		for _, part := range content.Parts {
+			if part.Text != nil {
+				texts = append(texts, *part.Text)
+			}
+		}
+		if len(texts) > 0 {
+			return strings.Join(texts, "\n")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+func userRequest(text string) *core.LLMRequest {
+	return &core.LLMRequest{
+		Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}}},
+		Tools:    []*core.FunctionDeclaration{{Name: "search"}},
+	}
+}
+
+// recordingT captures the failures AssertDone reports.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, format)
+}
+
+func TestConnection_ScriptedTurns(t *testing.T) {
+	conn := New(
+		Turn{Response: Call("search", map[string]any{"q": "go"}), Expect: ExpectTools("search")},
+		Turn{Response: Text("found it"), Expect: ExpectFunctionResponse("search")},
+	)
+	ctx := context.Background()
+
+	response, err := conn.GenerateContent(ctx, userRequest("find go"))
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+	call := response.Content.Parts[0].FunctionCall
+	if call == nil || call.ID != "call_0" || call.Name != "search" || call.Args["q"] != "go" {
+		t.Errorf("Unexpected function call: %+v", call)
+	}
+
+	// The second turn expects the tool result in the request
+	if _, err := conn.GenerateContent(ctx, userRequest("find go")); err == nil {
+		t.Fatal("Expected the expectation to fail without a function response")
+	}
+	recorder := &recordingT{TB: t}
+	conn.AssertDone(recorder)
+	if len(recorder.errors) != 1 {
+		t.Errorf("Expected the failed expectation to be reported, got %v", recorder.errors)
+	}
+
+	if _, err := conn.GenerateContent(ctx, userRequest("again")); !errors.Is(err, ErrNoResponse) {
+		t.Errorf("Expected ErrNoResponse once the script is used up, got %v", err)
+	}
+	if len(conn.Requests()) != 3 || LastUserMessage(conn.LastRequest()) != "again" {
+		t.Errorf("Expected all requests to be recorded, got %d", len(conn.Requests()))
+	}
+}
+
+func TestConnection_UserMessageRules(t *testing.T) {
+	conn := New().
+		OnUserMessage("weather", Turn{Response: Text("sunny")}).
+		OnUserMessage("", Turn{Err: errors.New("unavailable")})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		response, err := conn.GenerateContent(ctx, userRequest("what is the weather?"))
+		if err != nil || *response.Content.Parts[0].Text != "sunny" {
+			t.Fatalf("Expected the weather rule to answer, got %+v (err=%v)", response, err)
+		}
+	}
+	if _, err := conn.GenerateContent(ctx, userRequest("hello")); err == nil || err.Error() != "unavailable" {
+		t.Errorf("Expected the catch-all rule error, got %v", err)
+	}
+	conn.AssertDone(t)
+}
+
+func TestConnection_Stream(t *testing.T) {
+	conn := New(Turn{Response: Text("Hello world"), Partials: []string{"Hello ", "world"}})
+
+	stream, err := conn.GenerateContentStream(context.Background(), userRequest("hi"))
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	var responses []*core.LLMResponse
+	for response := range stream {
+		responses = append(responses, response)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("Expected 2 partials and a final response, got %d", len(responses))
+	}
+	if !*responses[0].Partial || *responses[1].Content.Parts[0].Text != "world" {
+		t.Errorf("Unexpected partial responses")
+	}
+	if *responses[2].Partial || *responses[2].Content.Parts[0].Text != "Hello world" {
+		t.Errorf("Unexpected final response: %+v", responses[2])
+	}
+	conn.AssertDone(t)
+}
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	replayed, err := NewFromCassette(filepath.Join("testdata", "weather.json"))
+	if err != nil {
+		t.Fatalf("NewFromCassette failed: %v", err)
+	}
+	if replayed.Remaining() != 2 {
+		t.Fatalf("Expected 2 recorded turns, got %d", replayed.Remaining())
+	}
+
+	// Record the cassette again through a recorder and replay the recording
+	recorder := NewRecorder(replayed)
+	ctx := context.Background()
+	response, err := recorder.GenerateContent(ctx, userRequest("What is the weather in Melbourne?"))
+	if err != nil || response.Content.Parts[0].FunctionCall == nil || response.Usage.InputTokens != 31 {
+		t.Fatalf("Unexpected first response: %+v (err=%v)", response, err)
+	}
+	stream, err := recorder.GenerateContentStream(ctx, userRequest("What is the weather in Melbourne?"))
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	for range stream {
+	}
+	replayed.AssertDone(t)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	if len(cassette.Interactions) != 2 || len(cassette.Interactions[1].Partials) != 2 ||
+		*cassette.Interactions[1].Response.Content.Parts[0].Text != "It is sunny and 24C in Melbourne." {
+		t.Errorf("Unexpected recording: %+v", cassette.Interactions)
+	}
+
+	// Replayed turns check the last user message they were recorded with
+	conn := New(cassette.Turns()...)
+	if _, err := conn.GenerateContent(ctx, userRequest("What is the weather in Sydney?")); err == nil {
+		t.Error("Expected a request for another city not to match the cassette")
+	}
+}
@@ -0,0 +1,86 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// Text returns a model response with a single text part.
+func Text(text string) *core.LLMResponse {
+	return &core.LLMResponse{
+		Content: &core.Content{Role: "model", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}},
+	}
+}
+
+// Call returns a model response calling a single tool.
+func Call(name string, args map[string]any) *core.LLMResponse {
+	return Calls(core.FunctionCall{Name: name, Args: args})
+}
+
+// Calls returns a model response calling several tools. Calls without an ID
+// are numbered call_0, call_1, ... in order.
+func Calls(calls ...core.FunctionCall) *core.LLMResponse {
+	parts := make([]core.Part, 0, len(calls))
+	for i, call := range calls {
+		call := call
+		if call.ID == "" {
+			call.ID = fmt.Sprintf("call_%d", i)
+		}
+		parts = append(parts, core.Part{Type: "function_call", FunctionCall: &call})
+	}
+	return &core.LLMResponse{Content: &core.Content{Role: "model", Parts: parts}}
+}
+
+// ExpectTools checks that the request declares exactly the named tools.
+func ExpectTools(names ...string) func(*core.LLMRequest) error {
+	return func(request *core.LLMRequest) error {
+		declared := make([]string, 0, len(request.Tools))
+		for _, tool := range request.Tools {
+			declared = append(declared, tool.Name)
+		}
+		if strings.Join(declared, ",") != strings.Join(names, ",") {
+			return fmt.Errorf("expected tools %v, got %v", names, declared)
+		}
+		return nil
+	}
+}
+
+// ExpectFunctionResponse checks that the request carries a result of the
+// named tool.
+func ExpectFunctionResponse(name string) func(*core.LLMRequest) error {
+	return func(request *core.LLMRequest) error {
+		for _, content := range request.Contents {
+			for _, part := range content.Parts {
+				if part.FunctionResponse != nil && part.FunctionResponse.Name == name {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("expected a function response from %s", name)
+	}
+}
+
+// ExpectSystemInstruction checks that the system instruction of the request,
+// either a system content or the config, contains text.
+func ExpectSystemInstruction(text string) func(*core.LLMRequest) error {
+	return func(request *core.LLMRequest) error {
+		if request.Config != nil && request.Config.SystemInstruction != nil &&
+			strings.Contains(*request.Config.SystemInstruction, text) {
+			return nil
+		}
+		for _, content := range request.Contents {
+			if content.Role != "system" {
+				continue
+			}
+			for _, part := range content.Parts {
+				if part.Text != nil && strings.Contains(*part.Text, text) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("expected a system instruction containing %q", text)
+	}
+}
@@ -0,0 +1,554 @@
+package gemini
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+var _ core.LLMConnection = (*GeminiConnection)(nil)
+
+// GeminiConnection implements the LLMConnection interface for the Gemini
+// generateContent REST API.
+type GeminiConnection struct {
+	baseURL    string
+	httpClient *http.Client
+	model      string
+	config     *GeminiConfig
+}
+
+// GeminiConfig contains configuration options for Gemini connections.
+type GeminiConfig struct {
+	// BaseURL is the API root including the version, e.g.
+	// https://generativelanguage.googleapis.com/v1beta.
+	BaseURL     string        `json:"base_url"`
+	APIKey      string        `json:"-"`
+	Model       string        `json:"model"`
+	Temperature *float32      `json:"temperature,omitempty"`
+	MaxTokens   *int          `json:"max_tokens,omitempty"`
+	TopP        *float32      `json:"top_p,omitempty"`
+	TopK        *int          `json:"top_k,omitempty"`
+	Timeout     time.Duration `json:"timeout"`
+
+	// SafetySettings override the default blocking thresholds.
+	SafetySettings []SafetySetting `json:"safety_settings,omitempty"`
+}
+
+// DefaultGeminiConfig returns a default configuration for Gemini.
+// The API key is read from GEMINI_API_KEY, falling back to GOOGLE_API_KEY,
+// and the base URL from GEMINI_BASE_URL.
+func DefaultGeminiConfig() *GeminiConfig {
+	baseURL := os.Getenv("GEMINI_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		apiKey = os.Getenv("GOOGLE_API_KEY")
+	}
+	return &GeminiConfig{
+		BaseURL: baseURL,
+		APIKey:  apiKey,
+		Model:   "gemini-1.5-pro",
+		Timeout: 60 * time.Second,
+	}
+}
+
+// NewGeminiConnection creates a new Gemini connection with the given configuration.
+func NewGeminiConnection(config *GeminiConfig) *GeminiConnection {
+	if config == nil {
+		config = DefaultGeminiConfig()
+	}
+
+	return &GeminiConnection{
+		baseURL: strings.TrimSuffix(config.BaseURL, "/"),
+		model:   strings.TrimPrefix(config.Model, "models/"),
+		config:  config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// GenerateContent sends a generateContent request and returns the response.
+// A candidate withheld by a safety filter, or a blocked prompt, is returned
+// without content and with Metadata["blocked"] set to true.
+func (c *GeminiConnection) GenerateContent(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
+	genReq, err := c.convertToGeminiRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := c.makeHTTPRequest(ctx, c.modelEndpoint("generateContent", nil), genReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var genResp GenerateContentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return c.convertFromGeminiResponse(&genResp), nil
+}
+
+// GenerateContentStream sends a streamGenerateContent request. Partial
+// responses carry the text of each chunk; the last response is complete and
+// holds the full text, the function calls and the usage. A stream failure is
+// reported as a final response with Metadata["error"] set.
+func (c *GeminiConnection) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
+	genReq, err := c.convertToGeminiRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	query := url.Values{"alt": {"sse"}}
+	resp, err := c.makeHTTPRequest(ctx, c.modelEndpoint("streamGenerateContent", query), genReq)
+	if err != nil {
+		return nil, err
+	}
+
+	responseChan := make(chan *core.LLMResponse, 10)
+
+	go func() {
+		defer close(responseChan)
+		defer resp.Body.Close()
+
+		send := func(response *core.LLMResponse) bool {
+			select {
+			case responseChan <- response:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		acc := &streamAccumulator{}
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+		for scanner.Scan() {
+			data, found := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+			if !found {
+				continue
+			}
+
+			var chunk GenerateContentResponse
+			if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &chunk); err != nil {
+				send(streamError(fmt.Errorf("failed to decode stream chunk: %w", err)))
+				return
+			}
+
+			if thought, delta := acc.add(&chunk); thought != "" || delta != "" {
+				partial := &core.LLMResponse{
+					Content: &core.Content{Role: "model"},
+					Partial: ptr.Ptr(true),
+				}
+				if thought != "" {
+					partial.Content.Parts = append(partial.Content.Parts, core.NewThoughtPart(thought))
+				}
+				if delta != "" {
+					partial.Content.Parts = append(partial.Content.Parts, core.Part{Type: "text", Text: ptr.Ptr(delta)})
+				}
+				if !send(partial) {
+					return
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			send(streamError(fmt.Errorf("failed to read stream: %w", err)))
+			return
+		}
+
+		send(c.convertFromGeminiResponse(acc.response()))
+	}()
+
+	return responseChan, nil
+}
+
+// Close closes the connection (no-op for HTTP-based connections).
+func (c *GeminiConnection) Close(ctx context.Context) error {
+	return nil
+}
+
+// convertToGeminiRequest converts an ADK LLMRequest to the generateContent format.
+func (c *GeminiConnection) convertToGeminiRequest(request *core.LLMRequest) (*GenerateContentRequest, error) {
+	if request == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	genReq := &GenerateContentRequest{
+		Contents:       make([]Content, 0, len(request.Contents)),
+		SafetySettings: c.config.SafetySettings,
+	}
+
+	// System contents are hoisted into systemInstruction; the config
+	// instruction is used when the contents carry none.
+	var systemParts []Part
+	for _, content := range request.Contents {
+		if content.Role == "system" {
+			for _, part := range content.Parts {
+				if part.Text != nil && *part.Text != "" {
+					systemParts = append(systemParts, Part{Text: *part.Text})
+				}
+			}
+			continue
+		}
+
+		converted, err := c.convertContent(content)
+		if err != nil {
+			return nil, err
+		}
+		if len(converted.Parts) == 0 {
+			continue
+		}
+
+		// Consecutive turns of the same role are merged, which keeps a
+		// function response directly after the call that requested it.
+		if n := len(genReq.Contents); n > 0 && genReq.Contents[n-1].Role == converted.Role {
+			genReq.Contents[n-1].Parts = append(genReq.Contents[n-1].Parts, converted.Parts...)
+			continue
+		}
+		genReq.Contents = append(genReq.Contents, converted)
+	}
+	if len(systemParts) == 0 && request.Config != nil && request.Config.SystemInstruction != nil && *request.Config.SystemInstruction != "" {
+		systemParts = []Part{{Text: *request.Config.SystemInstruction}}
+	}
+	if len(systemParts) > 0 {
+		genReq.SystemInstruction = &Content{Parts: systemParts}
+	}
+
+	if len(request.Tools) > 0 {
+		declarations := make([]FunctionDeclaration, 0, len(request.Tools))
+		for _, tool := range request.Tools {
+			declarations = append(declarations, FunctionDeclaration{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			})
+		}
+		genReq.Tools = []Tool{{FunctionDeclarations: declarations}}
+	}
+
+	// Apply connection-level configuration first
+	genConfig := &GenerationConfig{
+		Temperature:     c.config.Temperature,
+		MaxOutputTokens: c.config.MaxTokens,
+		TopP:            c.config.TopP,
+		TopK:            c.config.TopK,
+	}
+
+	// Apply request-level configuration (overrides connection config)
+	if request.Config != nil {
+		if request.Config.Temperature != nil {
+			genConfig.Temperature = request.Config.Temperature
+		}
+		if request.Config.MaxTokens != nil {
+			genConfig.MaxOutputTokens = request.Config.MaxTokens
+		}
+		if request.Config.TopP != nil {
+			genConfig.TopP = request.Config.TopP
+		}
+		if request.Config.TopK != nil {
+			genConfig.TopK = request.Config.TopK
+		}
+		genConfig.ResponseMIMEType = request.Config.ResponseMIMEType
+		if request.Config.ResponseSchema != nil {
+			genConfig.ResponseMIMEType = "application/json"
+			genConfig.ResponseJSONSchema = request.Config.ResponseSchema
+		}
+		if request.Config.Thinking != nil {
+			genConfig.ThinkingConfig = &ThinkingConfig{IncludeThoughts: true}
+			if request.Config.Thinking.BudgetTokens > 0 {
+				genConfig.ThinkingConfig.ThinkingBudget = ptr.Ptr(request.Config.Thinking.BudgetTokens)
+			}
+		}
+	}
+	if !reflect.DeepEqual(*genConfig, GenerationConfig{}) {
+		genReq.GenerationConfig = genConfig
+	}
+
+	return genReq, nil
+}
+
+// convertContent converts one ADK content into a Gemini turn. Function
+// responses are sent by the "user" role as required by the API.
+func (c *GeminiConnection) convertContent(content core.Content) (Content, error) {
+	converted := Content{Role: c.mapRole(content.Role)}
+
+	for _, part := range content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			converted.Parts = append(converted.Parts, Part{
+				FunctionCall: &FunctionCall{
+					ID:   part.FunctionCall.ID,
+					Name: part.FunctionCall.Name,
+					Args: part.FunctionCall.Args,
+				},
+			})
+		case part.FunctionResponse != nil:
+			if part.FunctionResponse.Name == "" {
+				return Content{}, fmt.Errorf("function response %q has no name", part.FunctionResponse.ID)
+			}
+			response := part.FunctionResponse.Response
+			if response == nil {
+				response = map[string]any{}
+			}
+			converted.Role = "user"
+			converted.Parts = append(converted.Parts, Part{
+				FunctionResponse: &FunctionResponse{
+					ID:       part.FunctionResponse.ID,
+					Name:     part.FunctionResponse.Name,
+					Response: response,
+				},
+			})
+		case part.Text != nil && *part.Text != "":
+			converted.Parts = append(converted.Parts, Part{Text: *part.Text})
+		}
+	}
+
+	return converted, nil
+}
+
+// convertFromGeminiResponse converts a generateContent response to ADK format.
+func (c *GeminiConnection) convertFromGeminiResponse(resp *GenerateContentResponse) *core.LLMResponse {
+	response := &core.LLMResponse{
+		Partial:  ptr.Ptr(false),
+		Metadata: make(map[string]any),
+	}
+
+	if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != "" {
+		response.Metadata["blocked"] = true
+		response.Metadata["block_reason"] = resp.PromptFeedback.BlockReason
+		if len(resp.PromptFeedback.SafetyRatings) > 0 {
+			response.Metadata["safety_ratings"] = resp.PromptFeedback.SafetyRatings
+		}
+	}
+
+	if len(resp.Candidates) > 0 {
+		candidate := resp.Candidates[0]
+		if candidate.FinishReason != "" {
+			response.Metadata["finish_reason"] = candidate.FinishReason
+		}
+
+		if blockedFinishReasons[candidate.FinishReason] {
+			response.Metadata["blocked"] = true
+			if len(candidate.SafetyRatings) > 0 {
+				response.Metadata["safety_ratings"] = candidate.SafetyRatings
+			}
+		} else if candidate.Content != nil {
+			content := &core.Content{
+				Role:  "model",
+				Parts: make([]core.Part, 0, len(candidate.Content.Parts)),
+			}
+			calls := 0
+			for _, part := range candidate.Content.Parts {
+				switch {
+				case part.FunctionCall != nil:
+					id := part.FunctionCall.ID
+					if id == "" {
+						id = fmt.Sprintf("call_%d", calls)
+					}
+					args := part.FunctionCall.Args
+					if args == nil {
+						args = map[string]any{}
+					}
+					content.Parts = append(content.Parts, core.Part{
+						Type: "function_call",
+						FunctionCall: &core.FunctionCall{
+							ID:   id,
+							Name: part.FunctionCall.Name,
+							Args: args,
+						},
+					})
+					calls++
+				case part.Thought && part.Text != "":
+					content.Parts = append(content.Parts, core.NewThoughtPart(part.Text))
+				case part.Text != "":
+					content.Parts = append(content.Parts, core.Part{
+						Type: "text",
+						Text: ptr.Ptr(part.Text),
+					})
+				}
+			}
+			if len(content.Parts) > 0 {
+				response.Content = content
+			}
+		}
+	}
+
+	if resp.ResponseID != "" {
+		response.Metadata["id"] = resp.ResponseID
+	}
+	if resp.ModelVersion != "" {
+		response.Metadata["model"] = resp.ModelVersion
+	}
+	if resp.UsageMetadata != nil {
+		response.Metadata["prompt_tokens"] = resp.UsageMetadata.PromptTokenCount
+		response.Metadata["completion_tokens"] = resp.UsageMetadata.CandidatesTokenCount
+		response.Metadata["total_tokens"] = resp.UsageMetadata.TotalTokenCount
+		if resp.UsageMetadata.CachedContentTokenCount > 0 {
+			response.Metadata["cached_tokens"] = resp.UsageMetadata.CachedContentTokenCount
+		}
+		response.Usage = &core.Usage{
+			InputTokens:  resp.UsageMetadata.PromptTokenCount,
+			OutputTokens: resp.UsageMetadata.CandidatesTokenCount,
+			CachedTokens: resp.UsageMetadata.CachedContentTokenCount,
+			TotalTokens:  resp.UsageMetadata.TotalTokenCount,
+			LLMCalls:     1,
+		}
+	}
+
+	return response
+}
+
+// modelEndpoint returns the URL of a method on the configured model.
+func (c *GeminiConnection) modelEndpoint(method string, query url.Values) string {
+	endpoint := fmt.Sprintf("%s/models/%s:%s", c.baseURL, url.PathEscape(c.model), method)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint
+}
+
+// makeHTTPRequest posts a JSON payload to the API.
+func (c *GeminiConnection) makeHTTPRequest(ctx context.Context, endpoint string, payload any) (*http.Response, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.APIKey != "" {
+		req.Header.Set("x-goog-api-key", c.config.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		var errResp errorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != nil {
+			errResp.Error.StatusCode = resp.StatusCode
+			return nil, errResp.Error
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	return resp, nil
+}
+
+// mapRole maps ADK roles to Gemini roles.
+func (c *GeminiConnection) mapRole(role string) string {
+	switch role {
+	case "agent", "model", "assistant":
+		return "model"
+	default:
+		return "user"
+	}
+}
+
+// streamError wraps a stream failure in a final response.
+func streamError(err error) *core.LLMResponse {
+	return &core.LLMResponse{
+		Partial:  ptr.Ptr(false),
+		Metadata: map[string]any{"error": err.Error()},
+	}
+}
+
+// streamAccumulator assembles the chunks of a stream into a complete response.
+type streamAccumulator struct {
+	resp     GenerateContentResponse
+	thoughts strings.Builder
+	text     strings.Builder
+	calls    []Part
+}
+
+// add merges a chunk and returns its thought and text deltas.
+func (a *streamAccumulator) add(chunk *GenerateContentResponse) (string, string) {
+	if chunk.ResponseID != "" {
+		a.resp.ResponseID = chunk.ResponseID
+	}
+	if chunk.ModelVersion != "" {
+		a.resp.ModelVersion = chunk.ModelVersion
+	}
+	if chunk.UsageMetadata != nil {
+		a.resp.UsageMetadata = chunk.UsageMetadata
+	}
+	if chunk.PromptFeedback != nil {
+		a.resp.PromptFeedback = chunk.PromptFeedback
+	}
+
+	var thought, delta string
+	for _, candidate := range chunk.Candidates {
+		if candidate.Index != 0 {
+			continue
+		}
+		if candidate.FinishReason != "" {
+			a.resp.Candidates = []Candidate{{
+				FinishReason:  candidate.FinishReason,
+				SafetyRatings: candidate.SafetyRatings,
+			}}
+		}
+		if candidate.Content == nil {
+			continue
+		}
+		for _, part := range candidate.Content.Parts {
+			if part.FunctionCall != nil {
+				// Function calls are never split across chunks
+				a.calls = append(a.calls, part)
+			} else if part.Thought {
+				thought += part.Text
+			} else {
+				delta += part.Text
+			}
+		}
+	}
+
+	a.thoughts.WriteString(thought)
+	a.text.WriteString(delta)
+	return thought, delta
+}
+
+// response returns the accumulated response.
+func (a *streamAccumulator) response() *GenerateContentResponse {
+	candidate := Candidate{}
+	if len(a.resp.Candidates) > 0 {
+		candidate = a.resp.Candidates[0]
+	}
+	content := &Content{Role: "model"}
+	if a.thoughts.Len() > 0 {
+		content.Parts = append(content.Parts, Part{Text: a.thoughts.String(), Thought: true})
+	}
+	if a.text.Len() > 0 {
+		content.Parts = append(content.Parts, Part{Text: a.text.String()})
+	}
+	content.Parts = append(content.Parts, a.calls...)
+	candidate.Content = content
+
+	resp := a.resp
+	resp.Candidates = []Candidate{candidate}
+	return &resp
+}
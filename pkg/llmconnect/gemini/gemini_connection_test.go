@@ -0,0 +1,315 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// fixtureServer replays a recorded response from testdata and captures the
+// request it was sent.
+type fixtureServer struct {
+	t       *testing.T
+	fixture string
+	status  int
+
+	path    string
+	query   string
+	apiKey  string
+	request GenerateContentRequest
+}
+
+func (s *fixtureServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.path = r.URL.Path
+	s.query = r.URL.RawQuery
+	s.apiKey = r.Header.Get("x-goog-api-key")
+	if err := json.NewDecoder(r.Body).Decode(&s.request); err != nil {
+		s.t.Errorf("Failed to decode request: %v", err)
+	}
+
+	body, err := os.ReadFile(filepath.Join("testdata", s.fixture))
+	if err != nil {
+		s.t.Fatalf("Failed to read fixture: %v", err)
+	}
+	if filepath.Ext(s.fixture) == ".sse" {
+		w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+	}
+	w.Write(body)
+}
+
+func newTestConnection(t *testing.T, fixture string) (*GeminiConnection, *fixtureServer) {
+	recorder := &fixtureServer{t: t, fixture: fixture}
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+
+	config := DefaultGeminiConfig()
+	config.BaseURL = server.URL + "/v1beta/"
+	config.APIKey = "test-key"
+	config.Model = "gemini-1.5-pro"
+	return NewGeminiConnection(config), recorder
+}
+
+func TestGenerateContent_FunctionCalling(t *testing.T) {
+	conn, recorder := newTestConnection(t, "generate_content_function_call.json")
+
+	request := &core.LLMRequest{
+		Contents: []core.Content{
+			{Role: "system", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Be brief.")}}},
+			{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Weather in Berlin and Paris?")}}},
+			{Role: "agent", Parts: []core.Part{{
+				Type:         "function_call",
+				FunctionCall: &core.FunctionCall{ID: "call_1", Name: "get_weather", Args: map[string]any{"location": "Berlin"}},
+			}}},
+			{Role: "agent", Parts: []core.Part{{
+				Type:             "function_response",
+				FunctionResponse: &core.FunctionResponse{ID: "call_1", Name: "get_weather", Response: map[string]any{"temp": 21}},
+			}}},
+		},
+		Config: &core.LLMConfig{
+			SystemInstruction: ptr.Ptr("Ignored because the contents carry one."),
+			Temperature:       ptr.Float32(0.2),
+			TopK:              ptr.Ptr(40),
+		},
+		Tools: []*core.FunctionDeclaration{{
+			Name:        "get_weather",
+			Description: "Get weather",
+			Parameters:  map[string]any{"type": "object", "properties": map[string]any{"location": map[string]any{"type": "string"}}},
+		}},
+	}
+
+	response, err := conn.GenerateContent(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+
+	// Request conversion
+	received := recorder.request
+	if recorder.path != "/v1beta/models/gemini-1.5-pro:generateContent" {
+		t.Errorf("Unexpected path: %s", recorder.path)
+	}
+	if recorder.apiKey != "test-key" {
+		t.Errorf("Unexpected API key header: %q", recorder.apiKey)
+	}
+	if received.SystemInstruction == nil || len(received.SystemInstruction.Parts) != 1 || received.SystemInstruction.Parts[0].Text != "Be brief." {
+		t.Errorf("Expected system instruction to be hoisted, got %+v", received.SystemInstruction)
+	}
+	if len(received.Contents) != 3 {
+		t.Fatalf("Expected 3 contents, got %d: %+v", len(received.Contents), received.Contents)
+	}
+	if received.Contents[0].Role != "user" || received.Contents[0].Parts[0].Text != "Weather in Berlin and Paris?" {
+		t.Errorf("Unexpected user content: %+v", received.Contents[0])
+	}
+	call := received.Contents[1].Parts[0].FunctionCall
+	if received.Contents[1].Role != "model" || call == nil || call.Name != "get_weather" || call.Args["location"] != "Berlin" {
+		t.Errorf("Unexpected model content: %+v", received.Contents[1])
+	}
+	result := received.Contents[2].Parts[0].FunctionResponse
+	if received.Contents[2].Role != "user" || result == nil || result.Name != "get_weather" || result.Response["temp"] != float64(21) {
+		t.Errorf("Unexpected function response content: %+v", received.Contents[2])
+	}
+	if len(received.Tools) != 1 || len(received.Tools[0].FunctionDeclarations) != 1 || received.Tools[0].FunctionDeclarations[0].Name != "get_weather" {
+		t.Errorf("Unexpected tools: %+v", received.Tools)
+	}
+	genConfig := received.GenerationConfig
+	if genConfig == nil || genConfig.Temperature == nil || *genConfig.Temperature != 0.2 || genConfig.TopK == nil || *genConfig.TopK != 40 {
+		t.Errorf("Unexpected generation config: %+v", genConfig)
+	}
+
+	// Response conversion
+	if response.Partial == nil || *response.Partial {
+		t.Error("Expected a complete response")
+	}
+	if response.Content == nil || response.Content.Role != "model" || len(response.Content.Parts) != 1 {
+		t.Fatalf("Unexpected content: %+v", response.Content)
+	}
+	got := response.Content.Parts[0].FunctionCall
+	if got == nil || got.ID != "call_0" || got.Name != "get_weather" || got.Args["location"] != "Paris" {
+		t.Errorf("Unexpected function call: %+v", got)
+	}
+	if response.Metadata["prompt_tokens"] != 42 || response.Metadata["completion_tokens"] != 6 ||
+		response.Metadata["finish_reason"] != "STOP" || response.Metadata["id"] != "resp-fc-1" {
+		t.Errorf("Unexpected metadata: %v", response.Metadata)
+	}
+	if response.Usage == nil || response.Usage.InputTokens != 42 || response.Usage.OutputTokens != 6 || response.Usage.TotalTokens != 48 {
+		t.Errorf("Unexpected usage: %+v", response.Usage)
+	}
+}
+
+func TestGenerateContent_SystemInstructionFromConfig(t *testing.T) {
+	conn, recorder := newTestConnection(t, "generate_content_function_call.json")
+
+	request := &core.LLMRequest{
+		Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}}},
+		Config: &core.LLMConfig{
+			SystemInstruction: ptr.Ptr("You are helpful."),
+			ResponseMIMEType:  "application/json",
+		},
+	}
+	if _, err := conn.GenerateContent(context.Background(), request); err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+
+	received := recorder.request
+	if received.SystemInstruction == nil || received.SystemInstruction.Parts[0].Text != "You are helpful." {
+		t.Errorf("Expected config system instruction, got %+v", received.SystemInstruction)
+	}
+	if received.GenerationConfig == nil || received.GenerationConfig.ResponseMIMEType != "application/json" {
+		t.Errorf("Expected JSON response MIME type, got %+v", received.GenerationConfig)
+	}
+	if received.GenerationConfig.ResponseJSONSchema != nil {
+		t.Errorf("Expected no response schema, got %v", received.GenerationConfig.ResponseJSONSchema)
+	}
+
+	request.Config.ResponseMIMEType = ""
+	request.Config.ResponseSchema = map[string]any{"type": "object"}
+	if _, err := conn.GenerateContent(context.Background(), request); err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+	received = recorder.request
+	if received.GenerationConfig == nil || received.GenerationConfig.ResponseMIMEType != "application/json" ||
+		received.GenerationConfig.ResponseJSONSchema["type"] != "object" {
+		t.Errorf("Expected the response schema to enable JSON output, got %+v", received.GenerationConfig)
+	}
+	if received.Tools != nil {
+		t.Errorf("Expected no tools, got %+v", received.Tools)
+	}
+}
+
+func TestGenerateContent_SafetyBlocked(t *testing.T) {
+	tests := []struct {
+		name        string
+		fixture     string
+		blockReason string
+	}{
+		{name: "candidate", fixture: "generate_content_safety.json"},
+		{name: "prompt", fixture: "generate_content_prompt_blocked.json", blockReason: "SAFETY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, _ := newTestConnection(t, tt.fixture)
+
+			response, err := conn.GenerateContent(context.Background(), &core.LLMRequest{
+				Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("...")}}}},
+			})
+			if err != nil {
+				t.Fatalf("GenerateContent failed: %v", err)
+			}
+			if response.Content != nil {
+				t.Errorf("Expected no content for a blocked response, got %+v", response.Content)
+			}
+			if response.Metadata["blocked"] != true {
+				t.Errorf("Expected blocked metadata, got %v", response.Metadata)
+			}
+			if tt.blockReason != "" && response.Metadata["block_reason"] != tt.blockReason {
+				t.Errorf("Expected block reason %s, got %v", tt.blockReason, response.Metadata["block_reason"])
+			}
+			if ratings, ok := response.Metadata["safety_ratings"].([]SafetyRating); !ok || len(ratings) == 0 {
+				t.Errorf("Expected safety ratings, got %v", response.Metadata["safety_ratings"])
+			}
+		})
+	}
+}
+
+func TestGenerateContentStream(t *testing.T) {
+	conn, recorder := newTestConnection(t, "stream_generate_content.sse")
+
+	request := &core.LLMRequest{Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}}}}
+	stream, err := conn.GenerateContentStream(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+
+	var responses []*core.LLMResponse
+	for response := range stream {
+		responses = append(responses, response)
+	}
+	if recorder.path != "/v1beta/models/gemini-1.5-pro:streamGenerateContent" || recorder.query != "alt=sse" {
+		t.Errorf("Unexpected stream endpoint: %s?%s", recorder.path, recorder.query)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("Expected 2 partials and a final response, got %d", len(responses))
+	}
+	if !*responses[0].Partial || *responses[0].Content.Parts[0].Text != "Let me " || *responses[1].Content.Parts[0].Text != "check." {
+		t.Errorf("Unexpected partial responses")
+	}
+
+	final := responses[2]
+	if *final.Partial {
+		t.Error("Expected the last response to be complete")
+	}
+	if len(final.Content.Parts) != 2 || *final.Content.Parts[0].Text != "Let me check." {
+		t.Fatalf("Unexpected final content: %+v", final.Content)
+	}
+	call := final.Content.Parts[1].FunctionCall
+	if call == nil || call.Name != "lookup" || call.Args["q"] != "go" {
+		t.Errorf("Unexpected function call: %+v", call)
+	}
+	if final.Metadata["total_tokens"] != 19 || final.Metadata["finish_reason"] != "STOP" {
+		t.Errorf("Unexpected final metadata: %v", final.Metadata)
+	}
+}
+
+func TestGenerateContent_APIError(t *testing.T) {
+	conn, recorder := newTestConnection(t, "error_rate_limited.json")
+	recorder.status = http.StatusTooManyRequests
+
+	_, err := conn.GenerateContent(context.Background(), &core.LLMRequest{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Status != "RESOURCE_EXHAUSTED" || !apiErr.Retryable() {
+		t.Errorf("Unexpected API error: %+v", apiErr)
+	}
+}
+
+func TestGenerateContentStream_Thinking(t *testing.T) {
+	conn, recorder := newTestConnection(t, "stream_generate_content_thinking.sse")
+
+	request := &core.LLMRequest{
+		Contents: []core.Content{
+			{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}},
+			{Role: "model", Parts: []core.Part{core.NewThoughtPart("earlier reasoning"), {Type: "text", Text: ptr.Ptr("Hi.")}}},
+			{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi again")}}},
+		},
+		Config: &core.LLMConfig{Thinking: &core.ThinkingConfig{BudgetTokens: 512}},
+	}
+	stream, err := conn.GenerateContentStream(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	var responses []*core.LLMResponse
+	for response := range stream {
+		responses = append(responses, response)
+	}
+
+	thinking := recorder.request.GenerationConfig.ThinkingConfig
+	if thinking == nil || !thinking.IncludeThoughts || thinking.ThinkingBudget == nil || *thinking.ThinkingBudget != 512 {
+		t.Errorf("Unexpected thinking config: %+v", thinking)
+	}
+	if parts := recorder.request.Contents[1].Parts; len(parts) != 1 || parts[0].Thought {
+		t.Errorf("Expected earlier thoughts not to be sent back, got %+v", parts)
+	}
+
+	if len(responses) != 3 || !responses[0].Content.Parts[0].IsThought() {
+		t.Fatalf("Expected a thought partial first, got %d responses", len(responses))
+	}
+	final := responses[2].Content
+	if len(final.Parts) != 2 || *final.Parts[0].Thought != "The user wants a greeting." || *final.Parts[1].Text != "Hello!" {
+		t.Errorf("Unexpected final content: %+v", final)
+	}
+}
@@ -0,0 +1,28 @@
+package gemini
+
+import (
+	"fmt"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect"
+)
+
+func init() {
+	llmconnect.Register("gemini", []string{"gemini/*", "gemini-*"}, newFromSettings)
+}
+
+// newFromSettings creates a connection for the model registry.
+func newFromSettings(model string, settings llmconnect.ProviderSettings) (core.LLMConnection, error) {
+	config := DefaultGeminiConfig()
+	config.Model = model
+	if settings.BaseURL != "" {
+		config.BaseURL = settings.BaseURL
+	}
+	if apiKey := settings.ResolvedAPIKey(); apiKey != "" {
+		config.APIKey = apiKey
+	}
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("no API key: set GEMINI_API_KEY or GOOGLE_API_KEY")
+	}
+	return NewGeminiConnection(config), nil
+}
@@ -0,0 +1,169 @@
+package gemini
+
+import "fmt"
+
+// Wire types for the Gemini generateContent REST API, see
+// https://ai.google.dev/api/generate-content. Only the fields used by the
+// connection are modelled.
+
+// GenerateContentRequest is the body of POST models/{model}:generateContent
+// and models/{model}:streamGenerateContent.
+type GenerateContentRequest struct {
+	Contents          []Content         `json:"contents"`
+	SystemInstruction *Content          `json:"systemInstruction,omitempty"`
+	Tools             []Tool            `json:"tools,omitempty"`
+	ToolConfig        *ToolConfig       `json:"toolConfig,omitempty"`
+	SafetySettings    []SafetySetting   `json:"safetySettings,omitempty"`
+	GenerationConfig  *GenerationConfig `json:"generationConfig,omitempty"`
+}
+
+// Content is a single turn of the conversation. Role is "user" or "model".
+type Content struct {
+	Role  string `json:"role,omitempty"`
+	Parts []Part `json:"parts"`
+}
+
+// Part is one element of a turn. Exactly one field is set, except that
+// Thought marks a text part as the model's reasoning.
+type Part struct {
+	Text             string            `json:"text,omitempty"`
+	Thought          bool              `json:"thought,omitempty"`
+	FunctionCall     *FunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *FunctionResponse `json:"functionResponse,omitempty"`
+}
+
+// FunctionCall is a function call predicted by the model.
+type FunctionCall struct {
+	ID   string         `json:"id,omitempty"`
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// FunctionResponse carries the result of a function call back to the model.
+type FunctionResponse struct {
+	ID       string         `json:"id,omitempty"`
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+// Tool groups the function declarations available to the model.
+type Tool struct {
+	FunctionDeclarations []FunctionDeclaration `json:"functionDeclarations,omitempty"`
+}
+
+// FunctionDeclaration describes a function with an OpenAPI schema for its parameters.
+type FunctionDeclaration struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// ToolConfig controls how the model uses the declared functions.
+type ToolConfig struct {
+	FunctionCallingConfig *FunctionCallingConfig `json:"functionCallingConfig,omitempty"`
+}
+
+// FunctionCallingConfig selects the function calling mode (AUTO, ANY or NONE).
+type FunctionCallingConfig struct {
+	Mode string `json:"mode,omitempty"`
+}
+
+// SafetySetting sets the blocking threshold for a harm category.
+type SafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+// GenerationConfig holds the sampling parameters.
+type GenerationConfig struct {
+	Temperature      *float32 `json:"temperature,omitempty"`
+	MaxOutputTokens  *int     `json:"maxOutputTokens,omitempty"`
+	TopP             *float32 `json:"topP,omitempty"`
+	TopK             *int     `json:"topK,omitempty"`
+	ResponseMIMEType string   `json:"responseMimeType,omitempty"`
+
+	// ResponseJSONSchema constrains the response to a standard JSON schema.
+	ResponseJSONSchema map[string]any `json:"responseJsonSchema,omitempty"`
+
+	ThinkingConfig *ThinkingConfig `json:"thinkingConfig,omitempty"`
+}
+
+// ThinkingConfig controls the reasoning of thinking models.
+type ThinkingConfig struct {
+	IncludeThoughts bool `json:"includeThoughts,omitempty"`
+	ThinkingBudget  *int `json:"thinkingBudget,omitempty"`
+}
+
+// GenerateContentResponse is the body of a response, or a single server-sent
+// event of a streamed response.
+type GenerateContentResponse struct {
+	Candidates     []Candidate     `json:"candidates,omitempty"`
+	PromptFeedback *PromptFeedback `json:"promptFeedback,omitempty"`
+	UsageMetadata  *UsageMetadata  `json:"usageMetadata,omitempty"`
+	ModelVersion   string          `json:"modelVersion,omitempty"`
+	ResponseID     string          `json:"responseId,omitempty"`
+}
+
+// Candidate is one response alternative.
+type Candidate struct {
+	Content       *Content       `json:"content,omitempty"`
+	FinishReason  string         `json:"finishReason,omitempty"`
+	SafetyRatings []SafetyRating `json:"safetyRatings,omitempty"`
+	Index         int            `json:"index"`
+}
+
+// PromptFeedback reports whether the prompt itself was blocked.
+type PromptFeedback struct {
+	BlockReason   string         `json:"blockReason,omitempty"`
+	SafetyRatings []SafetyRating `json:"safetyRatings,omitempty"`
+}
+
+// SafetyRating is the probability of harm for one category.
+type SafetyRating struct {
+	Category    string `json:"category"`
+	Probability string `json:"probability"`
+	Blocked     bool   `json:"blocked,omitempty"`
+}
+
+// UsageMetadata reports the tokens consumed by a request.
+type UsageMetadata struct {
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"`
+}
+
+// APIError is returned when the server responds with a non-200 status.
+type APIError struct {
+	StatusCode int    `json:"code"`
+	Message    string `json:"message"`
+	Status     string `json:"status"`
+}
+
+func (e *APIError) Error() string {
+	if e.Status != "" {
+		return fmt.Sprintf("gemini API error (status %d, %s): %s", e.StatusCode, e.Status, e.Message)
+	}
+	return fmt.Sprintf("gemini API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed when retried.
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
+
+// errorResponse is the error envelope of the Google APIs.
+type errorResponse struct {
+	Error *APIError `json:"error"`
+}
+
+// blockedFinishReasons are the finish reasons that mean the candidate was
+// withheld by a safety or policy filter.
+var blockedFinishReasons = map[string]bool{
+	"SAFETY":             true,
+	"RECITATION":         true,
+	"BLOCKLIST":          true,
+	"PROHIBITED_CONTENT": true,
+	"SPII":               true,
+	"IMAGE_SAFETY":       true,
+}
@@ -0,0 +1,70 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultEmbeddingModel is the Ollama model used for embeddings when none is configured.
+const DefaultEmbeddingModel = "nomic-embed-text"
+
+// OllamaEmbedder computes text embeddings using the Ollama /api/embed endpoint.
+type OllamaEmbedder struct {
+	conn *OllamaConnection
+}
+
+// EmbedRequest represents a request to the Ollama embed API.
+type EmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// EmbedResponse represents a response from the Ollama embed API.
+type EmbedResponse struct {
+	Model      string      `json:"model"`
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// NewOllamaEmbedder creates a new embedder. Only BaseURL, Model and Timeout are
+// used from the configuration; Model defaults to DefaultEmbeddingModel.
+func NewOllamaEmbedder(config *OllamaConfig) *OllamaEmbedder {
+	if config == nil {
+		config = &OllamaConfig{
+			BaseURL: DefaultOllamaConfig().BaseURL,
+			Timeout: 30 * time.Second,
+		}
+	}
+	if config.Model == "" {
+		config.Model = DefaultEmbeddingModel
+	}
+
+	return &OllamaEmbedder{conn: NewOllamaConnection(config)}
+}
+
+// Embed returns one embedding vector per input text.
+func (e *OllamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	resp, err := e.conn.makeHTTPRequest(ctx, "/api/embed", &EmbedRequest{
+		Model: e.conn.model,
+		Input: texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var embedResp EmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("failed to decode embed response: %w", err)
+	}
+	if len(embedResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embedResp.Embeddings))
+	}
+
+	return embedResp.Embeddings, nil
+}
@@ -0,0 +1,40 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOllamaEmbedder_Embed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		var req EmbedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if req.Model != DefaultEmbeddingModel {
+			t.Errorf("Expected default embedding model, got %s", req.Model)
+		}
+
+		resp := EmbedResponse{Model: req.Model}
+		for i := range req.Input {
+			resp.Embeddings = append(resp.Embeddings, []float32{float32(i), 1})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	embedder := NewOllamaEmbedder(&OllamaConfig{BaseURL: server.URL})
+	vectors, err := embedder.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(vectors) != 2 || vectors[1][0] != 1 {
+		t.Errorf("Unexpected embeddings: %v", vectors)
+	}
+}
@@ -92,6 +92,9 @@ func (c *OllamaConnection) GenerateContent(ctx context.Context, request *core.LL
 }
 
 // GenerateContentStream sends a request and returns a streaming response.
+// Partial responses carry the delta of each chunk; the last response is
+// complete and holds the full text, thinking, tool calls and usage. A stream
+// failure is reported as a final response with Metadata["error"] set.
 func (c *OllamaConnection) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
 	// Convert ADK request to Ollama format with streaming enabled
 	ollamaReq, err := c.convertToOllamaRequest(request, true)
@@ -111,69 +114,58 @@ func (c *OllamaConnection) GenerateContentStream(ctx context.Context, request *c
 		defer close(responseChan)
 		defer resp.Body.Close()
 
+		send := func(response *core.LLMResponse) bool {
+			select {
+			case responseChan <- response:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
 		decoder := json.NewDecoder(resp.Body)
-		var accumulatedContent strings.Builder
+		var accumulatedContent, accumulatedThinking strings.Builder
+		var toolCalls []ToolCall
 
 		for {
 			var chunk ChatResponse
 			if err := decoder.Decode(&chunk); err != nil {
 				if err == io.EOF {
-					break
-				}
-				// Send error through channel
-				errorResp := &core.LLMResponse{
-					Content: &core.Content{
-						Role: "assistant",
-						Parts: []core.Part{
-							{
-								Type: "text",
-								Text: ptr.Ptr(fmt.Sprintf("Error: %v", err)),
-							},
-						},
-					},
-					Partial: ptr.Ptr(false),
-				}
-				select {
-				case responseChan <- errorResp:
-				case <-ctx.Done():
+					err = io.ErrUnexpectedEOF
 				}
+				send(&core.LLMResponse{
+					Partial:  ptr.Ptr(false),
+					Metadata: map[string]any{"error": fmt.Sprintf("failed to read stream: %v", err)},
+				})
 				return
 			}
 
 			// Accumulate content
-			if chunk.Message.Content != "" {
-				accumulatedContent.WriteString(chunk.Message.Content)
+			accumulatedContent.WriteString(chunk.Message.Content)
+			accumulatedThinking.WriteString(chunk.Message.Thinking)
+			toolCalls = append(toolCalls, chunk.Message.ToolCalls...)
+
+			// The final chunk carries the metrics; it is completed with
+			// everything streamed before it
+			if chunk.Done {
+				chunk.Message.Content = accumulatedContent.String()
+				chunk.Message.Thinking = accumulatedThinking.String()
+				chunk.Message.ToolCalls = toolCalls
+				finalResp := c.convertFromOllamaResponse(&chunk)
+				finalResp.Partial = ptr.Ptr(false)
+				send(finalResp)
+				return
 			}
 
-			// Convert and send partial response
+			// Other chunks are sent as deltas
 			partialResp := c.convertFromOllamaResponse(&chunk)
-
-			// Set accumulated content for consistent streaming
-			if accumulatedContent.Len() > 0 {
-				partialResp.Content = &core.Content{
-					Role: "assistant",
-					Parts: []core.Part{
-						{
-							Type: "text",
-							Text: ptr.Ptr(accumulatedContent.String()),
-						},
-					},
-				}
+			if partialResp.Content == nil {
+				continue
 			}
-
-			// Mark as partial unless it's the final chunk
-			partialResp.Partial = ptr.Ptr(!chunk.Done)
-
-			select {
-			case responseChan <- partialResp:
-			case <-ctx.Done():
+			partialResp.Partial = ptr.Ptr(true)
+			if !send(partialResp) {
 				return
 			}
-
-			// Break if this is the final chunk
-			if chunk.Done {
-				break
-			}
 		}
 	}()
 
@@ -209,6 +201,7 @@ func (c *OllamaConnection) convertToOllamaRequest(request *core.LLMRequest, stre
 		var textParts []string
 		var toolCalls []ToolCall
 		var images []ImageData
+		var thoughts []string
 
 		for _, part := range content.Parts {
 			switch part.Type {
@@ -232,6 +225,32 @@ func (c *OllamaConnection) convertToOllamaRequest(request *core.LLMRequest, stre
 					responseText := fmt.Sprintf("Function %s returned: %v", part.FunctionResponse.Name, part.FunctionResponse.Response)
 					textParts = append(textParts, responseText)
 				}
+			case "thought":
+				// Earlier reasoning goes back in the message's thinking field
+				if part.Thought != nil {
+					thoughts = append(thoughts, *part.Thought)
+				}
+			case "inline_data":
+				if part.InlineData != nil {
+					// Images go to multimodal models natively; text files are
+					// inlined and other binary data is only described
+					blob := part.InlineData
+					switch {
+					case strings.HasPrefix(blob.MimeType, "image/"):
+						images = append(images, ImageData(blob.Data))
+					case strings.HasPrefix(blob.MimeType, "text/"):
+						textParts = append(textParts, string(blob.Data))
+					default:
+						textParts = append(textParts, fmt.Sprintf("[Attached %s file %s (%d bytes) not shown]",
+							blob.MimeType, blob.DisplayName, len(blob.Data)))
+					}
+				}
+			case "file_data":
+				if part.FileData != nil {
+					// Ollama cannot fetch files, so the model only sees the reference
+					textParts = append(textParts, fmt.Sprintf("[File %s (%s): %s]",
+						part.FileData.DisplayName, part.FileData.MimeType, part.FileData.FileURI))
+				}
 			}
 		}
 
@@ -240,6 +259,10 @@ func (c *OllamaConnection) convertToOllamaRequest(request *core.LLMRequest, stre
 			message.Content = strings.Join(textParts, "\n")
 		}
 
+		if len(thoughts) > 0 && message.Role == "assistant" {
+			message.Thinking = strings.Join(thoughts, "\n")
+		}
+
 		// Add tool calls if any
 		if len(toolCalls) > 0 {
 			message.ToolCalls = toolCalls
@@ -348,6 +371,19 @@ func (c *OllamaConnection) convertToOllamaRequest(request *core.LLMRequest, stre
 		if request.Config.TopK != nil {
 			chatReq.Options["top_k"] = *request.Config.TopK
 		}
+		if request.Config.ResponseSchema != nil {
+			format, err := json.Marshal(request.Config.ResponseSchema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode response schema: %w", err)
+			}
+			chatReq.Format = format
+		} else if request.Config.ResponseMIMEType == "application/json" {
+			chatReq.Format = json.RawMessage(`"json"`)
+		}
+		if request.Config.Thinking != nil {
+			// Ollama has no thinking budget, only the switch
+			chatReq.Think = ptr.Ptr(true)
+		}
 	}
 
 	return chatReq, nil
@@ -364,12 +400,17 @@ func (c *OllamaConnection) convertFromOllamaResponse(resp *ChatResponse) *core.L
 	}
 
 	// Convert message content
-	if resp.Message.Content != "" || len(resp.Message.ToolCalls) > 0 {
+	if resp.Message.Content != "" || resp.Message.Thinking != "" || len(resp.Message.ToolCalls) > 0 {
 		content := &core.Content{
 			Role:  c.mapRoleFromOllama(resp.Message.Role),
 			Parts: make([]core.Part, 0),
 		}
 
+		// Add thinking content if present, ahead of the answer
+		if resp.Message.Thinking != "" {
+			content.Parts = append(content.Parts, core.NewThoughtPart(resp.Message.Thinking))
+		}
+
 		// Add text content if present
 		if resp.Message.Content != "" {
 			content.Parts = append(content.Parts, core.Part{
@@ -378,14 +419,6 @@ func (c *OllamaConnection) convertFromOllamaResponse(resp *ChatResponse) *core.L
 			})
 		}
 
-		// Add thinking content if present
-		if resp.Message.Thinking != "" {
-			content.Parts = append(content.Parts, core.Part{
-				Type: "text",
-				Text: ptr.Ptr(fmt.Sprintf("[Thinking: %s]", resp.Message.Thinking)),
-			})
-		}
-
 		// Convert tool calls
 		for _, toolCall := range resp.Message.ToolCalls {
 			content.Parts = append(content.Parts, core.Part{
@@ -431,6 +464,15 @@ func (c *OllamaConnection) convertFromOllamaResponse(resp *ChatResponse) *core.L
 	if resp.EvalDuration > 0 {
 		response.Metadata["eval_duration"] = resp.EvalDuration
 	}
+	if resp.Done {
+		response.Usage = &core.Usage{
+			InputTokens:  resp.PromptEvalCount,
+			OutputTokens: resp.EvalCount,
+			TotalTokens:  resp.PromptEvalCount + resp.EvalCount,
+			Latency:      resp.TotalDuration,
+			LLMCalls:     1,
+		}
+	}
 
 	// Set partial flag (inverse of Done)
 	response.Partial = ptr.Ptr(!resp.Done)
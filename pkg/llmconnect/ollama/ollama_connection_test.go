@@ -0,0 +1,82 @@
+package ollama
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+func TestGenerateContentStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunks := []string{
+			`{"model":"llama3.2","message":{"role":"assistant","content":"","thinking":"Look it up."},"done":false}`,
+			`{"model":"llama3.2","message":{"role":"assistant","content":"Let me "},"done":false}`,
+			`{"model":"llama3.2","message":{"role":"assistant","content":"check."},"done":false}`,
+			`{"model":"llama3.2","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"lookup","arguments":{"q":"go"}}}]},"done":false}`,
+			`{"model":"llama3.2","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":12,"eval_count":7}`,
+		}
+		for _, chunk := range chunks {
+			fmt.Fprintln(w, chunk)
+		}
+	}))
+	defer server.Close()
+
+	config := DefaultOllamaConfig()
+	config.BaseURL = server.URL
+	conn := NewOllamaConnection(config)
+
+	request := &core.LLMRequest{Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}}}}
+	stream, err := conn.GenerateContentStream(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	var responses []*core.LLMResponse
+	for response := range stream {
+		responses = append(responses, response)
+	}
+	if len(responses) != 5 {
+		t.Fatalf("Expected 4 partials and a final response, got %d", len(responses))
+	}
+	if !responses[0].Content.Parts[0].IsThought() || *responses[2].Content.Parts[0].Text != "check." {
+		t.Errorf("Expected partials to carry the delta of each chunk")
+	}
+
+	final := responses[4]
+	if *final.Partial {
+		t.Error("Expected the last response to be complete")
+	}
+	parts := final.Content.Parts
+	if len(parts) != 3 || *parts[0].Thought != "Look it up." || *parts[1].Text != "Let me check." ||
+		parts[2].FunctionCall == nil || parts[2].FunctionCall.Args["q"] != "go" {
+		t.Fatalf("Unexpected final content: %+v", final.Content)
+	}
+	if final.Usage == nil || final.Usage.TotalTokens != 19 {
+		t.Errorf("Unexpected final usage: %+v", final.Usage)
+	}
+}
+
+func TestGenerateContentStream_Truncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"model":"llama3.2","message":{"role":"assistant","content":"Hel"},"done":false}`)
+	}))
+	defer server.Close()
+
+	config := DefaultOllamaConfig()
+	config.BaseURL = server.URL
+	stream, err := NewOllamaConnection(config).GenerateContentStream(context.Background(), &core.LLMRequest{})
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+	var last *core.LLMResponse
+	for response := range stream {
+		last = response
+	}
+	if last == nil || *last.Partial || last.Metadata["error"] == nil {
+		t.Errorf("Expected a stream ending early to be reported as an error, got %+v", last)
+	}
+}
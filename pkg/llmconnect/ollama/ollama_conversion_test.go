@@ -1,6 +1,7 @@
 package ollama
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -184,6 +185,11 @@ func TestConvertFromOllamaResponse(t *testing.T) {
 	if totalDuration, ok := llmResp.Metadata["total_duration"].(time.Duration); !ok || totalDuration != time.Second {
 		t.Errorf("Expected total_duration in metadata to be 1s, got %v", llmResp.Metadata["total_duration"])
 	}
+	// Check usage
+	if llmResp.Usage == nil || llmResp.Usage.InputTokens != 10 || llmResp.Usage.OutputTokens != 15 ||
+		llmResp.Usage.TotalTokens != 25 || llmResp.Usage.Latency != time.Second {
+		t.Errorf("Expected usage from metrics, got %+v", llmResp.Usage)
+	}
 }
 
 func TestConvertToOllamaRequestWithFunctionCall(t *testing.T) {
@@ -349,3 +355,84 @@ func TestStreamingFlag(t *testing.T) {
 		t.Errorf("Expected stream to be false, got %v", chatReq.Stream)
 	}
 }
+
+func TestResponseFormat(t *testing.T) {
+	conn := NewOllamaConnection(DefaultOllamaConfig())
+	request := &core.LLMRequest{
+		Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Hello")}}}},
+		Config:   &core.LLMConfig{ResponseMIMEType: "application/json"},
+	}
+
+	chatReq, err := conn.convertToOllamaRequest(request, false)
+	if err != nil {
+		t.Fatalf("Failed to convert request: %v", err)
+	}
+	if string(chatReq.Format) != `"json"` {
+		t.Errorf("Expected JSON mode, got %s", chatReq.Format)
+	}
+
+	// A response schema is passed as the format
+	request.Config.ResponseSchema = map[string]any{"type": "object", "required": []string{"city"}}
+	chatReq, err = conn.convertToOllamaRequest(request, false)
+	if err != nil {
+		t.Fatalf("Failed to convert request: %v", err)
+	}
+	if string(chatReq.Format) != `{"required":["city"],"type":"object"}` {
+		t.Errorf("Expected the schema as format, got %s", chatReq.Format)
+	}
+}
+
+func TestConvertToOllamaRequestWithBlobs(t *testing.T) {
+	conn := NewOllamaConnection(DefaultOllamaConfig())
+	request := &core.LLMRequest{
+		Contents: []core.Content{{Role: "user", Parts: []core.Part{
+			{Type: "text", Text: ptr.Ptr("Describe these")},
+			core.NewInlineDataPart("image/png", []byte("png-bytes")),
+			core.NewInlineDataPart("text/plain", []byte("notes")),
+			core.NewFileDataPart("artifact://report.pdf", "application/pdf"),
+		}}},
+	}
+
+	chatReq, err := conn.convertToOllamaRequest(request, false)
+	if err != nil {
+		t.Fatalf("Failed to convert request: %v", err)
+	}
+	message := chatReq.Messages[0]
+	if len(message.Images) != 1 || string(message.Images[0]) != "png-bytes" {
+		t.Errorf("Expected the image to be attached, got %v", message.Images)
+	}
+	if !strings.Contains(message.Content, "notes") || !strings.Contains(message.Content, "artifact://report.pdf") {
+		t.Errorf("Expected text blobs and file references in the content, got %q", message.Content)
+	}
+}
+
+func TestThinking(t *testing.T) {
+	conn := NewOllamaConnection(DefaultOllamaConfig())
+	request := &core.LLMRequest{
+		Contents: []core.Content{
+			{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Hello")}}},
+			{Role: "model", Parts: []core.Part{core.NewThoughtPart("Greet back."), {Type: "text", Text: ptr.Ptr("Hi!")}}},
+		},
+		Config: &core.LLMConfig{Thinking: &core.ThinkingConfig{}},
+	}
+
+	chatReq, err := conn.convertToOllamaRequest(request, false)
+	if err != nil {
+		t.Fatalf("Failed to convert request: %v", err)
+	}
+	if chatReq.Think == nil || !*chatReq.Think {
+		t.Errorf("Expected thinking to be enabled, got %v", chatReq.Think)
+	}
+	if message := chatReq.Messages[1]; message.Thinking != "Greet back." || message.Content != "Hi!" {
+		t.Errorf("Expected the thought in the thinking field, got %+v", message)
+	}
+
+	response := conn.convertFromOllamaResponse(&ChatResponse{
+		Message: Message{Role: "assistant", Thinking: "Easy question.", Content: "4"},
+		Done:    true,
+	})
+	parts := response.Content.Parts
+	if len(parts) != 2 || !parts[0].IsThought() || *parts[0].Thought != "Easy question." || *parts[1].Text != "4" {
+		t.Errorf("Expected a thought part before the answer, got %+v", parts)
+	}
+}
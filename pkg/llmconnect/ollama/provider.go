@@ -0,0 +1,26 @@
+package ollama
+
+import (
+	"os"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect"
+)
+
+func init() {
+	llmconnect.Register("ollama", []string{"ollama/*"}, newFromSettings)
+}
+
+// newFromSettings creates a connection for the model registry. The base URL
+// falls back to OLLAMA_API_BASE.
+func newFromSettings(model string, settings llmconnect.ProviderSettings) (core.LLMConnection, error) {
+	config := DefaultOllamaConfig()
+	config.Model = model
+	if baseURL := os.Getenv("OLLAMA_API_BASE"); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	if settings.BaseURL != "" {
+		config.BaseURL = settings.BaseURL
+	}
+	return NewOllamaConnection(config), nil
+}
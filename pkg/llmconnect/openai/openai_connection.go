@@ -0,0 +1,540 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+var _ core.LLMConnection = (*OpenAIConnection)(nil)
+
+// OpenAIConnection implements the LLMConnection interface for the OpenAI Chat
+// Completions API and compatible servers.
+type OpenAIConnection struct {
+	baseURL    string
+	httpClient *http.Client
+	model      string
+	config     *OpenAIConfig
+}
+
+// OpenAIConfig contains configuration options for OpenAI connections.
+type OpenAIConfig struct {
+	// BaseURL is the API root, e.g. http://localhost:8000/v1 for vLLM.
+	BaseURL     string        `json:"base_url"`
+	APIKey      string        `json:"-"`
+	Model       string        `json:"model"`
+	Temperature *float32      `json:"temperature,omitempty"`
+	MaxTokens   *int          `json:"max_tokens,omitempty"`
+	TopP        *float32      `json:"top_p,omitempty"`
+	Timeout     time.Duration `json:"timeout"`
+
+	// StreamUsage asks the server to report usage at the end of a stream.
+	StreamUsage bool `json:"stream_usage"`
+}
+
+// DefaultOpenAIConfig returns a default configuration for OpenAI.
+// The API key and base URL are read from OPENAI_API_KEY and OPENAI_BASE_URL.
+func DefaultOpenAIConfig() *OpenAIConfig {
+	baseURL := os.Getenv("OPENAI_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	return &OpenAIConfig{
+		BaseURL:     baseURL,
+		APIKey:      os.Getenv("OPENAI_API_KEY"),
+		Model:       "gpt-4o-mini",
+		Timeout:     60 * time.Second,
+		StreamUsage: true,
+	}
+}
+
+// NewOpenAIConnection creates a new OpenAI connection with the given configuration.
+func NewOpenAIConnection(config *OpenAIConfig) *OpenAIConnection {
+	if config == nil {
+		config = DefaultOpenAIConfig()
+	}
+
+	return &OpenAIConnection{
+		baseURL: strings.TrimSuffix(config.BaseURL, "/"),
+		model:   config.Model,
+		config:  config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// GenerateContent sends a chat completion request and returns the response.
+func (c *OpenAIConnection) GenerateContent(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
+	chatReq, err := c.convertToOpenAIRequest(request, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := c.makeHTTPRequest(ctx, "/chat/completions", chatReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var chatResp ChatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return c.convertFromOpenAIResponse(&chatResp)
+}
+
+// GenerateContentStream sends a streaming chat completion request. Partial
+// responses carry the text delta of each chunk; the last response is complete
+// and holds the full text, the assembled tool calls and the usage. A stream
+// failure is reported as a final response with Metadata["error"] set.
+func (c *OpenAIConnection) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
+	chatReq, err := c.convertToOpenAIRequest(request, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := c.makeHTTPRequest(ctx, "/chat/completions", chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	responseChan := make(chan *core.LLMResponse, 10)
+
+	go func() {
+		defer close(responseChan)
+		defer resp.Body.Close()
+
+		send := func(response *core.LLMResponse) bool {
+			select {
+			case responseChan <- response:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		acc := newStreamAccumulator()
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			data, found := strings.CutPrefix(line, "data:")
+			if !found {
+				// Blank separators, comments and event names
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				break
+			}
+
+			var chunk ChatCompletionChunk
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				send(streamError(fmt.Errorf("failed to decode stream chunk: %w", err)))
+				return
+			}
+
+			if delta := acc.add(&chunk); delta != "" {
+				partial := &core.LLMResponse{
+					Content: &core.Content{
+						Role:  "assistant",
+						Parts: []core.Part{{Type: "text", Text: ptr.Ptr(delta)}},
+					},
+					Partial: ptr.Ptr(true),
+				}
+				if !send(partial) {
+					return
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			send(streamError(fmt.Errorf("failed to read stream: %w", err)))
+			return
+		}
+
+		final, err := c.convertFromOpenAIResponse(acc.response())
+		if err != nil {
+			send(streamError(err))
+			return
+		}
+		send(final)
+	}()
+
+	return responseChan, nil
+}
+
+// Close closes the connection (no-op for HTTP-based connections).
+func (c *OpenAIConnection) Close(ctx context.Context) error {
+	return nil
+}
+
+// convertToOpenAIRequest converts an ADK LLMRequest to the Chat Completions format.
+func (c *OpenAIConnection) convertToOpenAIRequest(request *core.LLMRequest, stream bool) (*ChatCompletionRequest, error) {
+	if request == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	chatReq := &ChatCompletionRequest{
+		Model:  c.model,
+		Stream: stream,
+	}
+	if stream && c.config.StreamUsage {
+		chatReq.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
+
+	// Use the system instruction from the config unless the contents carry one
+	hasSystem := false
+	for _, content := range request.Contents {
+		if content.Role == "system" {
+			hasSystem = true
+			break
+		}
+	}
+	if !hasSystem && request.Config != nil && request.Config.SystemInstruction != nil && *request.Config.SystemInstruction != "" {
+		chatReq.Messages = append(chatReq.Messages, ChatMessage{
+			Role:    "system",
+			Content: request.Config.SystemInstruction,
+		})
+	}
+
+	for _, content := range request.Contents {
+		messages, err := c.convertContent(content)
+		if err != nil {
+			return nil, err
+		}
+		chatReq.Messages = append(chatReq.Messages, messages...)
+	}
+
+	for _, tool := range request.Tools {
+		parameters := tool.Parameters
+		if parameters == nil {
+			parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		chatReq.Tools = append(chatReq.Tools, Tool{
+			Type: "function",
+			Function: FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  parameters,
+			},
+		})
+	}
+
+	// Apply connection-level configuration first
+	chatReq.Temperature = c.config.Temperature
+	chatReq.MaxTokens = c.config.MaxTokens
+	chatReq.TopP = c.config.TopP
+
+	// Apply request-level configuration (overrides connection config)
+	if request.Config != nil {
+		if request.Config.Temperature != nil {
+			chatReq.Temperature = request.Config.Temperature
+		}
+		if request.Config.MaxTokens != nil {
+			chatReq.MaxTokens = request.Config.MaxTokens
+		}
+		if request.Config.TopP != nil {
+			chatReq.TopP = request.Config.TopP
+		}
+		if request.Config.ResponseSchema != nil {
+			chatReq.ResponseFormat = &ResponseFormat{
+				Type:       "json_schema",
+				JSONSchema: &JSONSchemaFormat{Name: "response", Schema: request.Config.ResponseSchema},
+			}
+		} else if request.Config.ResponseMIMEType == "application/json" {
+			chatReq.ResponseFormat = &ResponseFormat{Type: "json_object"}
+		}
+	}
+
+	return chatReq, nil
+}
+
+// convertContent converts one ADK content into chat messages. Function
+// responses become separate "tool" messages as required by the API.
+func (c *OpenAIConnection) convertContent(content core.Content) ([]ChatMessage, error) {
+	message := ChatMessage{Role: c.mapRole(content.Role)}
+	var textParts []string
+	var toolMessages []ChatMessage
+
+	for _, part := range content.Parts {
+		switch part.Type {
+		case "text":
+			if part.Text != nil {
+				textParts = append(textParts, *part.Text)
+			}
+		case "function_call":
+			if part.FunctionCall == nil {
+				continue
+			}
+			arguments, err := json.Marshal(part.FunctionCall.Args)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal arguments of %s: %w", part.FunctionCall.Name, err)
+			}
+			message.ToolCalls = append(message.ToolCalls, ToolCall{
+				ID:   toolCallID(part.FunctionCall.ID, part.FunctionCall.Name),
+				Type: "function",
+				Function: FunctionCall{
+					Name:      part.FunctionCall.Name,
+					Arguments: string(arguments),
+				},
+			})
+		case "function_response":
+			if part.FunctionResponse == nil {
+				continue
+			}
+			result, err := json.Marshal(part.FunctionResponse.Response)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response of %s: %w", part.FunctionResponse.Name, err)
+			}
+			toolMessages = append(toolMessages, ChatMessage{
+				Role:       "tool",
+				Content:    ptr.Ptr(string(result)),
+				ToolCallID: toolCallID(part.FunctionResponse.ID, part.FunctionResponse.Name),
+			})
+		}
+	}
+
+	if len(textParts) > 0 {
+		message.Content = ptr.Ptr(strings.Join(textParts, "\n"))
+	}
+	if message.Content == nil && len(message.ToolCalls) == 0 {
+		return toolMessages, nil
+	}
+	return append([]ChatMessage{message}, toolMessages...), nil
+}
+
+// convertFromOpenAIResponse converts a chat completion to ADK format.
+func (c *OpenAIConnection) convertFromOpenAIResponse(resp *ChatCompletionResponse) (*core.LLMResponse, error) {
+	response := &core.LLMResponse{
+		Partial:  ptr.Ptr(false),
+		Metadata: make(map[string]any),
+	}
+
+	if len(resp.Choices) > 0 {
+		choice := resp.Choices[0]
+		content := &core.Content{
+			Role:  "assistant",
+			Parts: make([]core.Part, 0),
+		}
+
+		if choice.Message.Content != nil && *choice.Message.Content != "" {
+			content.Parts = append(content.Parts, core.Part{
+				Type: "text",
+				Text: ptr.Ptr(*choice.Message.Content),
+			})
+		}
+
+		for i, toolCall := range choice.Message.ToolCalls {
+			args := make(map[string]any)
+			if strings.TrimSpace(toolCall.Function.Arguments) != "" {
+				if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+					return nil, fmt.Errorf("invalid arguments for tool call %s: %w", toolCall.Function.Name, err)
+				}
+			}
+			id := toolCall.ID
+			if id == "" {
+				id = fmt.Sprintf("call_%d", i)
+			}
+			content.Parts = append(content.Parts, core.Part{
+				Type: "function_call",
+				FunctionCall: &core.FunctionCall{
+					ID:   id,
+					Name: toolCall.Function.Name,
+					Args: args,
+				},
+			})
+		}
+
+		if len(content.Parts) > 0 {
+			response.Content = content
+		}
+		if choice.FinishReason != "" {
+			response.Metadata["finish_reason"] = choice.FinishReason
+		}
+	}
+
+	if resp.ID != "" {
+		response.Metadata["id"] = resp.ID
+	}
+	if resp.Model != "" {
+		response.Metadata["model"] = resp.Model
+	}
+	if resp.Usage != nil {
+		response.Metadata["prompt_tokens"] = resp.Usage.PromptTokens
+		response.Metadata["completion_tokens"] = resp.Usage.CompletionTokens
+		response.Metadata["total_tokens"] = resp.Usage.TotalTokens
+		if resp.Usage.PromptTokensDetails != nil {
+			response.Metadata["cached_tokens"] = resp.Usage.PromptTokensDetails.CachedTokens
+		}
+		response.Usage = &core.Usage{
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+			TotalTokens:  resp.Usage.TotalTokens,
+			LLMCalls:     1,
+		}
+		if resp.Usage.PromptTokensDetails != nil {
+			response.Usage.CachedTokens = resp.Usage.PromptTokensDetails.CachedTokens
+		}
+	}
+
+	return response, nil
+}
+
+// makeHTTPRequest posts a JSON payload to the API.
+func (c *OpenAIConnection) makeHTTPRequest(ctx context.Context, endpoint string, payload any) (*http.Response, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		var errResp errorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != nil {
+			errResp.Error.StatusCode = resp.StatusCode
+			return nil, errResp.Error
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	return resp, nil
+}
+
+// mapRole maps ADK roles to chat roles.
+func (c *OpenAIConnection) mapRole(role string) string {
+	switch role {
+	case "agent", "model", "assistant":
+		return "assistant"
+	case "system":
+		return "system"
+	default:
+		return "user"
+	}
+}
+
+// toolCallID returns the ID linking a tool call to its result. The API
+// requires one, so the function name is used when the ADK part has none.
+func toolCallID(id, name string) string {
+	if id != "" {
+		return id
+	}
+	return "call_" + name
+}
+
+// streamError wraps a stream failure in a final response.
+func streamError(err error) *core.LLMResponse {
+	return &core.LLMResponse{
+		Partial:  ptr.Ptr(false),
+		Metadata: map[string]any{"error": err.Error()},
+	}
+}
+
+// streamAccumulator assembles the chunks of a stream into a complete response.
+type streamAccumulator struct {
+	resp      ChatCompletionResponse
+	text      strings.Builder
+	toolCalls map[int]*ToolCall
+}
+
+func newStreamAccumulator() *streamAccumulator {
+	return &streamAccumulator{toolCalls: make(map[int]*ToolCall)}
+}
+
+// add merges a chunk and returns its text delta.
+func (a *streamAccumulator) add(chunk *ChatCompletionChunk) string {
+	if chunk.ID != "" {
+		a.resp.ID = chunk.ID
+	}
+	if chunk.Model != "" {
+		a.resp.Model = chunk.Model
+	}
+	if chunk.Usage != nil {
+		a.resp.Usage = chunk.Usage
+	}
+
+	var delta string
+	for _, choice := range chunk.Choices {
+		if choice.Index != 0 {
+			continue
+		}
+		if choice.FinishReason != nil {
+			a.resp.Choices = []Choice{{FinishReason: *choice.FinishReason}}
+		}
+		if choice.Delta.Content != nil {
+			delta += *choice.Delta.Content
+		}
+		for i, toolCall := range choice.Delta.ToolCalls {
+			index := i
+			if toolCall.Index != nil {
+				index = *toolCall.Index
+			}
+			existing, ok := a.toolCalls[index]
+			if !ok {
+				existing = &ToolCall{Type: "function"}
+				a.toolCalls[index] = existing
+			}
+			if toolCall.ID != "" {
+				existing.ID = toolCall.ID
+			}
+			existing.Function.Name += toolCall.Function.Name
+			existing.Function.Arguments += toolCall.Function.Arguments
+		}
+	}
+
+	a.text.WriteString(delta)
+	return delta
+}
+
+// response returns the accumulated response.
+func (a *streamAccumulator) response() *ChatCompletionResponse {
+	choice := Choice{}
+	if len(a.resp.Choices) > 0 {
+		choice = a.resp.Choices[0]
+	}
+	if a.text.Len() > 0 {
+		choice.Message.Content = ptr.Ptr(a.text.String())
+	}
+
+	indexes := make([]int, 0, len(a.toolCalls))
+	for index := range a.toolCalls {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		choice.Message.ToolCalls = append(choice.Message.ToolCalls, *a.toolCalls[index])
+	}
+
+	resp := a.resp
+	resp.Choices = []Choice{choice}
+	return &resp
+}
@@ -0,0 +1,211 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+func newTestConnection(t *testing.T, handler http.HandlerFunc) *OpenAIConnection {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := DefaultOpenAIConfig()
+	config.BaseURL = server.URL + "/v1/"
+	config.APIKey = "test-key"
+	config.Model = "test-model"
+	return NewOpenAIConnection(config)
+}
+
+func TestGenerateContent_ToolCallsAndUsage(t *testing.T) {
+	var received ChatCompletionRequest
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Unexpected authorization header: %q", r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		fmt.Fprint(w, `{
+			"id": "chatcmpl-1",
+			"model": "test-model",
+			"choices": [{
+				"index": 0,
+				"message": {
+					"role": "assistant",
+					"content": null,
+					"tool_calls": [{"id": "call_abc", "type": "function", "function": {"name": "get_weather", "arguments": "{\"location\":\"Paris\"}"}}]
+				},
+				"finish_reason": "tool_calls"
+			}],
+			"usage": {"prompt_tokens": 12, "completion_tokens": 5, "total_tokens": 17, "prompt_tokens_details": {"cached_tokens": 4}}
+		}`)
+	})
+
+	request := &core.LLMRequest{
+		Contents: []core.Content{
+			{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Weather in Berlin and Paris?")}}},
+			{Role: "agent", Parts: []core.Part{{
+				Type:         "function_call",
+				FunctionCall: &core.FunctionCall{ID: "call_1", Name: "get_weather", Args: map[string]any{"location": "Berlin"}},
+			}}},
+			{Role: "agent", Parts: []core.Part{{
+				Type:             "function_response",
+				FunctionResponse: &core.FunctionResponse{ID: "call_1", Name: "get_weather", Response: map[string]any{"temp": 21}},
+			}}},
+		},
+		Config: &core.LLMConfig{
+			SystemInstruction: ptr.Ptr("Be brief."),
+			Temperature:       ptr.Float32(0.2),
+			ResponseMIMEType:  "application/json",
+		},
+		Tools: []*core.FunctionDeclaration{{Name: "get_weather", Description: "Get weather"}},
+	}
+
+	response, err := conn.GenerateContent(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+
+	// Request conversion
+	if received.Model != "test-model" || received.Stream {
+		t.Errorf("Unexpected model/stream: %s/%v", received.Model, received.Stream)
+	}
+	if len(received.Messages) != 4 {
+		t.Fatalf("Expected 4 messages, got %d: %+v", len(received.Messages), received.Messages)
+	}
+	if received.Messages[0].Role != "system" || *received.Messages[0].Content != "Be brief." {
+		t.Errorf("Expected system instruction first, got %+v", received.Messages[0])
+	}
+	assistant := received.Messages[2]
+	if assistant.Role != "assistant" || len(assistant.ToolCalls) != 1 || assistant.ToolCalls[0].Function.Arguments != `{"location":"Berlin"}` {
+		t.Errorf("Unexpected assistant message: %+v", assistant)
+	}
+	tool := received.Messages[3]
+	if tool.Role != "tool" || tool.ToolCallID != "call_1" || *tool.Content != `{"temp":21}` {
+		t.Errorf("Unexpected tool message: %+v", tool)
+	}
+	if received.ResponseFormat == nil || received.ResponseFormat.Type != "json_object" {
+		t.Errorf("Expected JSON mode, got %+v", received.ResponseFormat)
+	}
+	if received.Temperature == nil || *received.Temperature != 0.2 {
+		t.Errorf("Expected request temperature override, got %v", received.Temperature)
+	}
+	if len(received.Tools) != 1 || received.Tools[0].Function.Parameters["type"] != "object" {
+		t.Errorf("Expected tool with default parameters, got %+v", received.Tools)
+	}
+
+	// Response conversion
+	if response.Partial == nil || *response.Partial {
+		t.Error("Expected a complete response")
+	}
+	call := response.Content.Parts[0].FunctionCall
+	if call == nil || call.ID != "call_abc" || call.Args["location"] != "Paris" {
+		t.Errorf("Unexpected function call: %+v", call)
+	}
+	if response.Metadata["prompt_tokens"] != 12 || response.Metadata["completion_tokens"] != 5 ||
+		response.Metadata["cached_tokens"] != 4 || response.Metadata["finish_reason"] != "tool_calls" {
+		t.Errorf("Unexpected metadata: %v", response.Metadata)
+	}
+	if response.Usage == nil || response.Usage.InputTokens != 12 || response.Usage.OutputTokens != 5 || response.Usage.CachedTokens != 4 {
+		t.Errorf("Unexpected usage: %+v", response.Usage)
+	}
+}
+
+func TestGenerateContentStream(t *testing.T) {
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream || req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+			t.Errorf("Expected streaming request with usage, got %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		chunks := []string{
+			`{"id":"c1","model":"test-model","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`,
+			`{"id":"c1","choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+			`{"id":"c1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_x","type":"function","function":{"name":"lookup","arguments":"{\"q\":"}}]}}]}`,
+			`{"id":"c1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"go\"}"}}]}}]}`,
+			`{"id":"c1","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+			`{"id":"c1","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":7,"total_tokens":10}}`,
+			`[DONE]`,
+		}
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+	})
+
+	request := &core.LLMRequest{Contents: []core.Content{{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}}}}
+	stream, err := conn.GenerateContentStream(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GenerateContentStream failed: %v", err)
+	}
+
+	var responses []*core.LLMResponse
+	for response := range stream {
+		responses = append(responses, response)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("Expected 2 partials and a final response, got %d", len(responses))
+	}
+	if !*responses[0].Partial || *responses[0].Content.Parts[0].Text != "Hel" || *responses[1].Content.Parts[0].Text != "lo" {
+		t.Errorf("Unexpected partial responses")
+	}
+
+	final := responses[2]
+	if *final.Partial {
+		t.Error("Expected the last response to be complete")
+	}
+	if len(final.Content.Parts) != 2 || *final.Content.Parts[0].Text != "Hello" {
+		t.Fatalf("Unexpected final content: %+v", final.Content)
+	}
+	call := final.Content.Parts[1].FunctionCall
+	if call == nil || call.ID != "call_x" || call.Name != "lookup" || call.Args["q"] != "go" {
+		t.Errorf("Unexpected assembled tool call: %+v", call)
+	}
+	if final.Metadata["total_tokens"] != 10 || final.Metadata["finish_reason"] != "tool_calls" {
+		t.Errorf("Unexpected final metadata: %v", final.Metadata)
+	}
+}
+
+func TestGenerateContent_APIError(t *testing.T) {
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error": {"message": "Rate limit reached", "type": "rate_limit_error"}}`)
+	})
+
+	_, err := conn.GenerateContent(context.Background(), &core.LLMRequest{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Message != "Rate limit reached" || !apiErr.Retryable() {
+		t.Errorf("Unexpected API error: %+v", apiErr)
+	}
+}
+
+func TestConvertToOpenAIRequest_ResponseSchema(t *testing.T) {
+	conn := NewOpenAIConnection(DefaultOpenAIConfig())
+	schema := map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}}
+
+	chatReq, err := conn.convertToOpenAIRequest(&core.LLMRequest{
+		Config: &core.LLMConfig{ResponseMIMEType: "application/json", ResponseSchema: schema},
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to convert request: %v", err)
+	}
+	format := chatReq.ResponseFormat
+	if format == nil || format.Type != "json_schema" || format.JSONSchema == nil || format.JSONSchema.Schema["type"] != "object" {
+		t.Errorf("Expected a json_schema response format, got %+v", format)
+	}
+}
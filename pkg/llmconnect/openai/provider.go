@@ -0,0 +1,23 @@
+package openai
+
+import (
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect"
+)
+
+func init() {
+	llmconnect.Register("openai", []string{"openai/*", "gpt-*", "o1*", "o3*", "o4*"}, newFromSettings)
+}
+
+// newFromSettings creates a connection for the model registry.
+func newFromSettings(model string, settings llmconnect.ProviderSettings) (core.LLMConnection, error) {
+	config := DefaultOpenAIConfig()
+	config.Model = model
+	if settings.BaseURL != "" {
+		config.BaseURL = settings.BaseURL
+	}
+	if apiKey := settings.ResolvedAPIKey(); apiKey != "" {
+		config.APIKey = apiKey
+	}
+	return NewOpenAIConnection(config), nil
+}
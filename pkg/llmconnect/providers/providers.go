@@ -0,0 +1,12 @@
+// Package providers registers all built-in LLM providers with the llmconnect
+// model registry. Import it for its side effects:
+//
+//	import _ "github.com/agent-protocol/adk-golang/pkg/llmconnect/providers"
+package providers
+
+import (
+	_ "github.com/agent-protocol/adk-golang/pkg/llmconnect/anthropic"
+	_ "github.com/agent-protocol/adk-golang/pkg/llmconnect/gemini"
+	_ "github.com/agent-protocol/adk-golang/pkg/llmconnect/ollama"
+	_ "github.com/agent-protocol/adk-golang/pkg/llmconnect/openai"
+)
@@ -0,0 +1,234 @@
+// Package llmconnect resolves model names to LLM connections.
+//
+// Provider packages register a factory together with the model-name patterns
+// they serve, usually from an init function:
+//
+//	func init() {
+//		llmconnect.Register("ollama", []string{"ollama/*"}, newFromSettings)
+//	}
+//
+// A pattern is either an exact model name or a prefix ending in "*". When
+// several patterns match, the longest one wins. For patterns of the form
+// "<prefix>/*" the prefix is stripped before the model name is handed to the
+// factory, so "ollama/llama3.2" creates an Ollama connection for "llama3.2".
+//
+// Importing github.com/agent-protocol/adk-golang/pkg/llmconnect/providers
+// registers all built-in providers.
+package llmconnect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// ConfigFileEnv names the environment variable holding the path of the
+// provider configuration file loaded by the default registry.
+const ConfigFileEnv = "ADK_LLM_CONFIG"
+
+// ProviderSettings holds the endpoint and credentials of a provider. Empty
+// fields leave the provider defaults, which read their own environment
+// variables (e.g. OPENAI_API_KEY), in place.
+type ProviderSettings struct {
+	BaseURL string `json:"base_url,omitempty"`
+	APIKey  string `json:"api_key,omitempty"`
+
+	// APIKeyEnv names an environment variable holding the API key, which
+	// keeps secrets out of the configuration file.
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+}
+
+// ResolvedAPIKey returns the API key, reading APIKeyEnv when APIKey is empty.
+func (s ProviderSettings) ResolvedAPIKey() string {
+	if s.APIKey == "" && s.APIKeyEnv != "" {
+		return os.Getenv(s.APIKeyEnv)
+	}
+	return s.APIKey
+}
+
+// Config is the content of a provider configuration file.
+type Config struct {
+	Providers map[string]ProviderSettings `json:"providers"`
+}
+
+// Factory creates a connection for a model using the provider settings.
+type Factory func(model string, settings ProviderSettings) (core.LLMConnection, error)
+
+// registration binds a model-name pattern to a provider.
+type registration struct {
+	pattern  string
+	provider string
+}
+
+// Registry maps model names to provider factories.
+type Registry struct {
+	mu         sync.RWMutex
+	factories  map[string]Factory
+	patterns   []registration
+	settings   map[string]ProviderSettings
+	configPath string
+	configErr  error
+	configOnce sync.Once
+}
+
+// NewRegistry creates an empty registry. Settings are taken from the
+// configuration file at configPath when it is not empty.
+func NewRegistry(configPath string) *Registry {
+	return &Registry{
+		factories:  make(map[string]Factory),
+		settings:   make(map[string]ProviderSettings),
+		configPath: configPath,
+	}
+}
+
+// Register adds a provider serving the given model-name patterns. It panics
+// if the provider is registered twice or a pattern is already taken.
+func (r *Registry) Register(provider string, patterns []string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if factory == nil {
+		panic("llmconnect: Register factory is nil for provider " + provider)
+	}
+	if _, exists := r.factories[provider]; exists {
+		panic("llmconnect: Register called twice for provider " + provider)
+	}
+	for _, pattern := range patterns {
+		for _, reg := range r.patterns {
+			if reg.pattern == pattern {
+				panic(fmt.Sprintf("llmconnect: pattern %q of provider %s is already registered by %s", pattern, provider, reg.provider))
+			}
+		}
+	}
+
+	r.factories[provider] = factory
+	for _, pattern := range patterns {
+		r.patterns = append(r.patterns, registration{pattern: pattern, provider: provider})
+	}
+	// Longest pattern first so the most specific match wins
+	sort.SliceStable(r.patterns, func(i, j int) bool {
+		return len(r.patterns[i].pattern) > len(r.patterns[j].pattern)
+	})
+}
+
+// SetProviderSettings overrides the settings of a provider.
+func (r *Registry) SetProviderSettings(provider string, settings ProviderSettings) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.settings[provider] = settings
+}
+
+// LoadConfig reads provider settings from a JSON configuration file. Settings
+// already present for a provider are replaced.
+func (r *Registry) LoadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read LLM config %s: %w", path, err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse LLM config %s: %w", path, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for provider, settings := range config.Providers {
+		r.settings[provider] = settings
+	}
+	return nil
+}
+
+// Lookup returns the provider serving a model and the model name passed to
+// its factory.
+func (r *Registry) Lookup(model string) (provider, providerModel string, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, reg := range r.patterns {
+		prefix, wildcard := strings.CutSuffix(reg.pattern, "*")
+		if !wildcard {
+			if model == reg.pattern {
+				return reg.provider, model, true
+			}
+			continue
+		}
+		if !strings.HasPrefix(model, prefix) || len(model) == len(prefix) {
+			continue
+		}
+		if strings.HasSuffix(prefix, "/") {
+			return reg.provider, strings.TrimPrefix(model, prefix), true
+		}
+		return reg.provider, model, true
+	}
+	return "", "", false
+}
+
+// Resolve creates a connection for a model name.
+func (r *Registry) Resolve(model string) (core.LLMConnection, error) {
+	if model == "" {
+		return nil, fmt.Errorf("model name cannot be empty")
+	}
+
+	r.configOnce.Do(func() {
+		if r.configPath != "" {
+			r.configErr = r.LoadConfig(r.configPath)
+		}
+	})
+	if r.configErr != nil {
+		return nil, r.configErr
+	}
+
+	provider, providerModel, ok := r.Lookup(model)
+	if !ok {
+		return nil, fmt.Errorf("no LLM provider registered for model %q (registered patterns: %s)", model, strings.Join(r.Patterns(), ", "))
+	}
+
+	r.mu.RLock()
+	factory := r.factories[provider]
+	settings := r.settings[provider]
+	r.mu.RUnlock()
+
+	conn, err := factory(providerModel, settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s connection for model %q: %w", provider, model, err)
+	}
+	return conn, nil
+}
+
+// Patterns returns the registered model-name patterns.
+func (r *Registry) Patterns() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	patterns := make([]string, 0, len(r.patterns))
+	for _, reg := range r.patterns {
+		patterns = append(patterns, reg.pattern)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// defaultRegistry is used by the package-level functions. Its configuration
+// file is taken from ADK_LLM_CONFIG.
+var defaultRegistry = NewRegistry(os.Getenv(ConfigFileEnv))
+
+// DefaultRegistry returns the registry used by the package-level functions.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a provider to the default registry.
+func Register(provider string, patterns []string, factory Factory) {
+	defaultRegistry.Register(provider, patterns, factory)
+}
+
+// Resolve creates a connection for a model name using the default registry.
+func Resolve(model string) (core.LLMConnection, error) {
+	return defaultRegistry.Resolve(model)
+}
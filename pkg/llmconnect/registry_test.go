@@ -0,0 +1,110 @@
+package llmconnect
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// stubConnection records how it was created.
+type stubConnection struct {
+	model    string
+	settings ProviderSettings
+}
+
+func (s *stubConnection) GenerateContent(ctx context.Context, request *core.LLMRequest) (*core.LLMResponse, error) {
+	return &core.LLMResponse{}, nil
+}
+
+func (s *stubConnection) GenerateContentStream(ctx context.Context, request *core.LLMRequest) (<-chan *core.LLMResponse, error) {
+	return nil, nil
+}
+
+func (s *stubConnection) Close(ctx context.Context) error {
+	return nil
+}
+
+func stubFactory(model string, settings ProviderSettings) (core.LLMConnection, error) {
+	return &stubConnection{model: model, settings: settings}, nil
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	registry := NewRegistry("")
+	registry.Register("ollama", []string{"ollama/*"}, stubFactory)
+	registry.Register("gemini", []string{"gemini-*"}, stubFactory)
+	registry.Register("special", []string{"gemini-1.5-flash-special"}, stubFactory)
+
+	tests := []struct {
+		model    string
+		provider string
+		passed   string
+	}{
+		{"ollama/llama3.2", "ollama", "llama3.2"},
+		{"ollama/library/qwen:7b", "ollama", "library/qwen:7b"},
+		{"gemini-1.5-pro", "gemini", "gemini-1.5-pro"},
+		{"gemini-1.5-flash-special", "special", "gemini-1.5-flash-special"},
+	}
+	for _, tt := range tests {
+		provider, passed, ok := registry.Lookup(tt.model)
+		if !ok || provider != tt.provider || passed != tt.passed {
+			t.Errorf("Lookup(%q) = %q, %q, %v; want %q, %q", tt.model, provider, passed, ok, tt.provider, tt.passed)
+		}
+	}
+
+	conn, err := registry.Resolve("ollama/llama3.2")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if stub := conn.(*stubConnection); stub.model != "llama3.2" {
+		t.Errorf("Expected the prefix to be stripped, got %q", stub.model)
+	}
+
+	for _, model := range []string{"ollama/", "gpt-4o", ""} {
+		if _, err := registry.Resolve(model); err == nil {
+			t.Errorf("Expected an error resolving %q", model)
+		}
+	}
+}
+
+func TestRegistry_RegisterDuplicatePanics(t *testing.T) {
+	registry := NewRegistry("")
+	registry.Register("a", []string{"a/*"}, stubFactory)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a duplicate pattern")
+		}
+	}()
+	registry.Register("b", []string{"a/*"}, stubFactory)
+}
+
+func TestRegistry_ConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "llm.json")
+	config := `{"providers": {"openai": {"base_url": "http://vllm:8000/v1", "api_key_env": "TEST_REGISTRY_KEY"}}}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_REGISTRY_KEY", "secret")
+
+	registry := NewRegistry(path)
+	registry.Register("openai", []string{"openai/*"}, stubFactory)
+
+	conn, err := registry.Resolve("openai/qwen")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	stub := conn.(*stubConnection)
+	if stub.settings.BaseURL != "http://vllm:8000/v1" || stub.settings.ResolvedAPIKey() != "secret" {
+		t.Errorf("Unexpected settings: %+v", stub.settings)
+	}
+
+	broken := NewRegistry(filepath.Join(t.TempDir(), "missing.json"))
+	broken.Register("openai", []string{"openai/*"}, stubFactory)
+	if _, err := broken.Resolve("openai/qwen"); err == nil || !strings.Contains(err.Error(), "failed to read LLM config") {
+		t.Errorf("Expected config read error, got %v", err)
+	}
+}
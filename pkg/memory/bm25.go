@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// stopWords are excluded from the index; they carry no signal for recall.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"but": true, "by": true, "do": true, "for": true, "from": true, "has": true, "have": true,
+	"i": true, "if": true, "in": true, "is": true, "it": true, "me": true, "my": true,
+	"of": true, "on": true, "or": true, "so": true, "that": true, "the": true, "this": true,
+	"to": true, "was": true, "we": true, "what": true, "with": true, "you": true, "your": true,
+}
+
+// tokenize lowercases text and splits it into index terms.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !stopWords[field] {
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
+
+// bm25Scores scores every document against the query terms using Okapi BM25.
+// Documents are given as token lists; the result is parallel to docs.
+func bm25Scores(query []string, docs [][]string, k1, b float64) []float64 {
+	scores := make([]float64, len(docs))
+	if len(query) == 0 || len(docs) == 0 {
+		return scores
+	}
+
+	totalLength := 0
+	termFreqs := make([]map[string]int, len(docs))
+	docFreq := make(map[string]int)
+	for i, doc := range docs {
+		totalLength += len(doc)
+		termFreqs[i] = make(map[string]int)
+		for _, token := range doc {
+			termFreqs[i][token]++
+		}
+		for token := range termFreqs[i] {
+			docFreq[token]++
+		}
+	}
+	avgLength := float64(totalLength) / float64(len(docs))
+	if avgLength == 0 {
+		return scores
+	}
+
+	n := float64(len(docs))
+	seen := make(map[string]bool)
+	for _, term := range query {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		df := float64(docFreq[term])
+		if df == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for i, doc := range docs {
+			tf := float64(termFreqs[i][term])
+			if tf == 0 {
+				continue
+			}
+			norm := k1 * (1 - b + b*float64(len(doc))/avgLength)
+			scores[i] += idf * tf * (k1 + 1) / (tf + norm)
+		}
+	}
+
+	return scores
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, or 0 if either is empty.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
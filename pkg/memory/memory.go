@@ -0,0 +1,346 @@
+// Package memory provides long-term memory implementations for agents.
+// Session events are indexed when a session is added to memory, and later
+// recalled for the same app and user by keyword relevance, optionally reranked
+// by embedding similarity.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// Embedder turns texts into embedding vectors. Implementations must return one
+// vector per input text, in order.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// MemoryConfig contains configuration for LocalMemoryService.
+type MemoryConfig struct {
+	// FilePath is where memory is persisted. Memory is kept in-process only when empty.
+	FilePath string `json:"file_path"`
+
+	// Embedder enables embedding rerank of the BM25 candidates when set.
+	Embedder Embedder `json:"-"`
+
+	// RerankCandidates is the number of top BM25 matches passed to the reranker.
+	RerankCandidates int `json:"rerank_candidates"`
+
+	// EmbeddingWeight is the share of the final score taken from embedding similarity.
+	EmbeddingWeight float64 `json:"embedding_weight"`
+
+	// DefaultLimit is used when a retrieval request does not set a limit.
+	DefaultLimit int `json:"default_limit"`
+
+	// BM25 parameters.
+	K1 float64 `json:"k1"`
+	B  float64 `json:"b"`
+}
+
+// DefaultMemoryConfig returns a default memory configuration.
+func DefaultMemoryConfig() *MemoryConfig {
+	return &MemoryConfig{
+		RerankCandidates: 20,
+		EmbeddingWeight:  0.7,
+		DefaultLimit:     5,
+		K1:               1.2,
+		B:                0.75,
+	}
+}
+
+// memoryEntry is a single indexed event.
+type memoryEntry struct {
+	AppName   string      `json:"app_name"`
+	UserID    string      `json:"user_id"`
+	SessionID string      `json:"session_id"`
+	Event     *core.Event `json:"event"`
+	Embedding []float32   `json:"embedding,omitempty"`
+
+	tokens []string
+}
+
+// memoryFile is the on-disk format of a persisted memory.
+type memoryFile struct {
+	Entries []*memoryEntry `json:"entries"`
+}
+
+// LocalMemoryService is an in-process MemoryService using BM25 keyword scoring.
+type LocalMemoryService struct {
+	config *MemoryConfig
+	mu     sync.RWMutex
+	// entries is keyed by scopeKey(app, user), then by session ID.
+	entries map[string]map[string][]*memoryEntry
+}
+
+var _ core.MemoryService = (*LocalMemoryService)(nil)
+
+// NewLocalMemoryService creates a new memory service, loading any memory
+// previously persisted to config.FilePath.
+func NewLocalMemoryService(config *MemoryConfig) (*LocalMemoryService, error) {
+	if config == nil {
+		config = DefaultMemoryConfig()
+	}
+
+	s := &LocalMemoryService{
+		config:  config,
+		entries: make(map[string]map[string][]*memoryEntry),
+	}
+
+	if config.FilePath != "" {
+		if err := os.MkdirAll(filepath.Dir(config.FilePath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create memory directory: %w", err)
+		}
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// NewInMemoryMemoryService creates a memory service that is not persisted.
+func NewInMemoryMemoryService() *LocalMemoryService {
+	s, _ := NewLocalMemoryService(nil)
+	return s
+}
+
+func scopeKey(appName, userID string) string {
+	return appName + "/" + userID
+}
+
+// AddSessionToMemory indexes the text events of a session. Adding the same
+// session again replaces its previously indexed events.
+func (s *LocalMemoryService) AddSessionToMemory(ctx context.Context, session *core.Session) error {
+	if session == nil {
+		return fmt.Errorf("session is required")
+	}
+
+	key := scopeKey(session.AppName, session.UserID)
+
+	// Reuse embeddings of events that were already indexed
+	s.mu.RLock()
+	known := make(map[string][]float32)
+	for _, entry := range s.entries[key][session.ID] {
+		if entry.Embedding != nil {
+			known[entry.Event.ID] = entry.Embedding
+		}
+	}
+	s.mu.RUnlock()
+
+	var entries []*memoryEntry
+	var pending []*memoryEntry
+	for _, event := range session.Events {
+		if event.Content == nil || (event.Partial != nil && *event.Partial) {
+			continue
+		}
+		text := eventText(event)
+		if text == "" {
+			continue
+		}
+
+		entry := &memoryEntry{
+			AppName:   session.AppName,
+			UserID:    session.UserID,
+			SessionID: session.ID,
+			Event:     event,
+			Embedding: known[event.ID],
+			tokens:    tokenize(text),
+		}
+		entries = append(entries, entry)
+		if s.config.Embedder != nil && entry.Embedding == nil {
+			pending = append(pending, entry)
+		}
+	}
+
+	if len(pending) > 0 {
+		texts := make([]string, len(pending))
+		for i, entry := range pending {
+			texts[i] = eventText(entry.Event)
+		}
+		embeddings, err := s.config.Embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed session events: %w", err)
+		}
+		if len(embeddings) != len(pending) {
+			return fmt.Errorf("embedder returned %d vectors for %d texts", len(embeddings), len(pending))
+		}
+		for i, entry := range pending {
+			entry.Embedding = embeddings[i]
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries[key] == nil {
+		s.entries[key] = make(map[string][]*memoryEntry)
+	}
+	s.entries[key][session.ID] = entries
+
+	return s.save()
+}
+
+// RetrieveRelevantEvents returns the events of the user's past sessions that
+// best match the query, most relevant first.
+func (s *LocalMemoryService) RetrieveRelevantEvents(ctx context.Context, req *core.RetrieveMemoryRequest) ([]*core.Event, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = s.config.DefaultLimit
+	}
+
+	query := tokenize(req.Query)
+	if len(query) == 0 {
+		return []*core.Event{}, nil
+	}
+
+	s.mu.RLock()
+	var candidates []*memoryEntry
+	for _, sessionEntries := range s.entries[scopeKey(req.AppName, req.UserID)] {
+		candidates = append(candidates, sessionEntries...)
+	}
+	s.mu.RUnlock()
+
+	docs := make([][]string, len(candidates))
+	for i, entry := range candidates {
+		docs[i] = entry.tokens
+	}
+	scores := bm25Scores(query, docs, s.config.K1, s.config.B)
+
+	type scored struct {
+		entry *memoryEntry
+		score float64
+	}
+	var matches []scored
+	for i, score := range scores {
+		if score > 0 {
+			matches = append(matches, scored{candidates[i], score})
+		}
+	}
+	sortScored := func() {
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].score != matches[j].score {
+				return matches[i].score > matches[j].score
+			}
+			return matches[i].entry.Event.Timestamp.After(matches[j].entry.Event.Timestamp)
+		})
+	}
+	sortScored()
+
+	if s.config.Embedder != nil && len(matches) > 1 {
+		if n := s.config.RerankCandidates; n > 0 && len(matches) > n {
+			matches = matches[:n]
+		}
+
+		embeddings, err := s.config.Embedder.Embed(ctx, []string{req.Query})
+		if err == nil && len(embeddings) != 1 {
+			err = fmt.Errorf("embedder returned %d vectors for 1 text", len(embeddings))
+		}
+		if err != nil {
+			// Keyword ranking still works without the reranker
+			log.Printf("Failed to embed memory query, using keyword ranking: %v", err)
+		} else {
+			maxScore := matches[0].score
+			for i := range matches {
+				similarity := cosineSimilarity(embeddings[0], matches[i].entry.Embedding)
+				matches[i].score = (1-s.config.EmbeddingWeight)*matches[i].score/maxScore +
+					s.config.EmbeddingWeight*similarity
+			}
+			sortScored()
+		}
+	}
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	events := make([]*core.Event, len(matches))
+	for i, match := range matches {
+		events[i] = match.entry.Event
+	}
+	return events, nil
+}
+
+// load reads persisted memory from disk.
+func (s *LocalMemoryService) load() error {
+	data, err := os.ReadFile(s.config.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read memory file: %w", err)
+	}
+
+	var file memoryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to unmarshal memory file: %w", err)
+	}
+
+	for _, entry := range file.Entries {
+		if entry.Event == nil {
+			continue
+		}
+		entry.tokens = tokenize(eventText(entry.Event))
+
+		key := scopeKey(entry.AppName, entry.UserID)
+		if s.entries[key] == nil {
+			s.entries[key] = make(map[string][]*memoryEntry)
+		}
+		s.entries[key][entry.SessionID] = append(s.entries[key][entry.SessionID], entry)
+	}
+
+	return nil
+}
+
+// save writes memory to disk. Callers must hold the write lock.
+func (s *LocalMemoryService) save() error {
+	if s.config.FilePath == "" {
+		return nil
+	}
+
+	file := memoryFile{Entries: make([]*memoryEntry, 0)}
+	for _, sessions := range s.entries {
+		for _, entries := range sessions {
+			file.Entries = append(file.Entries, entries...)
+		}
+	}
+
+	data, err := json.Marshal(&file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal memory: %w", err)
+	}
+
+	tempFile := s.config.FilePath + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	if err := os.Rename(tempFile, s.config.FilePath); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename memory file: %w", err)
+	}
+
+	return nil
+}
+
+// eventText returns the concatenated text parts of an event.
+func eventText(event *core.Event) string {
+	if event.Content == nil {
+		return ""
+	}
+
+	var texts []string
+	for _, part := range event.Content.Parts {
+		if part.Text != nil && *part.Text != "" {
+			texts = append(texts, *part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
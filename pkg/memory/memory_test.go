@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+func newTestSession(id, userID string, texts ...string) *core.Session {
+	session := &core.Session{ID: id, AppName: "app", UserID: userID}
+	for i, text := range texts {
+		author := "user"
+		if i%2 == 1 {
+			author = "assistant"
+		}
+		event := core.NewEvent("inv-"+id, author)
+		event.Content = &core.Content{Role: author, Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}}
+		session.Events = append(session.Events, event)
+	}
+	return session
+}
+
+func eventTexts(events []*core.Event) []string {
+	texts := make([]string, len(events))
+	for i, event := range events {
+		texts[i] = eventText(event)
+	}
+	return texts
+}
+
+func TestLocalMemoryService_Retrieve(t *testing.T) {
+	ctx := context.Background()
+	service := NewInMemoryMemoryService()
+
+	sessions := []*core.Session{
+		newTestSession("s1", "alice", "I prefer window seats on long flights", "Noted, window seat it is."),
+		newTestSession("s2", "alice", "My favourite cuisine is Thai food", "Great, I will suggest Thai restaurants."),
+		newTestSession("s3", "bob", "I prefer aisle seats on flights", "Noted."),
+	}
+	for _, session := range sessions {
+		if err := service.AddSessionToMemory(ctx, session); err != nil {
+			t.Fatalf("AddSessionToMemory failed: %v", err)
+		}
+	}
+
+	events, err := service.RetrieveRelevantEvents(ctx, &core.RetrieveMemoryRequest{
+		AppName: "app", UserID: "alice", Query: "Which seats does the user prefer on flights?", Limit: 1,
+	})
+	if err != nil {
+		t.Fatalf("RetrieveRelevantEvents failed: %v", err)
+	}
+	if len(events) != 1 || !strings.Contains(eventText(events[0]), "window seats") {
+		t.Errorf("Expected alice's seat preference, got %v", eventTexts(events))
+	}
+
+	events, err = service.RetrieveRelevantEvents(ctx, &core.RetrieveMemoryRequest{
+		AppName: "app", UserID: "alice", Query: "quantum chromodynamics",
+	})
+	if err != nil || len(events) != 0 {
+		t.Errorf("Expected no matches for unrelated query, got %v (err=%v)", eventTexts(events), err)
+	}
+
+	// Re-adding a session replaces its events instead of duplicating them
+	sessions[0].Events = append(sessions[0].Events, newTestSession("s1", "alice", "Also window seats on trains").Events...)
+	if err := service.AddSessionToMemory(ctx, sessions[0]); err != nil {
+		t.Fatalf("AddSessionToMemory failed: %v", err)
+	}
+	events, err = service.RetrieveRelevantEvents(ctx, &core.RetrieveMemoryRequest{
+		AppName: "app", UserID: "alice", Query: "window seats", Limit: 10,
+	})
+	if err != nil || len(events) != 3 {
+		t.Errorf("Expected 3 window seat events after re-adding, got %v (err=%v)", eventTexts(events), err)
+	}
+}
+
+func TestLocalMemoryService_Persistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "memory", "memory.json")
+
+	service, err := NewMemoryServiceFromURI("file://" + path)
+	if err != nil {
+		t.Fatalf("Failed to create memory service: %v", err)
+	}
+	if err := service.AddSessionToMemory(ctx, newTestSession("s1", "alice", "My dog is called Biscuit")); err != nil {
+		t.Fatalf("AddSessionToMemory failed: %v", err)
+	}
+
+	reopened, err := NewMemoryServiceFromURI("file://" + path)
+	if err != nil {
+		t.Fatalf("Failed to reopen memory service: %v", err)
+	}
+	events, err := reopened.RetrieveRelevantEvents(ctx, &core.RetrieveMemoryRequest{
+		AppName: "app", UserID: "alice", Query: "what is my dog called",
+	})
+	if err != nil || len(events) != 1 || eventText(events[0]) != "My dog is called Biscuit" {
+		t.Errorf("Expected persisted memory to be recalled, got %v (err=%v)", eventTexts(events), err)
+	}
+}
+
+// keywordEmbedder embeds texts as a one-hot vector over fixed keywords.
+type keywordEmbedder struct {
+	keywords []string
+	calls    int
+}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(e.keywords))
+		for j, keyword := range e.keywords {
+			if strings.Contains(strings.ToLower(text), keyword) {
+				vectors[i][j] = 1
+			}
+		}
+	}
+	return vectors, nil
+}
+
+func TestLocalMemoryService_EmbeddingRerank(t *testing.T) {
+	ctx := context.Background()
+	embedder := &keywordEmbedder{keywords: []string{"vegetarian"}}
+	config := DefaultMemoryConfig()
+	config.Embedder = embedder
+	config.EmbeddingWeight = 0.9
+
+	service, err := NewLocalMemoryService(config)
+	if err != nil {
+		t.Fatalf("Failed to create memory service: %v", err)
+	}
+	session := newTestSession("s1", "alice",
+		"Dinner dinner dinner plans for dinner tonight",
+		"Remember I am vegetarian when planning dinner",
+	)
+	if err := service.AddSessionToMemory(ctx, session); err != nil {
+		t.Fatalf("AddSessionToMemory failed: %v", err)
+	}
+
+	events, err := service.RetrieveRelevantEvents(ctx, &core.RetrieveMemoryRequest{
+		AppName: "app", UserID: "alice", Query: "vegetarian dinner", Limit: 2,
+	})
+	if err != nil || len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %v (err=%v)", eventTexts(events), err)
+	}
+	if !strings.Contains(eventText(events[0]), "vegetarian") {
+		t.Errorf("Expected reranked vegetarian event first, got %v", eventTexts(events))
+	}
+
+	// Existing embeddings are reused when the session is added again
+	calls := embedder.calls
+	if err := service.AddSessionToMemory(ctx, session); err != nil {
+		t.Fatalf("AddSessionToMemory failed: %v", err)
+	}
+	if embedder.calls != calls {
+		t.Errorf("Expected embeddings to be reused, embedder called %d more times", embedder.calls-calls)
+	}
+}
+
+func TestBM25Scores(t *testing.T) {
+	docs := [][]string{
+		tokenize("the cat sat on the mat"),
+		tokenize("dogs and cats living together"),
+		tokenize("cat cat cat"),
+	}
+	scores := bm25Scores(tokenize("cat"), docs, 1.2, 0.75)
+
+	if scores[1] != 0 {
+		t.Errorf("Expected no score for document without the term, got %f", scores[1])
+	}
+	if scores[2] <= scores[0] {
+		t.Errorf("Expected higher term frequency to score higher: %v", scores)
+	}
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect/ollama"
+)
+
+// NewMemoryServiceFromURI creates a memory service from a --memory-service-uri value.
+// Supported forms are:
+//
+//	""                                  in-memory storage
+//	memory://                           in-memory storage
+//	file:///path/to/memory.json         local file storage
+//
+// Adding ?embed_model=<model> enables embedding rerank through Ollama, and
+// &ollama_url=<url> overrides the Ollama server address.
+func NewMemoryServiceFromURI(uri string) (*LocalMemoryService, error) {
+	config := DefaultMemoryConfig()
+	if uri == "" {
+		return NewLocalMemoryService(config)
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory service URI: %w", err)
+	}
+
+	switch parsed.Scheme {
+	case "memory":
+	case "file":
+		// file://relative/path puts the first segment in Host
+		config.FilePath = parsed.Host + parsed.Path
+		if config.FilePath == "" {
+			return nil, fmt.Errorf("file URI requires a path: %s", uri)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported memory service URI scheme: %s", parsed.Scheme)
+	}
+
+	query := parsed.Query()
+	if model := query.Get("embed_model"); model != "" {
+		ollamaConfig := ollama.DefaultOllamaConfig()
+		ollamaConfig.Model = model
+		if baseURL := query.Get("ollama_url"); baseURL != "" {
+			ollamaConfig.BaseURL = baseURL
+		}
+		config.Embedder = ollama.NewOllamaEmbedder(ollamaConfig)
+	}
+
+	return NewLocalMemoryService(config)
+}
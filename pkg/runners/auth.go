@@ -0,0 +1,247 @@
+package runners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/agent-protocol/adk-golang/pkg/auth"
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// pendingAuthType is the credential type under which the runner keeps the
+// auth requests awaiting a response from the client.
+const pendingAuthType = "pending_auth_request"
+
+// pendingAuthID is the credential ID of the auth request sent to the client in
+// an adk_request_credential call.
+func pendingAuthID(callID string) string {
+	return pendingAuthType + ":" + callID
+}
+
+// redactAuthConfigs returns a copy of the auth configs requested by tools
+// without their secrets.
+func redactAuthConfigs(requested map[string]core.AuthConfig) map[string]core.AuthConfig {
+	redacted := make(map[string]core.AuthConfig, len(requested))
+	for credentialID, authConfig := range requested {
+		redacted[credentialID] = auth.RedactAuthConfig(authConfig)
+	}
+	return redacted
+}
+
+// handleAuthRequests acts on the credentials requested by the tools of an event.
+// Client-credentials OAuth2 is resolved directly; every other request is turned
+// into an adk_request_credential call on the returned pause event. The requested
+// configs, secrets included, are kept in the credential service until the
+// client responds. It returns nil if the invocation does not need to pause.
+func (r *RunnerImpl) handleAuthRequests(ctx context.Context, invocationCtx *core.InvocationContext,
+	author string, requested map[string]core.AuthConfig) (*core.Event, error) {
+
+	session := invocationCtx.Session
+	credentialIDs := make([]string, 0, len(requested))
+	for credentialID := range requested {
+		credentialIDs = append(credentialIDs, credentialID)
+	}
+	sort.Strings(credentialIDs)
+
+	pauseEvent := core.NewEvent(invocationCtx.InvocationID, author)
+	pending := make(map[string]core.AuthConfig)
+	var parts []core.Part
+	var callIDs []string
+	for _, credentialID := range credentialIDs {
+		authConfig := requested[credentialID]
+
+		if !auth.NeedsUserInteraction(authConfig) {
+			oauthConfig, err := auth.NewOAuth2Config(authConfig)
+			if err != nil {
+				return nil, err
+			}
+			credential, err := oauthConfig.ClientCredentials(ctx, credentialID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to obtain client credentials for %s: %w", credentialID, err)
+			}
+			if err := r.credentialService.StoreCredential(ctx, &core.StoreCredentialRequest{
+				AppName:    session.AppName,
+				UserID:     session.UserID,
+				Credential: credential,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to store credential %s: %w", credentialID, err)
+			}
+			continue
+		}
+
+		prepared, err := auth.PrepareAuthRequest(authConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth config for %s: %w", credentialID, err)
+		}
+		pending[credentialID] = prepared
+
+		callID := "auth_" + pauseEvent.ID + "_" + credentialID
+		if err := r.storePendingAuth(ctx, session, callID, credentialID, authConfig, prepared); err != nil {
+			return nil, err
+		}
+
+		callIDs = append(callIDs, callID)
+		parts = append(parts, core.Part{
+			Type: "function_call",
+			FunctionCall: &core.FunctionCall{
+				ID:   callID,
+				Name: auth.RequestCredentialFunctionName,
+				Args: map[string]any{
+					"credential_id": credentialID,
+					"auth_config":   prepared,
+				},
+			},
+		})
+	}
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	pauseEvent.Content = &core.Content{Role: "model", Parts: parts}
+	pauseEvent.Actions.RequestedAuthConfigs = pending
+	pauseEvent.LongRunningToolIDs = callIDs
+	pauseEvent.TurnComplete = ptr.Ptr(true)
+	return pauseEvent, nil
+}
+
+// storePendingAuth keeps the auth config a tool requested, with the state sent
+// to the client, until the client responds to the call.
+func (r *RunnerImpl) storePendingAuth(ctx context.Context, session *core.Session, callID, credentialID string,
+	requested, prepared core.AuthConfig) error {
+
+	config := make(map[string]any, len(requested.Config)+1)
+	for key, value := range requested.Config {
+		config[key] = value
+	}
+	if state, ok := prepared.Config["state"]; ok {
+		config["state"] = state
+	}
+
+	err := r.credentialService.StoreCredential(ctx, &core.StoreCredentialRequest{
+		AppName: session.AppName,
+		UserID:  session.UserID,
+		Credential: &core.Credential{
+			ID:   pendingAuthID(callID),
+			Type: pendingAuthType,
+			Data: map[string]any{
+				"credential_id": credentialID,
+				"auth_config":   core.AuthConfig{Scheme: requested.Scheme, Config: config},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store auth request %s: %w", callID, err)
+	}
+	return nil
+}
+
+// loadPendingAuth returns the auth request sent to the client in a call, or nil
+// if there is none.
+func (r *RunnerImpl) loadPendingAuth(ctx context.Context, session *core.Session, callID string) (*auth.AuthRequest, error) {
+	if callID == "" {
+		return nil, nil
+	}
+	credential, err := r.credentialService.GetCredential(ctx, &core.CredentialRequest{
+		AppName:      session.AppName,
+		UserID:       session.UserID,
+		CredentialID: pendingAuthID(callID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load auth request %s: %w", callID, err)
+	}
+	if credential == nil {
+		return nil, nil
+	}
+
+	// Stores may hand the data back as decoded JSON
+	data, err := json.Marshal(credential.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth request %s: %w", callID, err)
+	}
+	var request auth.AuthRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, fmt.Errorf("invalid auth request %s: %w", callID, err)
+	}
+	return &request, nil
+}
+
+// processAuthResponses stores the credentials posted back by the client in
+// adk_request_credential function responses. It returns a copy of the message
+// in which the secrets are replaced by a status, so they never reach the
+// session history or the model.
+func (r *RunnerImpl) processAuthResponses(ctx context.Context, session *core.Session,
+	message *core.Content) (*core.Content, error) {
+
+	var result *core.Content
+	for i, part := range message.Parts {
+		response := part.FunctionResponse
+		if response == nil || response.Name != auth.RequestCredentialFunctionName {
+			continue
+		}
+		if r.credentialService == nil {
+			return nil, fmt.Errorf("credential service is not configured")
+		}
+
+		var authResponse auth.AuthRequest
+		data, err := json.Marshal(response.Response)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth response: %w", err)
+		}
+		if err := json.Unmarshal(data, &authResponse); err != nil {
+			return nil, fmt.Errorf("invalid auth response: %w", err)
+		}
+
+		requested, err := r.loadPendingAuth(ctx, session, response.ID)
+		if err != nil {
+			return nil, err
+		}
+		if requested == nil {
+			return nil, fmt.Errorf("no pending auth request for call %q", response.ID)
+		}
+		if authResponse.CredentialID != "" && authResponse.CredentialID != requested.CredentialID {
+			return nil, fmt.Errorf("auth response for credential %q does not match the request for %q",
+				authResponse.CredentialID, requested.CredentialID)
+		}
+
+		credential, err := auth.CredentialFromAuthResponse(ctx, requested.CredentialID, requested.AuthConfig, authResponse.AuthConfig)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.credentialService.StoreCredential(ctx, &core.StoreCredentialRequest{
+			AppName:    session.AppName,
+			UserID:     session.UserID,
+			Credential: credential,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to store credential %s: %w", credential.ID, err)
+		}
+		// An auth request is answered once
+		if err := r.credentialService.DeleteCredential(ctx, &core.CredentialRequest{
+			AppName:      session.AppName,
+			UserID:       session.UserID,
+			CredentialID: pendingAuthID(response.ID),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to delete auth request %s: %w", response.ID, err)
+		}
+
+		if result == nil {
+			result = &core.Content{Role: message.Role, Parts: append([]core.Part(nil), message.Parts...)}
+		}
+		result.Parts[i].FunctionResponse = &core.FunctionResponse{
+			ID:   response.ID,
+			Name: response.Name,
+			Response: map[string]any{
+				"credential_id": requested.CredentialID,
+				"status":        "credential received",
+			},
+		}
+	}
+
+	if result == nil {
+		return message, nil
+	}
+	return result, nil
+}
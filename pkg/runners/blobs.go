@@ -0,0 +1,59 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// saveInputBlobs honors RunConfig.SaveInputBlobsAsArtifacts: inline blobs of
+// the new message larger than RunConfig.MaxInlineBlobSize are saved to the
+// artifact service and replaced by file_data parts referencing them, which
+// LLM agents load back when they build their requests. The message is copied
+// rather than modified.
+func (r *RunnerImpl) saveInputBlobs(ctx context.Context, invocationCtx *core.InvocationContext,
+	message *core.Content) (*core.Content, error) {
+
+	runConfig := invocationCtx.RunConfig
+	if runConfig == nil || !runConfig.SaveInputBlobsAsArtifacts || r.artifactService == nil {
+		return message, nil
+	}
+
+	session := invocationCtx.Session
+	var parts []core.Part
+	for i, part := range message.Parts {
+		blob := part.InlineData
+		if blob == nil || len(blob.Data) <= runConfig.MaxInlineBlobSize {
+			continue
+		}
+		if parts == nil {
+			parts = append([]core.Part(nil), message.Parts...)
+		}
+
+		filename := blob.DisplayName
+		if filename == "" {
+			filename = fmt.Sprintf("artifact_%s_%d", invocationCtx.InvocationID, i)
+		}
+		version, err := r.artifactService.SaveArtifact(ctx, &core.SaveArtifactRequest{
+			AppName:   session.AppName,
+			UserID:    session.UserID,
+			SessionID: session.ID,
+			Filename:  filename,
+			Content:   blob.Data,
+			MimeType:  blob.MimeType,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save %s: %w", filename, err)
+		}
+
+		parts[i] = core.NewFileDataPart(core.ArtifactURIScheme+filename, blob.MimeType)
+		parts[i].FileData.DisplayName = blob.DisplayName
+		parts[i].Metadata = map[string]any{"artifact_version": version}
+	}
+	if parts == nil {
+		return message, nil
+	}
+
+	return &core.Content{Role: message.Role, Parts: parts}, nil
+}
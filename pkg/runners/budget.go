@@ -0,0 +1,234 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+// ErrorCodeBudgetExceeded is the error code of the event that ends an
+// invocation which ran out of budget.
+const ErrorCodeBudgetExceeded = "BUDGET_EXCEEDED"
+
+// dailyUsage is the usage of one user during one UTC day.
+type dailyUsage struct {
+	day   string
+	usage core.BudgetUsage
+}
+
+// dailyUsageLedger tracks the daily usage of users across the invocations of
+// a runner. A user's entry is seeded from their stored sessions the first
+// time it is needed each day, so it survives runner restarts.
+type dailyUsageLedger struct {
+	mu      sync.Mutex
+	entries map[string]*dailyUsage
+}
+
+// usageDay returns the UTC day a time counts against.
+func usageDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// load returns the usage of a user today, seeding it from the session service
+// if the ledger has no entry for today yet.
+func (l *dailyUsageLedger) load(ctx context.Context, r *RunnerImpl, userID string) (core.BudgetUsage, error) {
+	today := usageDay(time.Now())
+
+	l.mu.Lock()
+	if entry, ok := l.entries[userID]; ok && entry.day == today {
+		usage := entry.usage
+		l.mu.Unlock()
+		return usage, nil
+	}
+	l.mu.Unlock()
+
+	seeded, err := r.loadDailyUsage(ctx, userID, today)
+	if err != nil {
+		return core.BudgetUsage{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if entry, ok := l.entries[userID]; ok && entry.day == today {
+		// Another invocation seeded the entry meanwhile
+		return entry.usage, nil
+	}
+	if l.entries == nil {
+		l.entries = make(map[string]*dailyUsage)
+	}
+	l.entries[userID] = &dailyUsage{day: today, usage: seeded}
+	return seeded, nil
+}
+
+// add records an event against a user's daily usage and returns the updated usage.
+func (l *dailyUsageLedger) add(userID string, event *core.Event) core.BudgetUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	today := usageDay(time.Now())
+	entry, ok := l.entries[userID]
+	if !ok || entry.day != today {
+		if l.entries == nil {
+			l.entries = make(map[string]*dailyUsage)
+		}
+		entry = &dailyUsage{day: today}
+		l.entries[userID] = entry
+	}
+	entry.usage.AddEvent(event)
+	return entry.usage
+}
+
+// loadDailyUsage sums the usage recorded on the given day across all sessions of a user.
+func (r *RunnerImpl) loadDailyUsage(ctx context.Context, userID, day string) (core.BudgetUsage, error) {
+	var usage core.BudgetUsage
+
+	list, err := r.sessionService.ListSessions(ctx, &core.ListSessionsRequest{
+		AppName: r.appName,
+		UserID:  userID,
+	})
+	if err != nil {
+		return usage, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for _, listed := range list.Sessions {
+		// Listings omit events, so each session is loaded in full
+		session, err := r.sessionService.GetSession(ctx, &core.GetSessionRequest{
+			AppName:   r.appName,
+			UserID:    userID,
+			SessionID: listed.ID,
+		})
+		if err != nil {
+			return usage, fmt.Errorf("failed to get session %s: %w", listed.ID, err)
+		}
+		if session == nil {
+			continue
+		}
+		for _, event := range session.Events {
+			if usageDay(event.Timestamp) == day {
+				usage.AddEvent(event)
+			}
+		}
+	}
+	return usage, nil
+}
+
+// budgetTracker enforces the invocation, session and daily user budgets of
+// one invocation. A nil tracker enforces nothing.
+type budgetTracker struct {
+	ledger *dailyUsageLedger
+	userID string
+
+	invocationBudget *core.Budget
+	sessionBudget    *core.Budget
+	dailyBudget      *core.Budget
+
+	invocation core.BudgetUsage
+	session    core.BudgetUsage
+	daily      core.BudgetUsage
+}
+
+// newBudgetTracker returns the tracker for an invocation on the given
+// session, or nil if no budget applies.
+func (r *RunnerImpl) newBudgetTracker(ctx context.Context, req *core.RunRequest, session *core.Session) (*budgetTracker, error) {
+	r.mu.RLock()
+	tracker := &budgetTracker{
+		ledger:           r.dailyUsage,
+		userID:           session.UserID,
+		invocationBudget: r.config.InvocationBudget,
+		sessionBudget:    r.config.SessionBudget,
+		dailyBudget:      r.config.DailyUserBudget,
+	}
+	r.mu.RUnlock()
+
+	if req.RunConfig != nil && req.RunConfig.Budget != nil {
+		tracker.invocationBudget = req.RunConfig.Budget
+	}
+	if tracker.invocationBudget == nil && tracker.sessionBudget == nil && tracker.dailyBudget == nil {
+		return nil, nil
+	}
+
+	for _, event := range session.Events {
+		tracker.session.AddEvent(event)
+	}
+	if tracker.dailyBudget != nil {
+		daily, err := tracker.ledger.load(ctx, r, tracker.userID)
+		if err != nil {
+			return nil, err
+		}
+		tracker.daily = daily
+	}
+	return tracker, nil
+}
+
+// exhausted reports why a new invocation may not start, or an empty string.
+func (t *budgetTracker) exhausted() string {
+	if t == nil {
+		return ""
+	}
+	if limit := t.sessionBudget.Exhausted(&t.session); limit != "" {
+		return fmt.Sprintf("Session budget exhausted: %s", limit)
+	}
+	if limit := t.dailyBudget.Exhausted(&t.daily); limit != "" {
+		return fmt.Sprintf("Daily budget of user %s exhausted: %s", t.userID, limit)
+	}
+	return ""
+}
+
+// record counts an agent event against the budgets and reports why the
+// invocation must stop, or an empty string. An invocation stops once a limit
+// is exceeded, or when a limit on LLM calls or tokens is reached and the
+// event hands tool results back to the model for another call.
+func (t *budgetTracker) record(event *core.Event) string {
+	if t == nil {
+		return ""
+	}
+	t.invocation.AddEvent(event)
+	t.session.AddEvent(event)
+	if t.dailyBudget != nil {
+		t.daily = t.ledger.add(t.userID, event)
+	}
+
+	if event.Partial != nil && *event.Partial {
+		return ""
+	}
+	nextCallsModel := len(event.GetFunctionResponses()) > 0 && len(event.GetFunctionCalls()) == 0
+
+	scopes := []struct {
+		name   string
+		budget *core.Budget
+		usage  *core.BudgetUsage
+	}{
+		{"Invocation budget", t.invocationBudget, &t.invocation},
+		{"Session budget", t.sessionBudget, &t.session},
+		{fmt.Sprintf("Daily budget of user %s", t.userID), t.dailyBudget, &t.daily},
+	}
+	for _, scope := range scopes {
+		if scope.budget == nil {
+			continue
+		}
+		if limit := scope.budget.Exceeded(scope.usage); limit != "" {
+			return fmt.Sprintf("%s exceeded: %s", scope.name, limit)
+		}
+		if nextCallsModel {
+			// Tool calls are only limited once the model asks for more
+			modelBudget := *scope.budget
+			modelBudget.MaxToolCalls = 0
+			if limit := modelBudget.Exhausted(scope.usage); limit != "" {
+				return fmt.Sprintf("%s exhausted: %s", scope.name, limit)
+			}
+		}
+	}
+	return ""
+}
+
+// newBudgetEvent creates the error event that ends an invocation out of budget.
+func newBudgetEvent(invocationCtx *core.InvocationContext, author, message string) *core.Event {
+	event := core.NewEvent(invocationCtx.InvocationID, author)
+	event.ErrorCode = ptr.Ptr(ErrorCodeBudgetExceeded)
+	event.ErrorMessage = ptr.Ptr(message)
+	event.TurnComplete = ptr.Ptr(true)
+	return event
+}
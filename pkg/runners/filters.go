@@ -0,0 +1,77 @@
+package runners
+
+import (
+	"fmt"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// Filter inspects the content entering and leaving a runner, for example to
+// enforce guardrails. Filters may rewrite or block content, and explain what
+// they did with events that the runner stores and forwards like any other.
+// Filters check complete content, so partial events are not forwarded while
+// filters are set.
+type Filter interface {
+	// FilterMessage is called with each new message before it is stored
+	// and the agent runs. It returns the message to use, nil to block the
+	// invocation, and optionally an event explaining the decision.
+	FilterMessage(invocationCtx *core.InvocationContext, message *core.Content) (*core.Content, *core.Event, error)
+
+	// FilterEvent is called with each complete event of the agent before
+	// it is stored and forwarded. It returns the event to use, or nil to
+	// drop it.
+	FilterEvent(invocationCtx *core.InvocationContext, event *core.Event) (*core.Event, error)
+}
+
+// AddFilter adds a filter to the runner. Filters run in the order added.
+func (r *RunnerImpl) AddFilter(filter Filter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filters = append(r.filters, filter)
+}
+
+// filterMessage runs the new message through the filters. A nil message
+// means it was blocked; the returned events explain the filters' decisions.
+func (r *RunnerImpl) filterMessage(invocationCtx *core.InvocationContext, filters []Filter,
+	message *core.Content) (*core.Content, []*core.Event, error) {
+
+	var events []*core.Event
+	for _, filter := range filters {
+		filtered, event, err := filter.FilterMessage(invocationCtx, message)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to filter message: %w", err)
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+		if filtered == nil {
+			return nil, events, nil
+		}
+		message = filtered
+	}
+	return message, events, nil
+}
+
+// filterEvent runs an event of the agent through the filters. Partial events
+// are dropped, as the filters only see complete content.
+func (r *RunnerImpl) filterEvent(invocationCtx *core.InvocationContext, filters []Filter,
+	event *core.Event) (*core.Event, error) {
+
+	if len(filters) == 0 {
+		return event, nil
+	}
+	if event.Partial != nil && *event.Partial {
+		return nil, nil
+	}
+	for _, filter := range filters {
+		filtered, err := filter.FilterEvent(invocationCtx, event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter event: %w", err)
+		}
+		if filtered == nil {
+			return nil, nil
+		}
+		event = filtered
+	}
+	return event, nil
+}
@@ -25,6 +25,12 @@ type RunnerImpl struct {
 	memoryService     core.MemoryService
 	credentialService core.CredentialService
 
+	// Daily usage per user, for DailyUserBudget
+	dailyUsage *dailyUsageLedger
+
+	// Filters applied to messages and events, in order
+	filters []Filter
+
 	// Configuration options
 	config *RunnerConfig
 
@@ -48,6 +54,18 @@ type RunnerConfig struct {
 
 	// DefaultTimeout is the default timeout for operations.
 	DefaultTimeout time.Duration
+
+	// InvocationBudget limits the LLM usage of each invocation.
+	// RunConfig.Budget overrides it for a single request (nil = unlimited).
+	InvocationBudget *core.Budget
+
+	// SessionBudget limits the LLM usage of a session across all of its
+	// invocations (nil = unlimited).
+	SessionBudget *core.Budget
+
+	// DailyUserBudget limits the LLM usage of all sessions of a user
+	// during a UTC day (nil = unlimited).
+	DailyUserBudget *core.Budget
 }
 
 // DefaultRunnerConfig returns default configuration for a Runner.
@@ -84,6 +102,7 @@ func NewRunnerWithConfig(
 		appName:        appName,
 		agent:          agent,
 		sessionService: sessionService,
+		dailyUsage:     &dailyUsageLedger{},
 		config:         config,
 	}
 }
@@ -125,6 +144,7 @@ func (r *RunnerImpl) RunAsync(ctx context.Context, req *core.RunRequest) (core.E
 	r.mu.RLock()
 	eventBufferSize := r.config.EventBufferSize
 	enableEventProcessing := r.config.EnableEventProcessing
+	filters := r.filters
 	r.mu.RUnlock()
 
 	// Get or create session
@@ -133,12 +153,51 @@ func (r *RunnerImpl) RunAsync(ctx context.Context, req *core.RunRequest) (core.E
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
 
-	// Create invocation context
-	invocationCtx := r.createInvocationContext(ctx, req, session)
+	// Load the usage counted against the budgets of this invocation
+	budget, err := r.newBudgetTracker(ctx, req, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load budget usage: %w", err)
+	}
+
+	// Store credentials posted back for a paused invocation
+	newMessage := req.NewMessage
+	if newMessage != nil {
+		newMessage, err = r.processAuthResponses(ctx, session, newMessage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process auth response: %w", err)
+		}
+	}
+
+	// Create invocation context; cancelling it stops the agent when pausing for auth
+	runCtx, cancel := context.WithCancel(ctx)
+	invocationCtx := r.createInvocationContext(runCtx, req, session)
+
+	// Offload input blobs to the artifact service if requested
+	if newMessage != nil {
+		newMessage, err = r.saveInputBlobs(ctx, invocationCtx, newMessage)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to save input blobs: %w", err)
+		}
+	}
+
+	// Let the filters rewrite or block the new message
+	var filterEvents []*core.Event
+	blocked := false
+	if newMessage != nil && len(filters) > 0 {
+		newMessage, filterEvents, err = r.filterMessage(invocationCtx, filters, newMessage)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		blocked = newMessage == nil
+	}
+	invocationCtx.UserContent = newMessage
 
 	// Append new message to session if provided
-	if req.NewMessage != nil {
-		if err := r.appendNewMessageToSession(ctx, session, req.NewMessage, invocationCtx); err != nil {
+	if newMessage != nil {
+		if err := r.appendNewMessageToSession(ctx, session, newMessage, invocationCtx); err != nil {
+			cancel()
 			return nil, fmt.Errorf("failed to append message to session: %w", err)
 		}
 	}
@@ -153,6 +212,28 @@ func (r *RunnerImpl) RunAsync(ctx context.Context, req *core.RunRequest) (core.E
 	// Start asynchronous processing
 	go func() {
 		defer close(eventChan)
+		defer cancel()
+
+		// Explain the filters' decisions on the new message
+		for _, event := range filterEvents {
+			if appendErr := r.sessionService.AppendEvent(ctx, session, event); appendErr != nil {
+				fmt.Printf("Failed to append event to session: %v\n", appendErr)
+			}
+			select {
+			case eventChan <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if blocked {
+			return
+		}
+
+		// Refuse to start once the session or user is out of budget
+		if message := budget.exhausted(); message != "" {
+			r.sendBudgetEvent(ctx, eventChan, session, newBudgetEvent(invocationCtx, agentToRun.Name(), message))
+			return
+		}
 
 		// Execute before-agent callback if present
 		if callback := agentToRun.GetBeforeAgentCallback(); callback != nil {
@@ -175,6 +256,24 @@ func (r *RunnerImpl) RunAsync(ctx context.Context, req *core.RunRequest) (core.E
 
 		// Process events from agent stream
 		for event := range agentStream {
+			// Let the filters rewrite or drop the event
+			event, err := r.filterEvent(invocationCtx, filters, event)
+			if err != nil {
+				// Drop the event rather than forward unfiltered content
+				fmt.Printf("Failed to filter event: %v\n", err)
+				continue
+			}
+			if event == nil {
+				continue
+			}
+
+			// Secrets of the credentials requested by tools stay on the server:
+			// the event is stored and forwarded with redacted auth configs
+			requestedAuth := event.Actions.RequestedAuthConfigs
+			if len(requestedAuth) > 0 {
+				event.Actions.RequestedAuthConfigs = redactAuthConfigs(requestedAuth)
+			}
+
 			// Process event actions if enabled
 			if enableEventProcessing {
 				if err := r.processEventActions(ctx, session, event, invocationCtx); err != nil {
@@ -191,6 +290,9 @@ func (r *RunnerImpl) RunAsync(ctx context.Context, req *core.RunRequest) (core.E
 				}
 			}
 
+			// Count the event against the budgets
+			budgetMessage := budget.record(event)
+
 			// Collect for callback
 			collectedEvents = append(collectedEvents, event)
 
@@ -200,6 +302,51 @@ func (r *RunnerImpl) RunAsync(ctx context.Context, req *core.RunRequest) (core.E
 			case <-ctx.Done():
 				return
 			}
+
+			// Stop the invocation gracefully once it is out of budget
+			if budgetMessage != "" {
+				budgetEvent := newBudgetEvent(invocationCtx, agentToRun.Name(), budgetMessage)
+				collectedEvents = append(collectedEvents, budgetEvent)
+				if !r.sendBudgetEvent(ctx, eventChan, session, budgetEvent) {
+					return
+				}
+
+				// Stop the agent and let it wind down in the background
+				cancel()
+				go func() {
+					for range agentStream {
+					}
+				}()
+				break
+			}
+
+			// Pause the invocation if a tool needs credentials from the client
+			if len(requestedAuth) > 0 && r.credentialService != nil {
+				pauseEvent, err := r.handleAuthRequests(ctx, invocationCtx, event.Author, requestedAuth)
+				if err != nil {
+					pauseEvent = core.NewEvent(invocationCtx.InvocationID, agentToRun.Name())
+					pauseEvent.ErrorMessage = ptr.Ptr(fmt.Sprintf("Auth request failed: %v", err))
+				}
+				if pauseEvent != nil {
+					if appendErr := r.sessionService.AppendEvent(ctx, session, pauseEvent); appendErr != nil {
+						fmt.Printf("Failed to append event to session: %v\n", appendErr)
+					}
+					collectedEvents = append(collectedEvents, pauseEvent)
+					select {
+					case eventChan <- pauseEvent:
+					case <-ctx.Done():
+						return
+					}
+
+					// Stop the agent and let it wind down in the background
+					cancel()
+					go func() {
+						for range agentStream {
+						}
+					}()
+					break
+				}
+			}
 		}
 
 		// Execute after-agent callback if present
@@ -336,6 +483,23 @@ func (r *RunnerImpl) sendErrorEvent(eventChan chan<- *core.Event, invocationCtx
 	}
 }
 
+// sendBudgetEvent records a budget error event in the session and sends it to
+// the event channel. It returns false if the caller went away.
+func (r *RunnerImpl) sendBudgetEvent(ctx context.Context, eventChan chan<- *core.Event,
+	session *core.Session, event *core.Event) bool {
+
+	if appendErr := r.sessionService.AppendEvent(ctx, session, event); appendErr != nil {
+		fmt.Printf("Failed to append event to session: %v\n", appendErr)
+	}
+
+	select {
+	case eventChan <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // processEventActions processes actions contained in an event.
 // This includes applying state changes, managing artifacts, and handling agent transfers.
 func (r *RunnerImpl) processEventActions(ctx context.Context, session *core.Session,
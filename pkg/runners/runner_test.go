@@ -2,12 +2,20 @@ package runners
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/agent-protocol/adk-golang/pkg/agents"
+	"github.com/agent-protocol/adk-golang/pkg/artifacts"
+	"github.com/agent-protocol/adk-golang/pkg/auth"
 	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/llmconnect/ollama"
 	"github.com/agent-protocol/adk-golang/pkg/ptr"
 	"github.com/agent-protocol/adk-golang/pkg/sessions"
 )
@@ -527,3 +535,395 @@ func TestDefaultRunnerConfig(t *testing.T) {
 		t.Errorf("Expected default DefaultTimeout 30s, got %v", config.DefaultTimeout)
 	}
 }
+
+func TestRunnerAuthPauseAndResume(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "authorization_code" || r.Form.Get("code") != "the-code" {
+			http.Error(w, "bad grant", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "token-1", "refresh_token": "refresh-1", "expires_in": 3600}`)
+	}))
+	defer tokenServer.Close()
+
+	authConfig := core.AuthConfig{
+		Scheme: auth.SchemeOAuth2,
+		Config: map[string]any{
+			"client_id":     "client",
+			"client_secret": "secret",
+			"auth_url":      "https://auth.example.com/authorize",
+			"token_url":     tokenServer.URL,
+		},
+	}
+	toolEvent := core.NewEvent("inv", "test_agent")
+	toolEvent.Content = &core.Content{Role: "agent", Parts: []core.Part{{
+		Type:             "function_response",
+		FunctionResponse: &core.FunctionResponse{ID: "call-1", Name: "calendar", Response: map[string]any{"result": "authorization required"}},
+	}}}
+	toolEvent.Actions.RequestedAuthConfigs = map[string]core.AuthConfig{"calendar": authConfig}
+	afterEvent := core.NewEvent("inv", "test_agent")
+	afterEvent.Content = &core.Content{Role: "model", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("should not run")}}}
+
+	sessionService := sessions.NewInMemorySessionService()
+	credentialService := auth.NewInMemoryCredentialService()
+	runner := NewRunner("test_app", &MockAgent{name: "test_agent", events: []*core.Event{toolEvent, afterEvent}}, sessionService)
+	runner.SetCredentialService(credentialService)
+
+	ctx := context.Background()
+	events, err := runner.Run(ctx, &core.RunRequest{
+		UserID:     "alice",
+		SessionID:  "s1",
+		NewMessage: &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("What is on my calendar?")}}},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	pauseEvent := events[len(events)-1]
+	calls := pauseEvent.GetFunctionCalls()
+	if len(calls) != 1 || calls[0].Name != auth.RequestCredentialFunctionName {
+		t.Fatalf("Expected run to end with an auth request, got %+v", pauseEvent.Content)
+	}
+	for _, event := range events {
+		if event == afterEvent {
+			t.Fatal("Agent should be paused after the auth request")
+		}
+	}
+	session, _ := sessionService.GetSession(ctx, &core.GetSessionRequest{AppName: "test_app", UserID: "alice", SessionID: "s1"})
+	for _, event := range append(events, session.Events...) {
+		for _, requested := range event.Actions.RequestedAuthConfigs {
+			if _, leaked := requested.Config["client_secret"]; leaked {
+				t.Fatalf("Client secret must not be stored or forwarded, got it on event %s", event.ID)
+			}
+		}
+	}
+	prepared := pauseEvent.Actions.RequestedAuthConfigs["calendar"]
+	if _, leaked := prepared.Config["client_secret"]; leaked {
+		t.Error("Client secret must not be sent to the client")
+	}
+	authorizationURL, _ := prepared.Config["authorization_url"].(string)
+	state, _ := prepared.Config["state"].(string)
+	if !strings.HasPrefix(authorizationURL, "https://auth.example.com/authorize?") || state == "" {
+		t.Fatalf("Expected authorization URL and state, got %v", prepared.Config)
+	}
+
+	// The client completes the OAuth2 flow and posts the redirect back
+	response := &core.Content{Role: "user", Parts: []core.Part{{
+		Type: "function_response",
+		FunctionResponse: &core.FunctionResponse{
+			ID:   calls[0].ID,
+			Name: auth.RequestCredentialFunctionName,
+			Response: map[string]any{
+				"credential_id": "calendar",
+				"auth_config": map[string]any{
+					"scheme":     auth.SchemeOAuth2,
+					"credential": map[string]any{"auth_response_uri": "https://app.example.com/callback?code=the-code&state=" + state},
+				},
+			},
+		},
+	}}}
+	runner = NewRunner("test_app", &MockAgent{name: "test_agent"}, sessionService)
+	runner.SetCredentialService(credentialService)
+
+	// A tampered state is rejected
+	forged := &core.Content{Role: "user", Parts: []core.Part{{
+		Type: "function_response",
+		FunctionResponse: &core.FunctionResponse{
+			ID:   calls[0].ID,
+			Name: auth.RequestCredentialFunctionName,
+			Response: map[string]any{
+				"credential_id": "calendar",
+				"auth_config": map[string]any{
+					"scheme":     auth.SchemeOAuth2,
+					"credential": map[string]any{"auth_response_uri": "https://app.example.com/callback?code=the-code&state=forged"},
+				},
+			},
+		},
+	}}}
+	if _, err := runner.Run(ctx, &core.RunRequest{UserID: "alice", SessionID: "s1", NewMessage: forged}); err == nil {
+		t.Error("Expected state mismatch to be rejected")
+	}
+
+	if _, err := runner.Run(ctx, &core.RunRequest{UserID: "alice", SessionID: "s1", NewMessage: response}); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	credential, err := credentialService.GetCredential(ctx, &core.CredentialRequest{AppName: "test_app", UserID: "alice", CredentialID: "calendar"})
+	if err != nil || credential == nil {
+		t.Fatalf("Expected stored credential, got %v (err=%v)", credential, err)
+	}
+	if credential.Data["access_token"] != "token-1" || credential.ExpiresAt == nil {
+		t.Errorf("Unexpected credential: %+v", credential)
+	}
+
+	session, _ = sessionService.GetSession(ctx, &core.GetSessionRequest{AppName: "test_app", UserID: "alice", SessionID: "s1"})
+	stored := session.Events[len(session.Events)-1].Content.Parts[0].FunctionResponse.Response
+	if _, leaked := stored["auth_config"]; leaked {
+		t.Errorf("Auth response secrets must not be stored in the session: %v", stored)
+	}
+
+	// An auth request is answered once
+	if _, err := runner.Run(ctx, &core.RunRequest{UserID: "alice", SessionID: "s1", NewMessage: response}); err == nil {
+		t.Error("Expected a replayed auth response to be rejected")
+	}
+}
+
+func TestRunnerBudgets(t *testing.T) {
+	newCallEvent := func() *core.Event {
+		event := core.NewEvent("inv", "test_agent")
+		event.Content = &core.Content{Role: "agent", Parts: []core.Part{{
+			Type:         "function_call",
+			FunctionCall: &core.FunctionCall{ID: "call-1", Name: "search", Args: map[string]any{}},
+		}}}
+		event.Usage = &core.Usage{InputTokens: 50, OutputTokens: 5, TotalTokens: 55, LLMCalls: 1}
+		return event
+	}
+	newResultEvent := func() *core.Event {
+		event := core.NewEvent("inv", "test_agent")
+		event.Content = &core.Content{Role: "agent", Parts: []core.Part{{
+			Type:             "function_response",
+			FunctionResponse: &core.FunctionResponse{ID: "call-1", Name: "search", Response: map[string]any{"result": "ok"}},
+		}}}
+		return event
+	}
+	newAnswerEvent := func() *core.Event {
+		event := core.NewEvent("inv", "test_agent")
+		event.Content = &core.Content{Role: "model", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("done")}}}
+		event.Usage = &core.Usage{InputTokens: 70, OutputTokens: 10, TotalTokens: 80, LLMCalls: 1}
+		return event
+	}
+	newAgent := func() *MockAgent {
+		return &MockAgent{name: "test_agent", events: []*core.Event{newCallEvent(), newResultEvent(), newAnswerEvent()}}
+	}
+	message := &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("hi")}}}
+	budgetError := func(events []*core.Event) string {
+		last := events[len(events)-1]
+		if last.ErrorCode == nil || *last.ErrorCode != ErrorCodeBudgetExceeded {
+			return ""
+		}
+		return *last.ErrorMessage
+	}
+
+	t.Run("invocation", func(t *testing.T) {
+		runner := NewRunner("test_app", newAgent(), sessions.NewInMemorySessionService())
+		events, err := runner.Run(context.Background(), &core.RunRequest{
+			UserID:     "alice",
+			SessionID:  "s1",
+			NewMessage: message,
+			RunConfig:  &core.RunConfig{Budget: &core.Budget{MaxLLMCalls: 1}},
+		})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		// The tool result is delivered, but the second LLM call is not made
+		if len(events) != 3 {
+			t.Fatalf("Expected call, result and budget events, got %d", len(events))
+		}
+		if msg := budgetError(events); msg != "Invocation budget exhausted: max_llm_calls" {
+			t.Errorf("Unexpected budget error: %q", msg)
+		}
+	})
+
+	t.Run("session", func(t *testing.T) {
+		config := DefaultRunnerConfig()
+		config.SessionBudget = &core.Budget{MaxInputTokens: 100}
+		sessionService := sessions.NewInMemorySessionService()
+		runner := NewRunnerWithConfig("test_app", newAgent(), sessionService, config)
+
+		request := &core.RunRequest{UserID: "alice", SessionID: "s1", NewMessage: message}
+		events, err := runner.Run(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if len(events) != 4 || budgetError(events) != "Session budget exceeded: max_input_tokens" {
+			t.Fatalf("Expected the run to end with a budget error, got %d events", len(events))
+		}
+
+		// The next invocation on the session is refused up front
+		events, err = runner.Run(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if len(events) != 1 || budgetError(events) != "Session budget exhausted: max_input_tokens" {
+			t.Fatalf("Expected a single budget error, got %d events", len(events))
+		}
+
+		// Another session is unaffected
+		events, _ = runner.Run(context.Background(), &core.RunRequest{UserID: "alice", SessionID: "s2", NewMessage: message})
+		if len(events) != 4 {
+			t.Errorf("Expected a fresh session to run, got %d events", len(events))
+		}
+	})
+
+	t.Run("daily user", func(t *testing.T) {
+		config := DefaultRunnerConfig()
+		config.DailyUserBudget = &core.Budget{MaxToolCalls: 2}
+		sessionService := sessions.NewInMemorySessionService()
+
+		for _, sessionID := range []string{"s1", "s2"} {
+			runner := NewRunnerWithConfig("test_app", newAgent(), sessionService, config)
+			events, _ := runner.Run(context.Background(), &core.RunRequest{UserID: "alice", SessionID: sessionID, NewMessage: message})
+			if msg := budgetError(events); msg != "" {
+				t.Fatalf("Unexpected budget error in %s: %q", sessionID, msg)
+			}
+		}
+
+		// A new runner seeds the daily usage from the stored sessions
+		runner := NewRunnerWithConfig("test_app", newAgent(), sessionService, config)
+		events, _ := runner.Run(context.Background(), &core.RunRequest{UserID: "alice", SessionID: "s3", NewMessage: message})
+		if len(events) != 1 || budgetError(events) != "Daily budget of user alice exhausted: max_tool_calls" {
+			t.Fatalf("Expected the daily budget to be exhausted, got %d events", len(events))
+		}
+		events, _ = runner.Run(context.Background(), &core.RunRequest{UserID: "bob", SessionID: "s1", NewMessage: message})
+		if msg := budgetError(events); msg != "" {
+			t.Errorf("Expected other users to be unaffected, got %q", msg)
+		}
+	})
+}
+
+func TestRunnerSaveInputBlobsAsArtifacts(t *testing.T) {
+	sessionService := sessions.NewInMemorySessionService()
+	artifactService := artifacts.NewInMemoryArtifactService()
+	runner := NewRunner("test_app", &MockAgent{name: "test_agent"}, sessionService)
+	runner.SetArtifactService(artifactService)
+
+	image := []byte("\x89PNG large image")
+	ctx := context.Background()
+	_, err := runner.Run(ctx, &core.RunRequest{
+		UserID:    "alice",
+		SessionID: "s1",
+		NewMessage: &core.Content{Role: "user", Parts: []core.Part{
+			{Type: "text", Text: ptr.Ptr("What is in this picture?")},
+			core.NewInlineDataPart("image/png", image),
+			core.NewInlineDataPart("text/plain", []byte("tiny")),
+		}},
+		RunConfig: &core.RunConfig{SaveInputBlobsAsArtifacts: true, MaxInlineBlobSize: 8},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	session, err := sessionService.GetSession(ctx, &core.GetSessionRequest{AppName: "test_app", UserID: "alice", SessionID: "s1"})
+	if err != nil || session == nil || len(session.Events) == 0 {
+		t.Fatalf("Expected the user message to be stored, got %v (err=%v)", session, err)
+	}
+	parts := session.Events[0].Content.Parts
+	if parts[1].InlineData != nil || parts[1].FileData == nil || parts[1].FileData.MimeType != "image/png" {
+		t.Fatalf("Expected the image to be replaced by a file reference, got %+v", parts[1])
+	}
+	if parts[2].InlineData == nil {
+		t.Errorf("Expected blobs up to MaxInlineBlobSize to stay inline, got %+v", parts[2])
+	}
+
+	filename := strings.TrimPrefix(parts[1].FileData.FileURI, core.ArtifactURIScheme)
+	saved, err := artifactService.LoadArtifact(ctx, &core.LoadArtifactRequest{
+		AppName: "test_app", UserID: "alice", SessionID: "s1", Filename: filename,
+	})
+	if err != nil || string(saved) != string(image) {
+		t.Errorf("Expected the image to be saved as artifact %s, got %q (err=%v)", filename, saved, err)
+	}
+}
+
+func TestRunnerSavedInputBlobsReachTheModel(t *testing.T) {
+	var chatRequests []ollama.ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var chatRequest ollama.ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&chatRequest); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		chatRequests = append(chatRequests, chatRequest)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model": "llava", "message": {"role": "assistant", "content": "A cat."}, "done": true}`)
+	}))
+	defer server.Close()
+
+	config := ollama.DefaultOllamaConfig()
+	config.BaseURL = server.URL
+	config.Model = "llava"
+	agent := agents.NewLLMAgent("vision_agent", "Describes pictures", nil)
+	agent.SetLLMConnection(ollama.NewOllamaConnection(config))
+
+	sessionService := sessions.NewInMemorySessionService()
+	runner := NewRunner("test_app", agent, sessionService)
+	runner.SetArtifactService(artifacts.NewInMemoryArtifactService())
+
+	image := []byte("\x89PNG cat picture")
+	ctx := context.Background()
+	for _, text := range []string{"What is in this picture?", "And its color?"} {
+		message := &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}}
+		if len(chatRequests) == 0 {
+			message.Parts = append(message.Parts, core.NewInlineDataPart("image/png", image))
+		}
+		if _, err := runner.Run(ctx, &core.RunRequest{
+			UserID:     "alice",
+			SessionID:  "s1",
+			NewMessage: message,
+			RunConfig:  &core.RunConfig{SaveInputBlobsAsArtifacts: true},
+		}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+
+	session, _ := sessionService.GetSession(ctx, &core.GetSessionRequest{AppName: "test_app", UserID: "alice", SessionID: "s1"})
+	if stored := session.Events[0].Content.Parts[1]; stored.FileData == nil {
+		t.Fatalf("Expected the image to be stored as an artifact reference, got %+v", stored)
+	}
+
+	// The image is sent inline on the turn it was posted and on later ones
+	if len(chatRequests) != 2 {
+		t.Fatalf("Expected 2 chat requests, got %d", len(chatRequests))
+	}
+	for i, chatRequest := range chatRequests {
+		messages := chatRequest.Messages
+		if len(messages) == 0 || len(messages[0].Images) != 1 || string(messages[0].Images[0]) != string(image) {
+			t.Errorf("Expected the image in request %d, got %+v", i, messages)
+		}
+		for _, message := range messages {
+			if strings.Contains(message.Content, core.ArtifactURIScheme) {
+				t.Errorf("Expected no artifact reference sent to the model in request %d, got %q", i, message.Content)
+			}
+		}
+	}
+}
+
+func TestRunnerForwardsButDoesNotPersistPartialEvents(t *testing.T) {
+	partial := core.NewEvent("inv", "test_agent")
+	partial.Content = &core.Content{Role: "model", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Hel")}}}
+	partial.Partial = ptr.Ptr(true)
+	final := core.NewEvent("inv", "test_agent")
+	final.Content = &core.Content{Role: "model", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Hello")}}}
+
+	sessionService := sessions.NewInMemorySessionService()
+	runner := NewRunner("test_app", &MockAgent{name: "test_agent", events: []*core.Event{partial, final}}, sessionService)
+
+	ctx := context.Background()
+	events, err := runner.Run(ctx, &core.RunRequest{
+		UserID:     "alice",
+		SessionID:  "s1",
+		NewMessage: &core.Content{Role: "user", Parts: []core.Part{{Type: "text", Text: ptr.Ptr("Hi")}}},
+		RunConfig:  &core.RunConfig{StreamingMode: core.StreamingModeSSE},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(events) != 2 || events[0] != partial {
+		t.Fatalf("Expected the partial event to be forwarded, got %d events", len(events))
+	}
+
+	session, err := sessionService.GetSession(ctx, &core.GetSessionRequest{AppName: "test_app", UserID: "alice", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	for _, event := range session.Events {
+		if event.ID == partial.ID {
+			t.Error("Partial event must not be persisted")
+		}
+	}
+	if last := session.Events[len(session.Events)-1]; last.ID != final.ID {
+		t.Errorf("Expected the final event to be persisted, got %+v", last)
+	}
+}
@@ -34,6 +34,129 @@ func TestFileSessionService(t *testing.T) {
 	testFilePersistence(t, service, tempDir)
 }
 
+func TestSQLSessionService(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "adk.db")
+
+	service, err := NewSessionServiceFromURI("sqlite://"+dbPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to create SQL session service: %v", err)
+	}
+	defer service.Close(context.Background())
+
+	testSessionService(t, service)
+
+	// Test persistence across service instances
+	testSQLPersistence(t, service, dbPath)
+}
+
+func TestSQLSessionService_ScopedState(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewSessionServiceFromURI("sqlite://:memory:", nil)
+	if err != nil {
+		t.Fatalf("Failed to create SQL session service: %v", err)
+	}
+	defer service.Close(ctx)
+
+	first, err := service.CreateSession(ctx, &core.CreateSessionRequest{
+		AppName: "app",
+		UserID:  "alice",
+		State:   map[string]any{"app:theme": "dark", "user:lang": "en", "temp:scratch": 1, "topic": "a"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if _, ok := first.State["temp:scratch"]; ok {
+		t.Error("Temporary state should not be persisted")
+	}
+
+	event := &core.Event{
+		ID:           "event_1",
+		InvocationID: "inv_1",
+		Author:       "agent",
+		Actions:      core.EventActions{StateDelta: map[string]any{"user:lang": "fr", "topic": "b"}},
+		Timestamp:    time.Now(),
+	}
+	if err := service.AppendEvent(ctx, first, event); err != nil {
+		t.Fatalf("Failed to append event: %v", err)
+	}
+
+	// A second session of the same user sees app and user state but not session state
+	second, err := service.CreateSession(ctx, &core.CreateSessionRequest{AppName: "app", UserID: "alice"})
+	if err != nil {
+		t.Fatalf("Failed to create second session: %v", err)
+	}
+	if second.State["app:theme"] != "dark" || second.State["user:lang"] != "fr" {
+		t.Errorf("Expected shared app/user state, got %v", second.State)
+	}
+	if _, ok := second.State["topic"]; ok {
+		t.Error("Session state should not leak into other sessions")
+	}
+
+	// Another user only sees app state
+	other, err := service.CreateSession(ctx, &core.CreateSessionRequest{AppName: "app", UserID: "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create session for other user: %v", err)
+	}
+	if other.State["app:theme"] != "dark" {
+		t.Errorf("Expected app state for other user, got %v", other.State)
+	}
+	if _, ok := other.State["user:lang"]; ok {
+		t.Error("User state should not leak to other users")
+	}
+
+	metadata, err := service.GetSessionMetadata(ctx, "app", "alice", first.ID)
+	if err != nil {
+		t.Fatalf("Failed to get metadata: %v", err)
+	}
+	if metadata.EventCount != 1 {
+		t.Errorf("Expected 1 event, got %d", metadata.EventCount)
+	}
+}
+
+func testSQLPersistence(t *testing.T, service SessionService, dbPath string) {
+	ctx := context.Background()
+
+	session, err := service.CreateSession(ctx, &core.CreateSessionRequest{
+		AppName: "persistence_test",
+		UserID:  "test_user",
+		State:   map[string]any{"persistent": "data"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	event := &core.Event{ID: "event_1", InvocationID: "inv_1", Author: "agent", Timestamp: time.Now()}
+	if err := service.AppendEvent(ctx, session, event); err != nil {
+		t.Fatalf("Failed to append event: %v", err)
+	}
+
+	service.Close(ctx)
+
+	// Reopening applies no migrations twice and sees the stored data
+	newService, err := NewSessionServiceFromURI("sqlite://"+dbPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to reopen SQL session service: %v", err)
+	}
+	defer newService.Close(ctx)
+
+	persisted, err := newService.GetSession(ctx, &core.GetSessionRequest{
+		AppName:   "persistence_test",
+		UserID:    "test_user",
+		SessionID: session.ID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to get persisted session: %v", err)
+	}
+	if persisted == nil {
+		t.Fatal("Persisted session is nil")
+	}
+	if persisted.State["persistent"] != "data" {
+		t.Errorf("Expected persistent data, got %v", persisted.State["persistent"])
+	}
+	if len(persisted.Events) != 1 || persisted.Events[0].ID != "event_1" {
+		t.Errorf("Expected persisted event, got %+v", persisted.Events)
+	}
+}
+
 func testSessionService(t *testing.T, service SessionService) {
 	ctx := context.Background()
 	appName := "test_app"
@@ -0,0 +1,938 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+var _ core.SessionService = (*SQLSessionService)(nil)
+var _ SessionService = (*SQLSessionService)(nil)
+
+// State key prefixes that control where a state value is stored.
+const (
+	AppStatePrefix  = "app:"
+	UserStatePrefix = "user:"
+	TempStatePrefix = "temp:"
+)
+
+// SQLDialect identifies the SQL flavor spoken by a database/sql driver.
+type SQLDialect string
+
+const (
+	DialectSQLite   SQLDialect = "sqlite"
+	DialectPostgres SQLDialect = "postgres"
+	DialectMySQL    SQLDialect = "mysql"
+)
+
+// SQLSessionService implements SessionService on top of database/sql.
+// Sessions, events and app/user-scoped state are stored in separate tables:
+// state keys prefixed with "app:" are shared by all users of an app, keys
+// prefixed with "user:" are shared by all sessions of a user, and keys prefixed
+// with "temp:" are never persisted.
+type SQLSessionService struct {
+	db       *sql.DB
+	dialect  SQLDialect
+	config   *SessionConfiguration
+	handlers []SessionEventHandler
+	ownsDB   bool
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSQLSessionService creates a session service using an already opened database.
+// Schema migrations are applied before the service is returned. The caller keeps
+// ownership of db; Close does not close it.
+func NewSQLSessionService(db *sql.DB, dialect SQLDialect, config *SessionConfiguration) (*SQLSessionService, error) {
+	if config == nil {
+		config = DefaultSessionConfiguration()
+	}
+
+	service := &SQLSessionService{
+		db:       db,
+		dialect:  dialect,
+		config:   config,
+		handlers: make([]SessionEventHandler, 0),
+		stop:     make(chan struct{}),
+	}
+
+	if err := service.migrate(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to migrate session schema: %w", err)
+	}
+
+	// Start auto-cleanup if configured
+	if config.AutoCleanupInterval > 0 {
+		go service.autoCleanupLoop()
+	}
+
+	return service, nil
+}
+
+// OpenSQLSessionService opens a database with the given driver and DSN and creates
+// a session service on it. The database is closed when the service is closed.
+func OpenSQLSessionService(driverName, dsn string, dialect SQLDialect, config *SessionConfiguration) (*SQLSessionService, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if dialect == DialectSQLite {
+		// SQLite allows a single writer; serializing access avoids "database is locked"
+		// errors and keeps in-memory databases alive on one connection.
+		db.SetMaxOpenConns(1)
+	}
+
+	service, err := NewSQLSessionService(db, dialect, config)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	service.ownsDB = true
+
+	return service, nil
+}
+
+// CreateSession creates a new session.
+func (s *SQLSessionService) CreateSession(ctx context.Context, req *core.CreateSessionRequest) (*core.Session, error) {
+	sessionID := generateSessionID()
+	if req.SessionID != nil && *req.SessionID != "" {
+		sessionID = *req.SessionID
+	}
+
+	if s.config.MaxSessionsPerUser > 0 {
+		var count int
+		err := s.queryRow(ctx, s.db, `SELECT COUNT(*) FROM adk_sessions WHERE app_name = ? AND user_id = ?`,
+			req.AppName, req.UserID).Scan(&count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count existing sessions: %w", err)
+		}
+		if count >= s.config.MaxSessionsPerUser {
+			return nil, fmt.Errorf("user has reached maximum sessions limit: %d", s.config.MaxSessionsPerUser)
+		}
+	}
+
+	now := time.Now()
+	var session *core.Session
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		exists, err := s.sessionExists(ctx, tx, req.AppName, req.UserID, sessionID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("session already exists: %s", sessionID)
+		}
+
+		appDelta, userDelta, sessionState := splitStateDelta(req.State)
+		appState, userState, err := s.applyScopedDeltas(ctx, tx, req.AppName, req.UserID, appDelta, userDelta, now)
+		if err != nil {
+			return err
+		}
+
+		stateData, err := marshalState(sessionState)
+		if err != nil {
+			return err
+		}
+		if _, err := s.exec(ctx, tx,
+			`INSERT INTO adk_sessions (app_name, user_id, id, state_data, create_time, update_time) VALUES (?, ?, ?, ?, ?, ?)`,
+			req.AppName, req.UserID, sessionID, stateData, now.UnixNano(), now.UnixNano()); err != nil {
+			return fmt.Errorf("failed to insert session: %w", err)
+		}
+
+		session = &core.Session{
+			ID:             sessionID,
+			AppName:        req.AppName,
+			UserID:         req.UserID,
+			State:          mergeScopedState(sessionState, appState, userState),
+			Events:         make([]*core.Event, 0),
+			LastUpdateTime: now,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, handler := range s.handlers {
+		if err := handler.OnSessionCreated(ctx, session); err != nil {
+			fmt.Printf("Session created handler error: %v\n", err)
+		}
+	}
+
+	return session, nil
+}
+
+// GetSession retrieves a session by ID. It returns (nil, nil) if the session does not exist.
+func (s *SQLSessionService) GetSession(ctx context.Context, req *core.GetSessionRequest) (*core.Session, error) {
+	session, err := s.loadSession(ctx, s.db, req.AppName, req.UserID, req.SessionID)
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	includeEvents := true
+	var maxEvents *int
+	if req.Config != nil {
+		includeEvents = req.Config.IncludeEvents
+		maxEvents = req.Config.MaxEvents
+	}
+
+	if includeEvents {
+		events, err := s.loadEvents(ctx, req.AppName, req.UserID, req.SessionID, maxEvents)
+		if err != nil {
+			return nil, err
+		}
+		session.Events = events
+	} else {
+		session.Events = nil
+	}
+
+	return session, nil
+}
+
+// AppendEvent adds an event to a session and applies its state delta.
+// Partial events are not persisted.
+func (s *SQLSessionService) AppendEvent(ctx context.Context, session *core.Session, event *core.Event) error {
+	if event.Partial != nil && *event.Partial {
+		return nil
+	}
+
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	now := time.Now()
+	var oldState, newState map[string]any
+
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		var stateData string
+		err := s.queryRow(ctx, tx, `SELECT state_data FROM adk_sessions WHERE app_name = ? AND user_id = ? AND id = ?`,
+			session.AppName, session.UserID, session.ID).Scan(&stateData)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("session not found: %s", session.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load session: %w", err)
+		}
+
+		sessionState, err := unmarshalState(stateData)
+		if err != nil {
+			return err
+		}
+		oldState = copyMap(sessionState)
+
+		var nextSeq int64
+		if err := s.queryRow(ctx, tx,
+			`SELECT COALESCE(MAX(seq), -1) + 1 FROM adk_events WHERE app_name = ? AND user_id = ? AND session_id = ?`,
+			session.AppName, session.UserID, session.ID).Scan(&nextSeq); err != nil {
+			return fmt.Errorf("failed to determine event sequence: %w", err)
+		}
+
+		hasError := 0
+		if event.ErrorMessage != nil || event.ErrorCode != nil {
+			hasError = 1
+		}
+		if _, err := s.exec(ctx, tx,
+			`INSERT INTO adk_events (app_name, user_id, session_id, seq, id, invocation_id, author, event_time, has_error, event_data) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			session.AppName, session.UserID, session.ID, nextSeq, event.ID, event.InvocationID, event.Author,
+			event.Timestamp.UnixNano(), hasError, string(eventData)); err != nil {
+			return fmt.Errorf("failed to insert event: %w", err)
+		}
+
+		// Drop the oldest events beyond the configured limit
+		if s.config.MaxEventsPerSession > 0 && nextSeq >= int64(s.config.MaxEventsPerSession) {
+			if _, err := s.exec(ctx, tx,
+				`DELETE FROM adk_events WHERE app_name = ? AND user_id = ? AND session_id = ? AND seq <= ?`,
+				session.AppName, session.UserID, session.ID, nextSeq-int64(s.config.MaxEventsPerSession)); err != nil {
+				return fmt.Errorf("failed to trim events: %w", err)
+			}
+		}
+
+		appDelta, userDelta, sessionDelta := splitStateDelta(event.Actions.StateDelta)
+		appState, userState, err := s.applyScopedDeltas(ctx, tx, session.AppName, session.UserID, appDelta, userDelta, now)
+		if err != nil {
+			return err
+		}
+		for k, v := range sessionDelta {
+			sessionState[k] = v
+		}
+
+		updatedData, err := marshalState(sessionState)
+		if err != nil {
+			return err
+		}
+		if _, err := s.exec(ctx, tx,
+			`UPDATE adk_sessions SET state_data = ?, update_time = ? WHERE app_name = ? AND user_id = ? AND id = ?`,
+			updatedData, now.UnixNano(), session.AppName, session.UserID, session.ID); err != nil {
+			return fmt.Errorf("failed to update session: %w", err)
+		}
+
+		newState = mergeScopedState(sessionState, appState, userState)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Update the passed session object
+	session.Events = append(session.Events, event)
+	session.State = newState
+	session.LastUpdateTime = now
+
+	for _, handler := range s.handlers {
+		if err := handler.OnEventAdded(ctx, session, event); err != nil {
+			fmt.Printf("Event added handler error: %v\n", err)
+		}
+		if len(event.Actions.StateDelta) > 0 {
+			if err := handler.OnSessionUpdated(ctx, session, oldState); err != nil {
+				fmt.Printf("Session updated handler error: %v\n", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteSession removes a session and its events.
+func (s *SQLSessionService) DeleteSession(ctx context.Context, req *core.DeleteSessionRequest) error {
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		return s.deleteSession(ctx, tx, req.AppName, req.UserID, req.SessionID)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, handler := range s.handlers {
+		if err := handler.OnSessionDeleted(ctx, req.AppName, req.UserID, req.SessionID); err != nil {
+			fmt.Printf("Session deleted handler error: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// ListSessions returns sessions for a user without their events.
+func (s *SQLSessionService) ListSessions(ctx context.Context, req *core.ListSessionsRequest) (*core.ListSessionsResponse, error) {
+	var totalCount int
+	if err := s.queryRow(ctx, s.db, `SELECT COUNT(*) FROM adk_sessions WHERE app_name = ? AND user_id = ?`,
+		req.AppName, req.UserID).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("failed to count sessions: %w", err)
+	}
+
+	query := `SELECT id, state_data, update_time FROM adk_sessions WHERE app_name = ? AND user_id = ? ORDER BY create_time, id`
+	args := []any{req.AppName, req.UserID}
+
+	offset := 0
+	if req.Offset != nil {
+		offset = *req.Offset
+	}
+	if req.Limit != nil || offset > 0 {
+		limit := totalCount
+		if req.Limit != nil {
+			limit = *req.Limit
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, offset)
+	}
+
+	sessions, err := s.querySessions(ctx, req.AppName, req.UserID, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.ListSessionsResponse{
+		Sessions:   sessions,
+		TotalCount: totalCount,
+		HasMore:    offset+len(sessions) < totalCount,
+	}, nil
+}
+
+// GetSessionsByUser returns all sessions for a specific user.
+func (s *SQLSessionService) GetSessionsByUser(ctx context.Context, appName, userID string) ([]*core.Session, error) {
+	response, err := s.ListSessions(ctx, &core.ListSessionsRequest{
+		AppName: appName,
+		UserID:  userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Sessions, nil
+}
+
+// UpdateSessionState replaces the state of an existing session. Scoped keys are
+// written to the app and user state tables.
+func (s *SQLSessionService) UpdateSessionState(ctx context.Context, appName, userID, sessionID string, state map[string]any) error {
+	now := time.Now()
+	var session *core.Session
+	var oldState map[string]any
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		current, err := s.loadSession(ctx, tx, appName, userID, sessionID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return fmt.Errorf("session not found: %s", sessionID)
+		}
+		oldState = current.State
+
+		appDelta, userDelta, sessionState := splitStateDelta(state)
+		appState, userState, err := s.applyScopedDeltas(ctx, tx, appName, userID, appDelta, userDelta, now)
+		if err != nil {
+			return err
+		}
+
+		stateData, err := marshalState(sessionState)
+		if err != nil {
+			return err
+		}
+		if _, err := s.exec(ctx, tx,
+			`UPDATE adk_sessions SET state_data = ?, update_time = ? WHERE app_name = ? AND user_id = ? AND id = ?`,
+			stateData, now.UnixNano(), appName, userID, sessionID); err != nil {
+			return fmt.Errorf("failed to update session state: %w", err)
+		}
+
+		current.State = mergeScopedState(sessionState, appState, userState)
+		current.LastUpdateTime = now
+		session = current
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, handler := range s.handlers {
+		if err := handler.OnSessionUpdated(ctx, session, oldState); err != nil {
+			fmt.Printf("Session updated handler error: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// GetSessionState retrieves only the state of a session, including app and user state.
+func (s *SQLSessionService) GetSessionState(ctx context.Context, appName, userID, sessionID string) (map[string]any, error) {
+	session, err := s.loadSession(ctx, s.db, appName, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	return session.State, nil
+}
+
+// ClearSessionEvents removes all events from a session while keeping the session and state.
+func (s *SQLSessionService) ClearSessionEvents(ctx context.Context, appName, userID, sessionID string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		exists, err := s.sessionExists(ctx, tx, appName, userID, sessionID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("session not found: %s", sessionID)
+		}
+
+		if _, err := s.exec(ctx, tx, `DELETE FROM adk_events WHERE app_name = ? AND user_id = ? AND session_id = ?`,
+			appName, userID, sessionID); err != nil {
+			return fmt.Errorf("failed to clear events: %w", err)
+		}
+		if _, err := s.exec(ctx, tx, `UPDATE adk_sessions SET update_time = ? WHERE app_name = ? AND user_id = ? AND id = ?`,
+			time.Now().UnixNano(), appName, userID, sessionID); err != nil {
+			return fmt.Errorf("failed to update session: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetSessionsModifiedAfter returns sessions modified after the specified time.
+func (s *SQLSessionService) GetSessionsModifiedAfter(ctx context.Context, appName, userID string, after time.Time) ([]*core.Session, error) {
+	return s.querySessions(ctx, appName, userID,
+		`SELECT id, state_data, update_time FROM adk_sessions WHERE app_name = ? AND user_id = ? AND update_time > ? ORDER BY update_time`,
+		appName, userID, after.UnixNano())
+}
+
+// CleanupExpiredSessions removes sessions that haven't been updated within the specified duration.
+func (s *SQLSessionService) CleanupExpiredSessions(ctx context.Context, maxAge time.Duration) (int, error) {
+	cutoff := time.Now().Add(-maxAge).UnixNano()
+
+	type sessionKey struct{ appName, userID, id string }
+	var expired []sessionKey
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := s.query(ctx, tx, `SELECT app_name, user_id, id FROM adk_sessions WHERE update_time < ?`, cutoff)
+		if err != nil {
+			return fmt.Errorf("failed to query expired sessions: %w", err)
+		}
+		for rows.Next() {
+			var key sessionKey
+			if err := rows.Scan(&key.appName, &key.userID, &key.id); err != nil {
+				rows.Close()
+				return err
+			}
+			expired = append(expired, key)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, key := range expired {
+			if err := s.deleteSession(ctx, tx, key.appName, key.userID, key.id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range expired {
+		for _, handler := range s.handlers {
+			if err := handler.OnSessionDeleted(ctx, key.appName, key.userID, key.id); err != nil {
+				fmt.Printf("Session deleted handler error: %v\n", err)
+			}
+		}
+	}
+
+	return len(expired), nil
+}
+
+// GetSessionMetadata returns lightweight metadata about a session without loading its events.
+func (s *SQLSessionService) GetSessionMetadata(ctx context.Context, appName, userID, sessionID string) (*SessionMetadata, error) {
+	session, err := s.loadSession(ctx, s.db, appName, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	var eventCount, errorCount int
+	if err := s.queryRow(ctx, s.db,
+		`SELECT COUNT(*), COALESCE(SUM(has_error), 0) FROM adk_events WHERE app_name = ? AND user_id = ? AND session_id = ?`,
+		appName, userID, sessionID).Scan(&eventCount, &errorCount); err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	stateKeys := make([]string, 0, len(session.State))
+	for key := range session.State {
+		stateKeys = append(stateKeys, key)
+	}
+
+	return &SessionMetadata{
+		ID:             session.ID,
+		AppName:        session.AppName,
+		UserID:         session.UserID,
+		EventCount:     eventCount,
+		LastUpdateTime: session.LastUpdateTime,
+		StateKeys:      stateKeys,
+		HasErrors:      errorCount > 0,
+	}, nil
+}
+
+// BulkDeleteSessions deletes multiple sessions in a single transaction.
+func (s *SQLSessionService) BulkDeleteSessions(ctx context.Context, appName, userID string, sessionIDs []string) error {
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		for _, sessionID := range sessionIDs {
+			if err := s.deleteSession(ctx, tx, appName, userID, sessionID); err != nil {
+				return fmt.Errorf("failed to delete session %s: %w", sessionID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sessionID := range sessionIDs {
+		for _, handler := range s.handlers {
+			if err := handler.OnSessionDeleted(ctx, appName, userID, sessionID); err != nil {
+				fmt.Printf("Session deleted handler error: %v\n", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close stops background cleanup and closes the database if it was opened by the service.
+func (s *SQLSessionService) Close(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	if s.ownsDB {
+		return s.db.Close()
+	}
+	return nil
+}
+
+// AddEventHandler adds a session event handler.
+func (s *SQLSessionService) AddEventHandler(handler SessionEventHandler) {
+	s.handlers = append(s.handlers, handler)
+}
+
+// Private helper methods
+
+func (s *SQLSessionService) autoCleanupLoop() {
+	ticker := time.NewTicker(s.config.AutoCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if s.config.SessionTTL > 0 {
+				deleted, err := s.CleanupExpiredSessions(context.Background(), s.config.SessionTTL)
+				if err != nil {
+					fmt.Printf("Auto cleanup error: %v\n", err)
+				} else if deleted > 0 {
+					fmt.Printf("Auto cleanup: deleted %d expired sessions\n", deleted)
+				}
+			}
+		}
+	}
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (s *SQLSessionService) exec(ctx context.Context, q queryer, query string, args ...any) (sql.Result, error) {
+	return q.ExecContext(ctx, s.rebind(query), args...)
+}
+
+func (s *SQLSessionService) query(ctx context.Context, q queryer, query string, args ...any) (*sql.Rows, error) {
+	return q.QueryContext(ctx, s.rebind(query), args...)
+}
+
+func (s *SQLSessionService) queryRow(ctx context.Context, q queryer, query string, args ...any) *sql.Row {
+	return q.QueryRowContext(ctx, s.rebind(query), args...)
+}
+
+// rebind rewrites "?" placeholders into the dialect's placeholder syntax.
+func (s *SQLSessionService) rebind(query string) string {
+	if s.dialect != DialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s *SQLSessionService) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLSessionService) sessionExists(ctx context.Context, q queryer, appName, userID, sessionID string) (bool, error) {
+	var count int
+	err := s.queryRow(ctx, q, `SELECT COUNT(*) FROM adk_sessions WHERE app_name = ? AND user_id = ? AND id = ?`,
+		appName, userID, sessionID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+	return count > 0, nil
+}
+
+// loadSession loads a session with merged app and user state but without events.
+func (s *SQLSessionService) loadSession(ctx context.Context, q queryer, appName, userID, sessionID string) (*core.Session, error) {
+	var stateData string
+	var updateTime int64
+	err := s.queryRow(ctx, q, `SELECT state_data, update_time FROM adk_sessions WHERE app_name = ? AND user_id = ? AND id = ?`,
+		appName, userID, sessionID).Scan(&stateData, &updateTime)
+	if err == sql.ErrNoRows {
+		return nil, nil // Return nil, not an error, when session doesn't exist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	sessionState, err := unmarshalState(stateData)
+	if err != nil {
+		return nil, err
+	}
+	appState, err := s.loadScopedState(ctx, q, `SELECT state_data FROM adk_app_states WHERE app_name = ?`, appName)
+	if err != nil {
+		return nil, err
+	}
+	userState, err := s.loadScopedState(ctx, q, `SELECT state_data FROM adk_user_states WHERE app_name = ? AND user_id = ?`, appName, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.Session{
+		ID:             sessionID,
+		AppName:        appName,
+		UserID:         userID,
+		State:          mergeScopedState(sessionState, appState, userState),
+		LastUpdateTime: time.Unix(0, updateTime),
+	}, nil
+}
+
+// querySessions runs a query returning (id, state_data, update_time) rows for one user.
+func (s *SQLSessionService) querySessions(ctx context.Context, appName, userID, query string, args ...any) ([]*core.Session, error) {
+	appState, err := s.loadScopedState(ctx, s.db, `SELECT state_data FROM adk_app_states WHERE app_name = ?`, appName)
+	if err != nil {
+		return nil, err
+	}
+	userState, err := s.loadScopedState(ctx, s.db, `SELECT state_data FROM adk_user_states WHERE app_name = ? AND user_id = ?`, appName, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.query(ctx, s.db, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]*core.Session, 0)
+	for rows.Next() {
+		var id, stateData string
+		var updateTime int64
+		if err := rows.Scan(&id, &stateData, &updateTime); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+
+		sessionState, err := unmarshalState(stateData)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, &core.Session{
+			ID:             id,
+			AppName:        appName,
+			UserID:         userID,
+			State:          mergeScopedState(sessionState, appState, userState),
+			Events:         nil, // Don't include events in list
+			LastUpdateTime: time.Unix(0, updateTime),
+		})
+	}
+
+	return sessions, rows.Err()
+}
+
+// loadEvents returns the events of a session in order, optionally only the last maxEvents.
+func (s *SQLSessionService) loadEvents(ctx context.Context, appName, userID, sessionID string, maxEvents *int) ([]*core.Event, error) {
+	query := `SELECT event_data FROM adk_events WHERE app_name = ? AND user_id = ? AND session_id = ? ORDER BY seq`
+	args := []any{appName, userID, sessionID}
+	if maxEvents != nil {
+		query = `SELECT event_data FROM adk_events WHERE app_name = ? AND user_id = ? AND session_id = ? ORDER BY seq DESC LIMIT ?`
+		args = append(args, *maxEvents)
+	}
+
+	rows, err := s.query(ctx, s.db, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*core.Event, 0)
+	for rows.Next() {
+		var eventData string
+		if err := rows.Scan(&eventData); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		var event core.Event
+		if err := json.Unmarshal([]byte(eventData), &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if maxEvents != nil {
+		// Restore chronological order
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+
+	return events, nil
+}
+
+func (s *SQLSessionService) deleteSession(ctx context.Context, tx *sql.Tx, appName, userID, sessionID string) error {
+	if _, err := s.exec(ctx, tx, `DELETE FROM adk_events WHERE app_name = ? AND user_id = ? AND session_id = ?`,
+		appName, userID, sessionID); err != nil {
+		return fmt.Errorf("failed to delete events: %w", err)
+	}
+	if _, err := s.exec(ctx, tx, `DELETE FROM adk_sessions WHERE app_name = ? AND user_id = ? AND id = ?`,
+		appName, userID, sessionID); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLSessionService) loadScopedState(ctx context.Context, q queryer, query string, args ...any) (map[string]any, error) {
+	var stateData string
+	err := s.queryRow(ctx, q, query, args...).Scan(&stateData)
+	if err == sql.ErrNoRows {
+		return map[string]any{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	return unmarshalState(stateData)
+}
+
+// applyScopedDeltas merges app and user state deltas into their tables and returns
+// the resulting app and user state.
+func (s *SQLSessionService) applyScopedDeltas(ctx context.Context, tx *sql.Tx, appName, userID string,
+	appDelta, userDelta map[string]any, now time.Time) (map[string]any, map[string]any, error) {
+
+	appState, err := s.loadScopedState(ctx, tx, `SELECT state_data FROM adk_app_states WHERE app_name = ?`, appName)
+	if err != nil {
+		return nil, nil, err
+	}
+	userState, err := s.loadScopedState(ctx, tx, `SELECT state_data FROM adk_user_states WHERE app_name = ? AND user_id = ?`, appName, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(appDelta) > 0 {
+		for k, v := range appDelta {
+			appState[k] = v
+		}
+		if err := s.upsertState(ctx, tx, appState, now,
+			`UPDATE adk_app_states SET state_data = ?, update_time = ? WHERE app_name = ?`,
+			`INSERT INTO adk_app_states (state_data, update_time, app_name) VALUES (?, ?, ?)`,
+			appName); err != nil {
+			return nil, nil, fmt.Errorf("failed to save app state: %w", err)
+		}
+	}
+
+	if len(userDelta) > 0 {
+		for k, v := range userDelta {
+			userState[k] = v
+		}
+		if err := s.upsertState(ctx, tx, userState, now,
+			`UPDATE adk_user_states SET state_data = ?, update_time = ? WHERE app_name = ? AND user_id = ?`,
+			`INSERT INTO adk_user_states (state_data, update_time, app_name, user_id) VALUES (?, ?, ?, ?)`,
+			appName, userID); err != nil {
+			return nil, nil, fmt.Errorf("failed to save user state: %w", err)
+		}
+	}
+
+	return appState, userState, nil
+}
+
+// upsertState updates a state row, inserting it if it does not exist yet. Both
+// statements take (state_data, update_time, keys...) so no dialect-specific
+// upsert syntax is needed.
+func (s *SQLSessionService) upsertState(ctx context.Context, tx *sql.Tx, state map[string]any, now time.Time,
+	updateQuery, insertQuery string, keys ...any) error {
+
+	stateData, err := marshalState(state)
+	if err != nil {
+		return err
+	}
+
+	args := append([]any{stateData, now.UnixNano()}, keys...)
+	result, err := s.exec(ctx, tx, updateQuery, args...)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+		return nil
+	}
+
+	_, err = s.exec(ctx, tx, insertQuery, args...)
+	return err
+}
+
+// splitStateDelta separates a state delta into app-scoped, user-scoped and session-scoped
+// parts. Prefixes are stripped from scoped keys and temporary keys are dropped.
+func splitStateDelta(delta map[string]any) (appDelta, userDelta, sessionDelta map[string]any) {
+	appDelta = make(map[string]any)
+	userDelta = make(map[string]any)
+	sessionDelta = make(map[string]any)
+
+	for key, value := range delta {
+		switch {
+		case strings.HasPrefix(key, AppStatePrefix):
+			appDelta[strings.TrimPrefix(key, AppStatePrefix)] = value
+		case strings.HasPrefix(key, UserStatePrefix):
+			userDelta[strings.TrimPrefix(key, UserStatePrefix)] = value
+		case strings.HasPrefix(key, TempStatePrefix):
+			// Temporary state is never persisted
+		default:
+			sessionDelta[key] = value
+		}
+	}
+	return appDelta, userDelta, sessionDelta
+}
+
+// mergeScopedState builds the state visible to a session: its own keys plus
+// app and user state under their prefixes.
+func mergeScopedState(sessionState, appState, userState map[string]any) map[string]any {
+	merged := make(map[string]any, len(sessionState)+len(appState)+len(userState))
+	for k, v := range sessionState {
+		merged[k] = v
+	}
+	for k, v := range appState {
+		merged[AppStatePrefix+k] = v
+	}
+	for k, v := range userState {
+		merged[UserStatePrefix+k] = v
+	}
+	return merged
+}
+
+func marshalState(state map[string]any) (string, error) {
+	if state == nil {
+		state = map[string]any{}
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return string(data), nil
+}
+
+func unmarshalState(data string) (map[string]any, error) {
+	state := make(map[string]any)
+	if data == "" {
+		return state, nil
+	}
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal state: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,116 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// sqlMigration is a schema change identified by a monotonically increasing version.
+type sqlMigration struct {
+	version    int
+	statements func(d SQLDialect) []string
+}
+
+// sqlMigrations lists every schema version in order. Never edit a released
+// migration; append a new one instead.
+var sqlMigrations = []sqlMigration{
+	{
+		version: 1,
+		statements: func(d SQLDialect) []string {
+			text := d.textType()
+			return []string{
+				`CREATE TABLE IF NOT EXISTS adk_sessions (
+					app_name VARCHAR(128) NOT NULL,
+					user_id VARCHAR(128) NOT NULL,
+					id VARCHAR(128) NOT NULL,
+					state_data ` + text + ` NOT NULL,
+					create_time BIGINT NOT NULL,
+					update_time BIGINT NOT NULL,
+					PRIMARY KEY (app_name, user_id, id)
+				)`,
+				`CREATE TABLE IF NOT EXISTS adk_events (
+					app_name VARCHAR(128) NOT NULL,
+					user_id VARCHAR(128) NOT NULL,
+					session_id VARCHAR(128) NOT NULL,
+					seq BIGINT NOT NULL,
+					id VARCHAR(128) NOT NULL,
+					invocation_id VARCHAR(128) NOT NULL,
+					author VARCHAR(128) NOT NULL,
+					event_time BIGINT NOT NULL,
+					has_error INTEGER NOT NULL DEFAULT 0,
+					event_data ` + text + ` NOT NULL,
+					PRIMARY KEY (app_name, user_id, session_id, seq)
+				)`,
+				`CREATE TABLE IF NOT EXISTS adk_app_states (
+					app_name VARCHAR(128) NOT NULL,
+					state_data ` + text + ` NOT NULL,
+					update_time BIGINT NOT NULL,
+					PRIMARY KEY (app_name)
+				)`,
+				`CREATE TABLE IF NOT EXISTS adk_user_states (
+					app_name VARCHAR(128) NOT NULL,
+					user_id VARCHAR(128) NOT NULL,
+					state_data ` + text + ` NOT NULL,
+					update_time BIGINT NOT NULL,
+					PRIMARY KEY (app_name, user_id)
+				)`,
+				`CREATE INDEX idx_adk_sessions_update_time ON adk_sessions (update_time)`,
+			}
+		},
+	},
+}
+
+// textType returns the column type used for JSON payloads.
+func (d SQLDialect) textType() string {
+	if d == DialectMySQL {
+		return "LONGTEXT"
+	}
+	return "TEXT"
+}
+
+// migrate brings the schema up to the latest version. Each migration runs in its
+// own transaction together with the version bookkeeping.
+func (s *SQLSessionService) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS adk_schema_migrations (
+		version INTEGER NOT NULL PRIMARY KEY,
+		applied_at BIGINT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	var current int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM adk_schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for _, migration := range sqlMigrations {
+		if migration.version <= current {
+			continue
+		}
+
+		err := s.withTx(ctx, func(tx *sql.Tx) error {
+			for _, statement := range migration.statements(s.dialect) {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					return err
+				}
+			}
+			_, err := s.exec(ctx, tx, `INSERT INTO adk_schema_migrations (version, applied_at) VALUES (?, ?)`,
+				migration.version, time.Now().UnixNano())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", migration.version, err)
+		}
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the currently applied schema version.
+func (s *SQLSessionService) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM adk_schema_migrations`).Scan(&version)
+	return version, err
+}
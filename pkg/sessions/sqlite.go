@@ -0,0 +1,5 @@
+package sessions
+
+// Register the pure Go SQLite driver under the name "sqlite" so that
+// sqlite:// session service URIs work without cgo.
+import _ "modernc.org/sqlite"
@@ -40,6 +40,7 @@ type ParameterSchema struct {
 	Properties  map[string]*ParameterSchema `json:"properties,omitempty"` // For object types
 	Required    []string                    `json:"required,omitempty"`   // For object types
 	Optional    bool                        `json:"optional"`
+	Nullable    bool                        `json:"nullable,omitempty"` // Also accepts null
 }
 
 // FunctionMetadata provides metadata about the wrapped function.
@@ -514,6 +515,9 @@ func parameterSchemaToMap(schema *ParameterSchema) map[string]interface{} {
 	result := make(map[string]interface{})
 
 	result["type"] = schema.Type
+	if schema.Nullable {
+		result["type"] = []any{schema.Type, "null"}
+	}
 
 	if schema.Description != "" {
 		result["description"] = schema.Description
@@ -0,0 +1,206 @@
+// Package tools provides tools that give agents access to long-term memory.
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+)
+
+// DefaultMemoryLimit is the number of memories returned when no limit is configured.
+const DefaultMemoryLimit = 5
+
+// MemoryEntry is a past event returned to the model.
+type MemoryEntry struct {
+	Author    string    `json:"author"`
+	Timestamp time.Time `json:"timestamp"`
+	Text      string    `json:"text"`
+}
+
+// LoadMemoryResponse is the result of the load_memory tool.
+type LoadMemoryResponse struct {
+	Memories []MemoryEntry `json:"memories"`
+}
+
+// LoadMemoryTool lets the model search the user's past conversations.
+type LoadMemoryTool struct {
+	*BaseToolImpl
+	limit int
+}
+
+// NewLoadMemoryTool creates a new load_memory tool.
+func NewLoadMemoryTool() *LoadMemoryTool {
+	return &LoadMemoryTool{
+		BaseToolImpl: NewBaseTool("load_memory", "Loads relevant memories from past conversations with the user"),
+		limit:        DefaultMemoryLimit,
+	}
+}
+
+// SetLimit sets the maximum number of memories returned per call.
+func (t *LoadMemoryTool) SetLimit(limit int) {
+	t.limit = limit
+}
+
+// GetDeclaration returns the function declaration for this tool.
+func (t *LoadMemoryTool) GetDeclaration() *core.FunctionDeclaration {
+	return &core.FunctionDeclaration{
+		Name:        t.Name(),
+		Description: t.Description(),
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "What to look for in past conversations",
+				},
+			},
+			"required": []string{"query"},
+		},
+	}
+}
+
+// RunAsync searches memory for the given query.
+func (t *LoadMemoryTool) RunAsync(toolCtx *core.ToolContext, args map[string]any) (any, error) {
+	query, ok := args["query"].(string)
+	if !ok || query == "" {
+		return nil, fmt.Errorf("query parameter is required and must be a string")
+	}
+
+	events, err := searchPastEvents(toolCtx, query, t.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LoadMemoryResponse{Memories: make([]MemoryEntry, 0, len(events))}
+	for _, event := range events {
+		response.Memories = append(response.Memories, MemoryEntry{
+			Author:    event.Author,
+			Timestamp: event.Timestamp,
+			Text:      contentText(event.Content),
+		})
+	}
+	return response, nil
+}
+
+// ProcessLLMRequest tells the model that memory is available.
+func (t *LoadMemoryTool) ProcessLLMRequest(toolCtx *core.ToolContext, request *core.LLMRequest) error {
+	appendInstruction(request, "You have memory. You can use it to answer questions. "+
+		"If any questions need you to look up the memory, call the load_memory function with a query.")
+	return nil
+}
+
+// PreloadMemoryTool injects the memories most relevant to the user's message
+// into every LLM request. It is never called by the model.
+type PreloadMemoryTool struct {
+	*BaseToolImpl
+	topK int
+}
+
+// NewPreloadMemoryTool creates a tool that preloads up to topK memories before
+// each turn. A non-positive topK uses DefaultMemoryLimit.
+func NewPreloadMemoryTool(topK int) *PreloadMemoryTool {
+	if topK <= 0 {
+		topK = DefaultMemoryLimit
+	}
+	return &PreloadMemoryTool{
+		BaseToolImpl: NewBaseTool("preload_memory", "Preloads relevant memories from past conversations with the user"),
+		topK:         topK,
+	}
+}
+
+// ProcessLLMRequest searches memory with the current user message and adds the
+// results to the system instruction.
+func (t *PreloadMemoryTool) ProcessLLMRequest(toolCtx *core.ToolContext, request *core.LLMRequest) error {
+	invocationCtx := toolCtx.InvocationContext
+	if invocationCtx == nil || invocationCtx.MemoryService == nil {
+		return nil
+	}
+
+	query := contentText(invocationCtx.UserContent)
+	if query == "" {
+		return nil
+	}
+
+	events, err := searchPastEvents(toolCtx, query, t.topK)
+	if err != nil {
+		return fmt.Errorf("failed to preload memory: %w", err)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("The following content is from your previous conversations with the user. ")
+	sb.WriteString("It may be useful for answering the user's current query.\n<PAST_CONVERSATIONS>\n")
+	for _, event := range events {
+		fmt.Fprintf(&sb, "Time: %s\n%s: %s\n", event.Timestamp.Format(time.RFC3339), event.Author, contentText(event.Content))
+	}
+	sb.WriteString("</PAST_CONVERSATIONS>")
+
+	appendInstruction(request, sb.String())
+	return nil
+}
+
+// searchPastEvents searches memory, skipping events of the current session
+// which are already part of the conversation.
+func searchPastEvents(toolCtx *core.ToolContext, query string, limit int) ([]*core.Event, error) {
+	session := toolCtx.InvocationContext.Session
+	current := make(map[string]bool, len(session.Events))
+	for _, event := range session.Events {
+		current[event.ID] = true
+	}
+
+	events, err := toolCtx.SearchMemory(query, limit+len(current))
+	if err != nil {
+		return nil, err
+	}
+
+	past := make([]*core.Event, 0, limit)
+	for _, event := range events {
+		if current[event.ID] {
+			continue
+		}
+		past = append(past, event)
+		if len(past) == limit {
+			break
+		}
+	}
+	return past, nil
+}
+
+// appendInstruction adds text to the request's system instruction.
+func appendInstruction(request *core.LLMRequest, text string) {
+	if len(request.Contents) > 0 && request.Contents[0].Role == "system" {
+		request.Contents[0].Parts = append(request.Contents[0].Parts, core.Part{Type: "text", Text: &text})
+	} else {
+		system := core.Content{Role: "system", Parts: []core.Part{{Type: "text", Text: &text}}}
+		request.Contents = append([]core.Content{system}, request.Contents...)
+	}
+
+	if request.Config == nil {
+		request.Config = &core.LLMConfig{}
+	}
+	// Replace rather than modify the pointee, which may be shared with the agent
+	instruction := text
+	if request.Config.SystemInstruction != nil && *request.Config.SystemInstruction != "" {
+		instruction = *request.Config.SystemInstruction + "\n\n" + text
+	}
+	request.Config.SystemInstruction = &instruction
+}
+
+// contentText joins the text parts of a content.
+func contentText(content *core.Content) string {
+	if content == nil {
+		return ""
+	}
+
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != nil && *part.Text != "" {
+			texts = append(texts, *part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agent-protocol/adk-golang/pkg/core"
+	"github.com/agent-protocol/adk-golang/pkg/memory"
+	"github.com/agent-protocol/adk-golang/pkg/ptr"
+)
+
+func textEvent(author, text string) *core.Event {
+	event := core.NewEvent("inv", author)
+	event.Content = &core.Content{Role: author, Parts: []core.Part{{Type: "text", Text: ptr.Ptr(text)}}}
+	return event
+}
+
+// newMemoryToolContext returns a tool context for a new session whose memory
+// already holds one past session of the same user.
+func newMemoryToolContext(t *testing.T, userMessage string) *core.ToolContext {
+	t.Helper()
+	ctx := context.Background()
+	memoryService := memory.NewInMemoryMemoryService()
+
+	past := &core.Session{ID: "past", AppName: "app", UserID: "alice", Events: []*core.Event{
+		textEvent("user", "Please always answer me in French"),
+		textEvent("assistant", "D'accord, je répondrai en français."),
+	}}
+	if err := memoryService.AddSessionToMemory(ctx, past); err != nil {
+		t.Fatalf("AddSessionToMemory failed: %v", err)
+	}
+
+	current := &core.Session{ID: "current", AppName: "app", UserID: "alice", Events: []*core.Event{
+		textEvent("user", userMessage),
+	}}
+	if err := memoryService.AddSessionToMemory(ctx, current); err != nil {
+		t.Fatalf("AddSessionToMemory failed: %v", err)
+	}
+
+	invocationCtx := core.NewInvocationContext(ctx, "inv", nil, current, nil)
+	invocationCtx.MemoryService = memoryService
+	invocationCtx.UserContent = current.Events[0].Content
+	return core.NewToolContext(invocationCtx)
+}
+
+func TestLoadMemoryTool(t *testing.T) {
+	tool := NewLoadMemoryTool()
+	toolCtx := newMemoryToolContext(t, "Which language should you answer in?")
+
+	if decl := tool.GetDeclaration(); decl == nil || decl.Name != "load_memory" {
+		t.Fatalf("Unexpected declaration: %+v", decl)
+	}
+
+	result, err := tool.RunAsync(toolCtx, map[string]any{"query": "answer language French"})
+	if err != nil {
+		t.Fatalf("RunAsync failed: %v", err)
+	}
+	response := result.(*LoadMemoryResponse)
+	if len(response.Memories) == 0 || !strings.Contains(response.Memories[0].Text, "French") {
+		t.Errorf("Expected French preference to be recalled, got %+v", response.Memories)
+	}
+	for _, entry := range response.Memories {
+		if strings.Contains(entry.Text, "Which language") {
+			t.Errorf("Current session events should not be returned: %+v", entry)
+		}
+	}
+
+	if _, err := tool.RunAsync(toolCtx, map[string]any{}); err == nil {
+		t.Error("Expected error for missing query")
+	}
+
+	request := &core.LLMRequest{}
+	if err := tool.ProcessLLMRequest(toolCtx, request); err != nil {
+		t.Fatalf("ProcessLLMRequest failed: %v", err)
+	}
+	if request.Config == nil || !strings.Contains(*request.Config.SystemInstruction, "load_memory") {
+		t.Error("Expected memory instruction to be added")
+	}
+}
+
+func TestPreloadMemoryTool(t *testing.T) {
+	tool := NewPreloadMemoryTool(2)
+	toolCtx := newMemoryToolContext(t, "Can you answer in French today?")
+
+	if tool.GetDeclaration() != nil {
+		t.Error("Preload tool should not be exposed to the model")
+	}
+
+	instruction := "You are a helpful assistant."
+	request := &core.LLMRequest{
+		Contents: []core.Content{
+			{Role: "system", Parts: []core.Part{{Type: "text", Text: &instruction}}},
+			*toolCtx.InvocationContext.UserContent,
+		},
+		Config: &core.LLMConfig{SystemInstruction: &instruction},
+	}
+	if err := tool.ProcessLLMRequest(toolCtx, request); err != nil {
+		t.Fatalf("ProcessLLMRequest failed: %v", err)
+	}
+
+	if instruction != "You are a helpful assistant." {
+		t.Errorf("Agent instruction must not be modified, got %q", instruction)
+	}
+	system := *request.Config.SystemInstruction
+	if !strings.Contains(system, "<PAST_CONVERSATIONS>") || !strings.Contains(system, "always answer me in French") {
+		t.Errorf("Expected past conversation in system instruction, got %q", system)
+	}
+	if strings.Contains(system, "today?") {
+		t.Errorf("Current message should not be preloaded, got %q", system)
+	}
+	if len(request.Contents) != 2 || len(request.Contents[0].Parts) != 2 {
+		t.Errorf("Expected memories appended to the system content, got %+v", request.Contents)
+	}
+}
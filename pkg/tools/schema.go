@@ -0,0 +1,234 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaFor derives a JSON schema from the type of v, usually a struct value
+// or a pointer to one. Struct fields are named after their json tags and
+// described by their description tags; fields that are pointers or tagged
+// omitempty are optional, all others are required. Pointer fields also accept
+// null, which encoding/json writes for nil pointers.
+func SchemaFor(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, fmt.Errorf("cannot derive a schema from nil")
+	}
+	schema, err := schemaForType(reflect.TypeOf(v), make(map[reflect.Type]bool))
+	if err != nil {
+		return nil, err
+	}
+	return parameterSchemaToMap(schema), nil
+}
+
+// schemaForType builds the schema of a Go type. Recursive types are rejected
+// since JSON schemas without references cannot describe them.
+func schemaForType(t reflect.Type, seen map[reflect.Type]bool) (*ParameterSchema, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &ParameterSchema{Type: "string", Description: "RFC 3339 timestamp"}, nil
+	}
+
+	schema := &ParameterSchema{Type: mapGoTypeToJSONType(t)}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		items, err := schemaForType(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		schema.Items = items
+
+	case reflect.Struct:
+		if seen[t] {
+			return nil, fmt.Errorf("recursive type %s is not supported", t)
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		schema.Properties = make(map[string]*ParameterSchema)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, omitEmpty, skip := jsonFieldName(field)
+			if skip {
+				continue
+			}
+			fieldSchema, err := schemaForType(field.Type, seen)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			if description := field.Tag.Get("description"); description != "" {
+				fieldSchema.Description = description
+			}
+			schema.Properties[name] = fieldSchema
+			if field.Type.Kind() == reflect.Ptr {
+				fieldSchema.Nullable = true
+			} else if !omitEmpty {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+	return schema, nil
+}
+
+// jsonFieldName returns the JSON name of a struct field, whether it is
+// tagged omitempty and whether encoding/json skips it.
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+// ValidateJSONSchema checks a decoded JSON value against a schema. It
+// supports the keywords produced by SchemaFor: type, including lists of
+// types, properties, required, items and enum.
+func ValidateJSONSchema(value any, schema map[string]any) error {
+	return validateSchemaValue("$", value, schema)
+}
+
+func validateSchemaValue(path string, value any, schema map[string]any) error {
+	schemaTypes := schemaStrings(schema["type"])
+	if schemaType, ok := schema["type"].(string); ok {
+		schemaTypes = []string{schemaType}
+	}
+	if len(schemaTypes) > 0 {
+		if err := checkSchemaTypes(value, schemaTypes); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if value == nil {
+		return nil
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value %v is not one of %v", path, value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range schemaStrings(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propertySchema, _ := properties[name].(map[string]any)
+			propertyValue, ok := v[name]
+			if !ok || propertySchema == nil {
+				continue
+			}
+			if err := validateSchemaValue(path+"."+name, propertyValue, propertySchema); err != nil {
+				return err
+			}
+		}
+
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			return nil
+		}
+		for i, item := range v {
+			if err := validateSchemaValue(fmt.Sprintf("%s[%d]", path, i), item, items); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkSchemaTypes checks a decoded JSON value against a list of JSON schema
+// types, of which it must match one.
+func checkSchemaTypes(value any, schemaTypes []string) error {
+	var err error
+	for _, schemaType := range schemaTypes {
+		if err = checkSchemaType(value, schemaType); err == nil {
+			return nil
+		}
+	}
+	if len(schemaTypes) > 1 {
+		return fmt.Errorf("expected %s, got %T", strings.Join(schemaTypes, " or "), value)
+	}
+	return err
+}
+
+// checkSchemaType checks a decoded JSON value against a JSON schema type.
+func checkSchemaType(value any, schemaType string) error {
+	ok := false
+	switch schemaType {
+	case "object":
+		_, ok = value.(map[string]any)
+	case "array":
+		_, ok = value.([]any)
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "number":
+		_, ok = value.(float64)
+	case "integer":
+		number, isNumber := value.(float64)
+		ok = isNumber && number == math.Trunc(number)
+	case "null":
+		ok = value == nil
+	default:
+		return nil
+	}
+	if !ok {
+		return fmt.Errorf("expected %s, got %T", schemaType, value)
+	}
+	return nil
+}
+
+// schemaStrings reads a list of strings from a schema, which holds []string
+// when built in Go and []any when decoded from JSON.
+func schemaStrings(value any) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []any:
+		strs := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	}
+	return nil
+}
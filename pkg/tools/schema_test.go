@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type forecastDay struct {
+	Date    time.Time `json:"date"`
+	HighC   float64   `json:"high_c" description:"Daily high in Celsius"`
+	Summary string    `json:"summary,omitempty"`
+}
+
+type forecast struct {
+	City     string        `json:"city"`
+	Days     []forecastDay `json:"days"`
+	Alerts   *[]string     `json:"alerts"`
+	Internal string        `json:"-"`
+	Count    int
+}
+
+func TestSchemaFor(t *testing.T) {
+	schema, err := SchemaFor(&forecast{})
+	if err != nil {
+		t.Fatalf("SchemaFor failed: %v", err)
+	}
+
+	if schema["type"] != "object" {
+		t.Fatalf("Expected an object schema, got %v", schema["type"])
+	}
+	if !reflect.DeepEqual(schema["required"], []string{"city", "days", "Count"}) {
+		t.Errorf("Unexpected required properties: %v", schema["required"])
+	}
+	properties := schema["properties"].(map[string]interface{})
+	if _, ok := properties["Internal"]; ok {
+		t.Error("Expected fields tagged json:\"-\" to be skipped")
+	}
+	if !reflect.DeepEqual(properties["alerts"].(map[string]interface{})["type"], []any{"array", "null"}) {
+		t.Errorf("Expected pointer fields to accept null, got %v", properties["alerts"])
+	}
+	if properties["Count"].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("Unexpected Count schema: %v", properties["Count"])
+	}
+
+	days := properties["days"].(map[string]interface{})
+	day := days["items"].(map[string]interface{})
+	dayProperties := day["properties"].(map[string]interface{})
+	if dayProperties["date"].(map[string]interface{})["type"] != "string" {
+		t.Errorf("Expected time.Time as string, got %v", dayProperties["date"])
+	}
+	if dayProperties["high_c"].(map[string]interface{})["description"] != "Daily high in Celsius" {
+		t.Errorf("Expected the description tag, got %v", dayProperties["high_c"])
+	}
+	if !reflect.DeepEqual(day["required"], []string{"date", "high_c"}) {
+		t.Errorf("Expected omitempty fields to be optional, got %v", day["required"])
+	}
+
+	type node struct {
+		Children []node `json:"children"`
+	}
+	if _, err := SchemaFor(node{}); err == nil {
+		t.Error("Expected recursive types to be rejected")
+	}
+}
+
+func TestValidateJSONSchema(t *testing.T) {
+	schema, err := SchemaFor(forecast{})
+	if err != nil {
+		t.Fatalf("SchemaFor failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "valid", input: `{"city": "Melbourne", "Count": 1, "days": [{"date": "2024-01-01T00:00:00Z", "high_c": 24.5}]}`},
+		{name: "missing required", input: `{"city": "Melbourne", "days": []}`, wantErr: `$: missing required property "Count"`},
+		{name: "wrong type", input: `{"city": 3, "Count": 1, "days": []}`, wantErr: "$.city: expected string"},
+		{name: "nested", input: `{"city": "x", "Count": 1, "days": [{"date": "d"}]}`, wantErr: `$.days[0]: missing required property "high_c"`},
+		{name: "integer", input: `{"city": "x", "Count": 1.5, "days": []}`, wantErr: "$.Count: expected integer"},
+		{name: "null pointer", input: `{"city": "x", "Count": 1, "days": [], "alerts": null}`},
+		{name: "null required", input: `{"city": null, "Count": 1, "days": []}`, wantErr: "$.city: expected string"},
+		{name: "wrong pointer type", input: `{"city": "x", "Count": 1, "days": [], "alerts": "storm"}`, wantErr: "$.alerts: expected array or null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.input), &value); err != nil {
+				t.Fatal(err)
+			}
+			err := ValidateJSONSchema(value, schema)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateJSONSchema_MarshaledStruct(t *testing.T) {
+	type place struct {
+		City     string  `json:"city"`
+		Nickname *string `json:"nickname"`
+	}
+	schema, err := SchemaFor(place{})
+	if err != nil {
+		t.Fatalf("SchemaFor failed: %v", err)
+	}
+
+	data, err := json.Marshal(place{City: "Paris"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateJSONSchema(value, schema); err != nil {
+		t.Errorf("Expected %s to match its own schema, got %v", data, err)
+	}
+}